@@ -139,5 +139,11 @@ func NewAIChart(scope constructs.Construct, id string, props *AIChartProps) cdk8
 	//	},
 	//})
 	/************************** arkusnexus    ********************************/
+	/************************** detection     ********************************/
+	NewDetectionController(chart, "detection-controller", &DetectionControllerProps{
+		Namespace: namespace,
+		Image:     "aiarkusnexus/opensource-demo-be:latest",
+	})
+	/************************** detection     ********************************/
 	return chart
 }
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/arkusnexus/ai-demo/iac/imports/k8s"
+	"github.com/aws/constructs-go/constructs/v10"
+	"github.com/aws/jsii-runtime-go"
+)
+
+const (
+	detectionGroup = "detection.arkusnexus.com"
+	// detectionJobServiceAccount is what the Jobs the controller starts run
+	// as, it lets their pods report progress to the DetectionJob status.
+	detectionJobServiceAccount = "detection-job"
+)
+
+type DetectionControllerProps struct {
+	Namespace string
+	// Image is the server image, the controller runs it and starts Jobs
+	// with it.
+	Image string
+}
+
+// NewDetectionController adds the DetectionJob CRD and the controller that
+// runs every DetectionJob as a Kubernetes Job of the server in one-shot mode.
+// DetectionJobs in other namespaces need a detection-job service account
+// there bound to the detection-job cluster role.
+func NewDetectionController(scope constructs.Construct, id string, props *DetectionControllerProps) constructs.Construct {
+	construct := constructs.NewConstruct(scope, jsii.String(id))
+	namespace := props.Namespace
+
+	/************************** crd            ********************************/
+	k8s.NewKubeCustomResourceDefinition(construct, jsii.String("detectionjobs-crd"), &k8s.KubeCustomResourceDefinitionProps{
+		Metadata: &k8s.ObjectMeta{
+			Name: jsii.String(fmt.Sprintf("detectionjobs.%v", detectionGroup)),
+		},
+		Spec: &k8s.CustomResourceDefinitionSpec{
+			Group: jsii.String(detectionGroup),
+			Scope: jsii.String("Namespaced"),
+			Names: &k8s.CustomResourceDefinitionNames{
+				Kind:       jsii.String("DetectionJob"),
+				ListKind:   jsii.String("DetectionJobList"),
+				Plural:     jsii.String("detectionjobs"),
+				Singular:   jsii.String("detectionjob"),
+				ShortNames: jsii.Strings("dj"),
+			},
+			Versions: &[]*k8s.CustomResourceDefinitionVersion{{
+				Name:    jsii.String("v1alpha1"),
+				Served:  jsii.Bool(true),
+				Storage: jsii.Bool(true),
+				Subresources: &k8s.CustomResourceSubresources{
+					Status: map[string]interface{}{},
+				},
+				AdditionalPrinterColumns: &[]*k8s.CustomResourceColumnDefinition{
+					{Name: jsii.String("Phase"), Type: jsii.String("string"), JsonPath: jsii.String(".status.phase")},
+					{Name: jsii.String("Stage"), Type: jsii.String("string"), JsonPath: jsii.String(".status.stage")},
+					{Name: jsii.String("Progress"), Type: jsii.String("integer"), JsonPath: jsii.String(".status.progress")},
+					{Name: jsii.String("Age"), Type: jsii.String("date"), JsonPath: jsii.String(".metadata.creationTimestamp")},
+				},
+				Schema: &k8s.CustomResourceValidation{
+					OpenApiv3Schema: &k8s.JsonSchemaProps{
+						Type: jsii.String("object"),
+						Properties: &map[string]*k8s.JsonSchemaProps{
+							"apiVersion": schemaString("", nil),
+							"kind":       schemaString("", nil),
+							"metadata":   {Type: jsii.String("object")},
+							"spec":       detectionJobSpecSchema(),
+							"status":     detectionJobStatusSchema(),
+						},
+					},
+				},
+			}},
+		},
+	})
+	/************************** crd            ********************************/
+	/************************** rbac           ********************************/
+	controllerName := "detection-controller"
+	k8s.NewKubeServiceAccount(construct, jsii.String("controller-service-account"), &k8s.KubeServiceAccountProps{
+		Metadata: &k8s.ObjectMeta{Name: jsii.String(controllerName), Namespace: jsii.String(namespace)},
+	})
+	k8s.NewKubeClusterRole(construct, jsii.String("controller-role"), &k8s.KubeClusterRoleProps{
+		Metadata: &k8s.ObjectMeta{Name: jsii.String(controllerName)},
+		Rules: &[]*k8s.PolicyRule{
+			{
+				ApiGroups: jsii.Strings(detectionGroup),
+				Resources: jsii.Strings("detectionjobs"),
+				Verbs:     jsii.Strings("get", "list", "watch"),
+			},
+			{
+				ApiGroups: jsii.Strings(detectionGroup),
+				Resources: jsii.Strings("detectionjobs/status"),
+				Verbs:     jsii.Strings("get", "update", "patch"),
+			},
+			// owner references with blockOwnerDeletion on the Jobs
+			{
+				ApiGroups: jsii.Strings(detectionGroup),
+				Resources: jsii.Strings("detectionjobs/finalizers"),
+				Verbs:     jsii.Strings("update"),
+			},
+			{
+				ApiGroups: jsii.Strings("batch"),
+				Resources: jsii.Strings("jobs"),
+				Verbs:     jsii.Strings("get", "list", "watch", "create"),
+			},
+			// leader election
+			{
+				ApiGroups: jsii.Strings("coordination.k8s.io"),
+				Resources: jsii.Strings("leases"),
+				Verbs:     jsii.Strings("get", "list", "watch", "create", "update", "patch", "delete"),
+			},
+			{
+				ApiGroups: jsii.Strings(""),
+				Resources: jsii.Strings("events"),
+				Verbs:     jsii.Strings("create", "patch"),
+			},
+		},
+	})
+	k8s.NewKubeClusterRoleBinding(construct, jsii.String("controller-role-binding"), &k8s.KubeClusterRoleBindingProps{
+		Metadata: &k8s.ObjectMeta{Name: jsii.String(controllerName)},
+		RoleRef: &k8s.RoleRef{
+			ApiGroup: jsii.String("rbac.authorization.k8s.io"),
+			Kind:     jsii.String("ClusterRole"),
+			Name:     jsii.String(controllerName),
+		},
+		Subjects: &[]*k8s.Subject{{
+			Kind:      jsii.String("ServiceAccount"),
+			Name:      jsii.String(controllerName),
+			Namespace: jsii.String(namespace),
+		}},
+	})
+
+	k8s.NewKubeServiceAccount(construct, jsii.String("job-service-account"), &k8s.KubeServiceAccountProps{
+		Metadata: &k8s.ObjectMeta{Name: jsii.String(detectionJobServiceAccount), Namespace: jsii.String(namespace)},
+	})
+	k8s.NewKubeClusterRole(construct, jsii.String("job-role"), &k8s.KubeClusterRoleProps{
+		Metadata: &k8s.ObjectMeta{Name: jsii.String(detectionJobServiceAccount)},
+		Rules: &[]*k8s.PolicyRule{{
+			ApiGroups: jsii.Strings(detectionGroup),
+			Resources: jsii.Strings("detectionjobs/status"),
+			Verbs:     jsii.Strings("get", "patch"),
+		}},
+	})
+	k8s.NewKubeClusterRoleBinding(construct, jsii.String("job-role-binding"), &k8s.KubeClusterRoleBindingProps{
+		Metadata: &k8s.ObjectMeta{Name: jsii.String(detectionJobServiceAccount)},
+		RoleRef: &k8s.RoleRef{
+			ApiGroup: jsii.String("rbac.authorization.k8s.io"),
+			Kind:     jsii.String("ClusterRole"),
+			Name:     jsii.String(detectionJobServiceAccount),
+		},
+		Subjects: &[]*k8s.Subject{{
+			Kind:      jsii.String("ServiceAccount"),
+			Name:      jsii.String(detectionJobServiceAccount),
+			Namespace: jsii.String(namespace),
+		}},
+	})
+	/************************** rbac           ********************************/
+	/************************** controller     ********************************/
+	labels := map[string]*string{
+		"app": jsii.String(controllerName),
+	}
+	healthPort := float64(8082)
+	k8s.NewKubeDeployment(construct, jsii.String("controller-deployment"), &k8s.KubeDeploymentProps{
+		Metadata: &k8s.ObjectMeta{
+			Name:      jsii.String(controllerName),
+			Namespace: jsii.String(namespace),
+		},
+		Spec: &k8s.DeploymentSpec{
+			Replicas: jsii.Number(1),
+			Selector: &k8s.LabelSelector{
+				MatchLabels: &labels,
+			},
+			Template: &k8s.PodTemplateSpec{
+				Metadata: &k8s.ObjectMeta{
+					Labels: &labels,
+				},
+				Spec: &k8s.PodSpec{
+					ServiceAccountName: jsii.String(controllerName),
+					Containers: &[]*k8s.Container{{
+						Name:  jsii.String("controller"),
+						Image: jsii.String(props.Image),
+						Args:  jsii.Strings("controller"),
+						Env: &[]*k8s.EnvVar{
+							{Name: jsii.String("JOB_IMAGE"), Value: jsii.String(props.Image)},
+							{Name: jsii.String("JOB_SERVICE_ACCOUNT"), Value: jsii.String(detectionJobServiceAccount)},
+							{Name: jsii.String("LEADER_ELECT"), Value: jsii.String("true")},
+						},
+						LivenessProbe: &k8s.Probe{
+							HttpGet: &k8s.HttpGetAction{
+								Path: jsii.String("/healthz"),
+								Port: k8s.IntOrString_FromNumber(&healthPort),
+							},
+						},
+						ReadinessProbe: &k8s.Probe{
+							HttpGet: &k8s.HttpGetAction{
+								Path: jsii.String("/readyz"),
+								Port: k8s.IntOrString_FromNumber(&healthPort),
+							},
+						},
+					}},
+				},
+			},
+		},
+	})
+	/************************** controller     ********************************/
+	return construct
+}
+
+func schemaString(description string, pattern *string) *k8s.JsonSchemaProps {
+	s := &k8s.JsonSchemaProps{Type: jsii.String("string"), Pattern: pattern}
+	if description != "" {
+		s.Description = jsii.String(description)
+	}
+	return s
+}
+
+func schemaBool(description string) *k8s.JsonSchemaProps {
+	return &k8s.JsonSchemaProps{Type: jsii.String("boolean"), Description: jsii.String(description)}
+}
+
+// detectionJobSpecSchema mirrors DetectionJobSpec in server/k8s/v1alpha1.
+func detectionJobSpecSchema() *k8s.JsonSchemaProps {
+	return &k8s.JsonSchemaProps{
+		Type:     jsii.String("object"),
+		Required: jsii.Strings("source"),
+		Properties: &map[string]*k8s.JsonSchemaProps{
+			"source": {
+				Type:        jsii.String("string"),
+				Description: jsii.String("Source is the url or path of the video or image."),
+				MinLength:   jsii.Number(1),
+			},
+			"model":      schemaString("Model is the name or path of the weights, the server default when empty.", nil),
+			"confidence": schemaString("Confidence is the minimum detection confidence, between 0 and 1.", jsii.String(`^(0(\.[0-9]+)?|1(\.0+)?)$`)),
+			"mode": {
+				Type:        jsii.String("string"),
+				Description: jsii.String("Mode is detect or track."),
+				Enum:        &[]interface{}{"detect", "track"},
+			},
+			"tracker":           schemaString("", nil),
+			"profile":           schemaString("Profile is the transcoding profile of the playable video.", nil),
+			"highlights":        schemaBool(""),
+			"renditions":        schemaBool(""),
+			"image":             schemaString("Image overrides the server image the controller runs jobs with.", nil),
+			"outputVolumeClaim": schemaString("OutputVolumeClaim is a PersistentVolumeClaim mounted as the jobs dir, so the artifacts outlive the pod.", nil),
+			"resources": {
+				Type:                             jsii.String("object"),
+				Description:                      jsii.String("Resources of the pod, set nvidia.com/gpu to run on a GPU node."),
+				XKubernetesPreserveUnknownFields: jsii.Bool(true),
+			},
+			"backoffLimit": {
+				Type:        jsii.String("integer"),
+				Format:      jsii.String("int32"),
+				Description: jsii.String("BackoffLimit is how many times a pod that failed with a retryable error is retried, 1 by default."),
+				Minimum:     jsii.Number(0),
+			},
+		},
+	}
+}
+
+// detectionJobStatusSchema mirrors DetectionJobStatus in server/k8s/v1alpha1.
+func detectionJobStatusSchema() *k8s.JsonSchemaProps {
+	return &k8s.JsonSchemaProps{
+		Type: jsii.String("object"),
+		Properties: &map[string]*k8s.JsonSchemaProps{
+			"phase":   schemaString("", nil),
+			"jobName": schemaString("JobName is the Kubernetes Job running the detect job.", nil),
+			"jobID":   schemaString("JobID is the id of the detect job, the name of its dir in the output volume.", nil),
+			"stage":   schemaString("Stage is inference, transcode or done.", nil),
+			"progress": {
+				Type:        jsii.String("integer"),
+				Format:      jsii.String("int32"),
+				Description: jsii.String("Progress is the percent done of the stage."),
+				Minimum:     jsii.Number(0),
+				Maximum:     jsii.Number(100),
+			},
+			"message":   schemaString("Message is why the job failed.", nil),
+			"errorCode": schemaString("ErrorCode is the code of the failure, see the HTTP API errors.", nil),
+			"artifacts": {
+				Type: jsii.String("array"),
+				Items: &k8s.JsonSchemaProps{
+					Type:     jsii.String("object"),
+					Required: jsii.Strings("name", "path"),
+					Properties: &map[string]*k8s.JsonSchemaProps{
+						"name": schemaString("", nil),
+						"path": schemaString("Path is relative to the output volume.", nil),
+					},
+				},
+			},
+			"counts": {
+				Type:                 jsii.String("object"),
+				Description:          jsii.String("Counts is objects per class."),
+				AdditionalProperties: &k8s.JsonSchemaProps{Type: jsii.String("integer"), Format: jsii.String("int32")},
+			},
+			"startTime":          {Type: jsii.String("string"), Format: jsii.String("date-time")},
+			"completionTime":     {Type: jsii.String("string"), Format: jsii.String("date-time")},
+			"observedGeneration": {Type: jsii.String("integer"), Format: jsii.String("int64")},
+		},
+	}
+}
@@ -0,0 +1,449 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const activeLearningConfidence = 0.25
+
+// activeLearningRequest configures which frames of a detection run are sent
+// to Label Studio. Either JobID points at a finished job or Source is run
+// through yolo with a low confidence so uncertain boxes are kept.
+type activeLearningRequest struct {
+	JobID      string  `json:"job_id"`
+	Source     string  `json:"source"`
+	Confidence float64 `json:"confidence"`
+	Project    int     `json:"project"`
+
+	// Strategies is any of "confidence", "entropy", "rarity" and "conflict".
+	Strategies      []string `json:"strategies"`
+	MinConfidence   float64  `json:"min_confidence"`
+	MaxConfidence   float64  `json:"max_confidence"`
+	RarityThreshold float64  `json:"rarity_threshold"`
+	ConflictIoU     float64  `json:"conflict_iou"`
+	Limit           int      `json:"limit"`
+}
+
+func (r *activeLearningRequest) setDefaults() {
+	if len(r.Strategies) == 0 {
+		r.Strategies = []string{"confidence", "entropy", "rarity", "conflict"}
+	}
+	if r.Confidence == 0 {
+		r.Confidence = activeLearningConfidence
+	}
+	if r.MinConfidence == 0 && r.MaxConfidence == 0 {
+		r.MinConfidence, r.MaxConfidence = 0.25, 0.6
+	}
+	if r.RarityThreshold == 0 {
+		r.RarityThreshold = 0.05
+	}
+	if r.ConflictIoU == 0 {
+		r.ConflictIoU = 0.5
+	}
+	if r.Limit == 0 {
+		r.Limit = 50
+	}
+}
+
+// clampBand raises the bottom of the confidence band to the threshold the job
+// ran with, it has no boxes below it. It returns false when the whole band is
+// below the threshold, like the default band for a job run at 0.70, and the
+// confidence strategy can't match anything.
+func (r *activeLearningRequest) clampBand(threshold float64) bool {
+	if threshold >= r.MaxConfidence {
+		return false
+	}
+	r.MinConfidence = math.Max(r.MinConfidence, threshold)
+	return true
+}
+
+// withoutStrategy returns the strategies of the request but name.
+func (r activeLearningRequest) withoutStrategy(name string) []string {
+	strategies := []string{}
+	for _, s := range r.Strategies {
+		if s != name {
+			strategies = append(strategies, s)
+		}
+	}
+	return strategies
+}
+
+// frameScore is how uncertain the model was about a frame and why.
+type frameScore struct {
+	Frame    int     `json:"frame"`
+	Time     float64 `json:"time"`
+	Score    float64 `json:"score"`
+	Strategy string  `json:"strategy"`
+}
+
+type frameStrategy func(req activeLearningRequest, j *job, frames map[int][]detection) map[int]float64
+
+var frameStrategies = map[string]frameStrategy{
+	"confidence": confidenceBandScores,
+	"entropy":    entropyScores,
+	"rarity":     rarityScores,
+	"conflict":   conflictScores,
+}
+
+// selectUncertainFrames scores every frame with each strategy, keeps the
+// highest score per frame and returns the top frames.
+func selectUncertainFrames(req activeLearningRequest, j *job) ([]frameScore, error) {
+	_, byFrame := detectionsByFrame(j)
+	best := map[int]frameScore{}
+	for _, name := range req.Strategies {
+		strategy, ok := frameStrategies[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown strategy: %q", name)
+		}
+		for frame, score := range strategy(req, j, byFrame) {
+			if score > best[frame].Score {
+				best[frame] = frameScore{Frame: frame, Score: score, Strategy: name}
+			}
+		}
+	}
+
+	selected := []frameScore{}
+	for _, score := range best {
+		if j.Media.FPS > 0 {
+			score.Time = float64(score.Frame) / j.Media.FPS
+		}
+		selected = append(selected, score)
+	}
+	sort.Slice(selected, func(a, b int) bool {
+		if selected[a].Score == selected[b].Score {
+			return selected[a].Frame < selected[b].Frame
+		}
+		return selected[a].Score > selected[b].Score
+	})
+	if len(selected) > req.Limit {
+		selected = selected[:req.Limit]
+	}
+	return selected, nil
+}
+
+// confidenceBandScores favors detections near the middle of the band.
+func confidenceBandScores(req activeLearningRequest, j *job, frames map[int][]detection) map[int]float64 {
+	scores := map[int]float64{}
+	mid := (req.MinConfidence + req.MaxConfidence) / 2
+	half := (req.MaxConfidence - req.MinConfidence) / 2
+	for frame, detections := range frames {
+		for _, d := range detections {
+			if d.Confidence < req.MinConfidence || d.Confidence > req.MaxConfidence {
+				continue
+			}
+			score := 1.0
+			if half > 0 {
+				score = 1 - math.Abs(d.Confidence-mid)/half
+			}
+			scores[frame] = math.Max(scores[frame], math.Max(score, 0.01))
+		}
+	}
+	return scores
+}
+
+// entropyScores uses the binary entropy of each detection confidence.
+func entropyScores(req activeLearningRequest, j *job, frames map[int][]detection) map[int]float64 {
+	scores := map[int]float64{}
+	for frame, detections := range frames {
+		for _, d := range detections {
+			p := math.Min(math.Max(d.Confidence, 1e-6), 1-1e-6)
+			entropy := -(p*math.Log2(p) + (1-p)*math.Log2(1-p))
+			scores[frame] = math.Max(scores[frame], entropy)
+		}
+	}
+	return scores
+}
+
+// rarityScores favors frames with classes that are a small share of all detections.
+func rarityScores(req activeLearningRequest, j *job, frames map[int][]detection) map[int]float64 {
+	counts := map[int]int{}
+	for _, d := range j.Detections {
+		counts[d.ClassID]++
+	}
+	scores := map[int]float64{}
+	for frame, detections := range frames {
+		for _, d := range detections {
+			share := float64(counts[d.ClassID]) / float64(len(j.Detections))
+			if share >= req.RarityThreshold {
+				continue
+			}
+			scores[frame] = math.Max(scores[frame], 1-share/req.RarityThreshold)
+		}
+	}
+	return scores
+}
+
+// conflictScores finds overlapping boxes with different classes.
+func conflictScores(req activeLearningRequest, j *job, frames map[int][]detection) map[int]float64 {
+	scores := map[int]float64{}
+	for frame, detections := range frames {
+		for a := 0; a < len(detections); a++ {
+			for b := a + 1; b < len(detections); b++ {
+				if detections[a].ClassID == detections[b].ClassID {
+					continue
+				}
+				if overlap := iou(detections[a], detections[b]); overlap >= req.ConflictIoU {
+					scores[frame] = math.Max(scores[frame], overlap)
+				}
+			}
+		}
+	}
+	return scores
+}
+
+// iou is the intersection over union of two normalized boxes.
+func iou(a, b detection) float64 {
+	ax1, ay1, ax2, ay2 := a.pixels(1, 1)
+	bx1, by1, bx2, by2 := b.pixels(1, 1)
+	w := math.Min(ax2, bx2) - math.Max(ax1, bx1)
+	h := math.Min(ay2, by2) - math.Max(ay1, by1)
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	intersection := w * h
+	union := a.W*a.H + b.W*b.H - intersection
+	if union <= 0 {
+		return 0
+	}
+	return intersection / union
+}
+
+// frameSource returns the raw media a frame of the job can be read from, the
+// source when it's a local file or the copy saveSource kept. The output has
+// boxes drawn on it so it's never used.
+func frameSource(j *job) (string, error) {
+	if !strings.Contains(j.Source, "://") {
+		if info, err := os.Stat(j.Source); err == nil && info.Mode().IsRegular() {
+			return j.Source, nil
+		}
+	}
+	if j.SourcePath == "" {
+		return "", fmt.Errorf("job %v has no local copy of its source", j.ID)
+	}
+	if _, err := os.Stat(j.SourcePath); err != nil {
+		return "", fmt.Errorf("job %v has no local copy of its source: %v", j.ID, err)
+	}
+	return j.SourcePath, nil
+}
+
+// saveSource downloads an http source into the job dir and keeps its path in
+// SourcePath, so frames come from the same media yolo ran on. Local sources
+// are read in place and streams can't be saved.
+func saveSource(ctx context.Context, j *job) error {
+	if _, err := frameSource(j); err == nil {
+		return nil
+	}
+	u, err := url.Parse(j.Source)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return newJobError(errCodeInvalidSource, fmt.Errorf("frames can't be read from %q, only from files and http urls", j.Source))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.Source, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error downloading source: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return newJobError(errCodeInvalidSource, fmt.Errorf("error downloading source: %v", resp.Status))
+	}
+
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		name = "source"
+	}
+	dir := filepath.Join(j.dir(), "source")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating source dir: %v", err)
+	}
+	sourcePath, err := filepath.Abs(filepath.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	out, err := os.Create(sourcePath)
+	if err != nil {
+		return fmt.Errorf("error creating source copy: %v", err)
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		return fmt.Errorf("error saving source: %v", err)
+	}
+	j.SourcePath = sourcePath
+	return nil
+}
+
+// extractFrame saves a single frame of the job media as a jpg.
+func extractFrame(ctx context.Context, j *job, frame int, destPath string) error {
+	source, err := frameSource(j)
+	if err != nil {
+		return err
+	}
+	args := []string{"-y"}
+	if j.Media.IsVideo && j.Media.FPS > 0 {
+		args = append(args, "-ss", strconv.FormatFloat(float64(frame)/j.Media.FPS, 'f', 3, 64))
+	}
+	args = append(args, "-i", source, "-frames:v", "1", "-q:v", "2", destPath)
+	return executeCommand(ctx, "ffmpeg", ".", args)
+}
+
+type activeLearningResult struct {
+	JobID   string       `json:"job_id"`
+	Project int          `json:"project"`
+	Frames  []frameScore `json:"frames"`
+	TaskIDs []int        `json:"task_ids"`
+}
+
+// runActiveLearning picks uncertain frames of a job, extracts them and
+// imports them into a Label Studio project with pre-annotations.
+func runActiveLearning(req activeLearningRequest, j *job) (activeLearningResult, error) {
+	result := activeLearningResult{JobID: j.ID, Project: req.Project}
+	if !req.clampBand(j.Confidence) {
+		logger.Infof("job %v: confidence band [%v, %v] is below the threshold %v, skipping the confidence strategy", j.ID, req.MinConfidence, req.MaxConfidence, j.Confidence)
+		req.Strategies = req.withoutStrategy("confidence")
+	}
+	frames, err := selectUncertainFrames(req, j)
+	if err != nil {
+		return result, err
+	}
+	result.Frames = frames
+	if len(frames) == 0 {
+		return result, nil
+	}
+
+	if j.SourcePath == "" {
+		if err := saveSource(context.Background(), j); err != nil {
+			return result, err
+		}
+		if j.SourcePath != "" {
+			if err := j.save(); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	client := newLabelStudioClient()
+	config, err := client.projectLabelConfig(req.Project)
+	if err != nil {
+		return result, err
+	}
+
+	framesDir := filepath.Join(j.dir(), "frames")
+	if err := os.MkdirAll(framesDir, 0755); err != nil {
+		return result, fmt.Errorf("error creating frames dir: %v", err)
+	}
+	_, byFrame := detectionsByFrame(j)
+	tasks := []labelStudioImportTask{}
+	for _, frame := range frames {
+		name := j.Media.frameName(frame.Frame)
+		if err := extractFrame(context.Background(), j, frame.Frame, filepath.Join(framesDir, name)); err != nil {
+			logger.Errorf("error extracting frame %v of job %v: %v", frame.Frame, j.ID, err)
+			continue
+		}
+		tasks = append(tasks, labelStudioImportTask{
+			Data: map[string]interface{}{
+				config.DataKey: publicURL(j.fileURL("frames/" + name)),
+				"job_id":       j.ID,
+				"frame":        frame.Frame,
+				"strategy":     frame.Strategy,
+			},
+			Predictions: []labelStudioPrediction{newLabelStudioPrediction(config, j, byFrame[frame.Frame])},
+		})
+	}
+
+	result.TaskIDs, err = client.importTasks(req.Project, tasks)
+	if err != nil {
+		return result, err
+	}
+	logger.Infof("job %v: sent %v frames to label studio project %v", j.ID, len(result.TaskIDs), req.Project)
+	return result, nil
+}
+
+// publicURL prefixes a server path with PUBLIC_URL so Label Studio can load it.
+func publicURL(path string) string {
+	base := os.Getenv("PUBLIC_URL")
+	if base == "" {
+		base = "http://localhost:8080"
+	}
+	return base + path
+}
+
+// autoActiveLearning sends uncertain frames of every finished job to the
+// project in LABEL_STUDIO_PROJECT, when set.
+func autoActiveLearning(j *job) {
+	project, _ := strconv.Atoi(os.Getenv("LABEL_STUDIO_PROJECT"))
+	if project == 0 {
+		return
+	}
+	req := activeLearningRequest{Project: project}
+	req.setDefaults()
+	if _, err := runActiveLearning(req, j); err != nil {
+		logger.Errorf("error running active learning for job %v: %v", j.ID, err)
+	}
+}
+
+// activeLearningHandler runs the active learning loop for a job or a new source.
+// POST /active-learning
+func activeLearningHandler(w http.ResponseWriter, r *http.Request) {
+	req := activeLearningRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	req.setDefaults()
+	if req.Project == 0 {
+		req.Project, _ = strconv.Atoi(os.Getenv("LABEL_STUDIO_PROJECT"))
+	}
+	if req.Project == 0 {
+		http.Error(w, "no label studio project given", http.StatusBadRequest)
+		return
+	}
+
+	var j *job
+	var err error
+	switch {
+	case req.JobID != "":
+		j, err = loadJob(req.JobID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+	case req.Source != "":
+		j = newJob(req.Source)
+		j.Confidence = req.Confidence
+		j.publishStarted()
+		err := saveSource(context.Background(), j)
+		if err == nil {
+			err = j.runDetection(context.Background(), func(line string) {})
+		}
+		if err := j.finish(err); err != nil {
+			logger.Errorf("error running detection: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	default:
+		http.Error(w, "job_id or source is required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := runActiveLearning(req, j)
+	if err != nil {
+		logger.Errorf("error running active learning for job %v: %v", j.ID, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+const testLabelConfig = `<View>
+  <Image name="img" value="$photo"/>
+  <RectangleLabels name="box" toName="img">
+    <Label value="Person"/>
+    <Label value="Car"/>
+  </RectangleLabels>
+</View>`
+
+// labelStudioStandIn serves the two Label Studio endpoints the active
+// learning loop calls and records the imported tasks.
+type labelStudioStandIn struct {
+	mu    sync.Mutex
+	tasks []labelStudioImportTask
+	auth  string
+}
+
+func (s *labelStudioStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auth = r.Header.Get("Authorization")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/projects/7":
+		writeJSON(w, http.StatusOK, map[string]string{"label_config": testLabelConfig})
+	case r.Method == http.MethodPost && r.URL.Path == "/api/projects/7/import":
+		tasks := []labelStudioImportTask{}
+		if err := json.NewDecoder(r.Body).Decode(&tasks); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ids := []int{}
+		for _, task := range tasks {
+			ids = append(ids, 100+len(s.tasks))
+			s.tasks = append(s.tasks, task)
+		}
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"task_count": len(tasks), "task_ids": ids})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func activeLearningTestJob(t *testing.T) *job {
+	t.Helper()
+	source, err := filepath.Abs("clip.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(source, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	j := newJob(source)
+	j.Media = mediaInfo{Name: "clip", Width: 640, Height: 480, FPS: 10, Frames: 30, IsVideo: true}
+	j.Classes = []string{"person", "car"}
+	j.Detections = []detection{
+		// confident frame, above the band
+		{Frame: 1, ClassID: 0, Class: "person", X: 0.5, Y: 0.5, W: 0.2, H: 0.4, Confidence: 0.99},
+		// uncertain for a job run at the default threshold
+		{Frame: 5, ClassID: 1, Class: "car", X: 0.3, Y: 0.3, W: 0.1, H: 0.1, Confidence: 0.8},
+	}
+	return j
+}
+
+func TestClampBand(t *testing.T) {
+	req := activeLearningRequest{MinConfidence: 0.5, MaxConfidence: 0.9}
+	if !req.clampBand(defaultConfidence) || req.MinConfidence != defaultConfidence || req.MaxConfidence != 0.9 {
+		t.Fatalf("band = [%v, %v], want [%v, 0.9]", req.MinConfidence, req.MaxConfidence, defaultConfidence)
+	}
+
+	// the default band is below the default threshold
+	req = activeLearningRequest{}
+	req.setDefaults()
+	if req.clampBand(defaultConfidence) {
+		t.Fatalf("band [%v, %v] kept for a job run at %v", req.MinConfidence, req.MaxConfidence, defaultConfidence)
+	}
+
+	req = activeLearningRequest{}
+	req.setDefaults()
+	if !req.clampBand(activeLearningConfidence) || req.MinConfidence != 0.25 || req.MaxConfidence != 0.6 {
+		t.Fatalf("band = [%v, %v], want the default band for a low threshold run", req.MinConfidence, req.MaxConfidence)
+	}
+}
+
+func TestRunActiveLearning(t *testing.T) {
+	chdirTemp(t)
+	// ffmpeg writes its last argument, the frame jpg
+	fakeCommand(t, "ffmpeg", `for last; do :; done; echo frame > "$last"`)
+	standIn := &labelStudioStandIn{}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+	t.Setenv("LABEL_STUDIO_URL", server.URL)
+	t.Setenv("LABEL_STUDIO_API_KEY", "secret")
+	t.Setenv("PUBLIC_URL", "http://demo")
+
+	j := activeLearningTestJob(t)
+	req := activeLearningRequest{Project: 7, Strategies: []string{"confidence"}, MinConfidence: 0.6, MaxConfidence: 0.9, Limit: 1}
+	req.setDefaults()
+	result, err := runActiveLearning(req, j)
+	if err != nil {
+		t.Fatalf("runActiveLearning: %v", err)
+	}
+
+	if len(result.Frames) != 1 || result.Frames[0].Frame != 5 {
+		t.Fatalf("frames = %+v, want frame 5 as the most uncertain", result.Frames)
+	}
+	if len(result.TaskIDs) != 1 || result.TaskIDs[0] != 100 {
+		t.Fatalf("task ids = %v, want [100]", result.TaskIDs)
+	}
+	if standIn.auth != "Token secret" {
+		t.Errorf("authorization = %q", standIn.auth)
+	}
+	if _, err := os.Stat(filepath.Join(j.dir(), "frames", "clip_000005.jpg")); err != nil {
+		t.Errorf("frame was not extracted: %v", err)
+	}
+
+	task := standIn.tasks[0]
+	if got := task.Data["photo"]; got != "http://demo/static/jobs/"+j.ID+"/frames/clip_000005.jpg" {
+		t.Errorf("task image = %v", got)
+	}
+	if len(task.Predictions) != 1 || len(task.Predictions[0].Result) != 1 {
+		t.Fatalf("predictions = %+v", task.Predictions)
+	}
+	box := task.Predictions[0].Result[0]
+	if box.FromName != "box" || box.ToName != "img" || len(box.Value.RectangleLabels) != 1 || box.Value.RectangleLabels[0] != "Car" {
+		t.Errorf("prediction = %+v, want a Car box from box to img", box)
+	}
+}
+
+func TestRunActiveLearningLabelStudioError(t *testing.T) {
+	chdirTemp(t)
+	fakeCommand(t, "ffmpeg", `for last; do :; done; echo frame > "$last"`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such project", http.StatusNotFound)
+	}))
+	defer server.Close()
+	t.Setenv("LABEL_STUDIO_URL", server.URL)
+
+	req := activeLearningRequest{Project: 7}
+	req.setDefaults()
+	if _, err := runActiveLearning(req, activeLearningTestJob(t)); err == nil {
+		t.Fatal("expected an error from label studio")
+	}
+}
+
+func TestFrameSourceIsTheRawSource(t *testing.T) {
+	chdirTemp(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("raw video"))
+	}))
+	defer server.Close()
+	output, _ := filepath.Abs("out.mp4")
+	if err := os.WriteFile(output, []byte("annotated video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the output has boxes drawn on it
+	j := newJob(server.URL + "/videos/clip.mp4")
+	j.OutputPath = output
+	if source, err := frameSource(j); err == nil {
+		t.Fatalf("frameSource = %q before the source was saved", source)
+	}
+	if err := saveSource(context.Background(), j); err != nil {
+		t.Fatal(err)
+	}
+	source, err := frameSource(j)
+	if err != nil || filepath.Base(source) != "clip.mp4" {
+		t.Fatalf("frameSource = %q, %v, want the saved clip.mp4", source, err)
+	}
+	if data, _ := os.ReadFile(source); string(data) != "raw video" {
+		t.Errorf("saved source = %q", data)
+	}
+
+	stream := newJob("rtsp://camera/stream")
+	if err := saveSource(context.Background(), stream); err == nil {
+		t.Error("saved a stream")
+	}
+}
@@ -0,0 +1,7078 @@
+// Package apiv1 provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.4.1 DO NOT EDIT.
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	WorkerTokenScopes = "workerToken.Scopes"
+)
+
+// Defines values for ActiveLearningRequestStrategies.
+const (
+	Confidence ActiveLearningRequestStrategies = "confidence"
+	Conflict   ActiveLearningRequestStrategies = "conflict"
+	Entropy    ActiveLearningRequestStrategies = "entropy"
+	Rarity     ActiveLearningRequestStrategies = "rarity"
+)
+
+// Defines values for DetectRequestMode.
+const (
+	DetectRequestModeDetect DetectRequestMode = "detect"
+	DetectRequestModeTrack  DetectRequestMode = "track"
+)
+
+// Defines values for EvaluationRequestSplit.
+const (
+	EvaluationRequestSplitTest EvaluationRequestSplit = "test"
+	EvaluationRequestSplitVal  EvaluationRequestSplit = "val"
+)
+
+// Defines values for FrameExtractionRequestMode.
+const (
+	FrameExtractionRequestModeDetection FrameExtractionRequestMode = "detection"
+	FrameExtractionRequestModeInterval  FrameExtractionRequestMode = "interval"
+	FrameExtractionRequestModeScene     FrameExtractionRequestMode = "scene"
+)
+
+// Defines values for JobMode.
+const (
+	JobModeDetect JobMode = "detect"
+	JobModeTrack  JobMode = "track"
+)
+
+// Defines values for JobStatus.
+const (
+	JobStatusCancelled JobStatus = "cancelled"
+	JobStatusDone      JobStatus = "done"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusRunning   JobStatus = "running"
+)
+
+// Defines values for JobType.
+const (
+	JobTypeDetect   JobType = "detect"
+	JobTypeEvaluate JobType = "evaluate"
+	JobTypeFrames   JobType = "frames"
+)
+
+// Defines values for JobErrorCode.
+const (
+	JobErrorCodeCancelled        JobErrorCode = "cancelled"
+	JobErrorCodeDownloadFailed   JobErrorCode = "download_failed"
+	JobErrorCodeEvaluationFailed JobErrorCode = "evaluation_failed"
+	JobErrorCodeInferenceFailed  JobErrorCode = "inference_failed"
+	JobErrorCodeInternal         JobErrorCode = "internal"
+	JobErrorCodeInvalidDataset   JobErrorCode = "invalid_dataset"
+	JobErrorCodeInvalidRequest   JobErrorCode = "invalid_request"
+	JobErrorCodeInvalidSource    JobErrorCode = "invalid_source"
+	JobErrorCodeModelLoadFailed  JobErrorCode = "model_load_failed"
+	JobErrorCodeQuotaExceeded    JobErrorCode = "quota_exceeded"
+	JobErrorCodeTimeout          JobErrorCode = "timeout"
+	JobErrorCodeTrainingFailed   JobErrorCode = "training_failed"
+	JobErrorCodeTranscodeFailed  JobErrorCode = "transcode_failed"
+)
+
+// Defines values for JobRequestMode.
+const (
+	JobRequestModeDetect JobRequestMode = "detect"
+	JobRequestModeTrack  JobRequestMode = "track"
+)
+
+// Defines values for JobRequestTracker.
+const (
+	JobRequestTrackerBotsortYaml   JobRequestTracker = "botsort.yaml"
+	JobRequestTrackerBytetrackYaml JobRequestTracker = "bytetrack.yaml"
+)
+
+// Defines values for JobUploadMode.
+const (
+	JobUploadModeDetect JobUploadMode = "detect"
+	JobUploadModeTrack  JobUploadMode = "track"
+)
+
+// Defines values for JobUploadTracker.
+const (
+	JobUploadTrackerBotsortYaml   JobUploadTracker = "botsort.yaml"
+	JobUploadTrackerBytetrackYaml JobUploadTracker = "bytetrack.yaml"
+)
+
+// Defines values for RuleDirection.
+const (
+	Any      RuleDirection = "any"
+	Backward RuleDirection = "backward"
+	Forward  RuleDirection = "forward"
+)
+
+// Defines values for RuleType.
+const (
+	Line RuleType = "line"
+	Zone RuleType = "zone"
+)
+
+// Defines values for RuleEventType.
+const (
+	LineCross RuleEventType = "line_cross"
+	ZoneEnter RuleEventType = "zone_enter"
+	ZoneExit  RuleEventType = "zone_exit"
+)
+
+// Defines values for TrainingStatus.
+const (
+	Cancelled TrainingStatus = "cancelled"
+	Done      TrainingStatus = "done"
+	Failed    TrainingStatus = "failed"
+	Running   TrainingStatus = "running"
+)
+
+// Defines values for TranscodeProfileCodec.
+const (
+	Av1  TranscodeProfileCodec = "av1"
+	H264 TranscodeProfileCodec = "h264"
+	Hevc TranscodeProfileCodec = "hevc"
+	Vp9  TranscodeProfileCodec = "vp9"
+)
+
+// Defines values for TranscodeProfileContainer.
+const (
+	Mp4  TranscodeProfileContainer = "mp4"
+	Webm TranscodeProfileContainer = "webm"
+)
+
+// Defines values for CompareEvaluationsParamsSplit.
+const (
+	CompareEvaluationsParamsSplitTest CompareEvaluationsParamsSplit = "test"
+	CompareEvaluationsParamsSplitVal  CompareEvaluationsParamsSplit = "val"
+)
+
+// Defines values for CompareEvaluationsParamsFormat.
+const (
+	CompareEvaluationsParamsFormatHtml CompareEvaluationsParamsFormat = "html"
+	CompareEvaluationsParamsFormatJson CompareEvaluationsParamsFormat = "json"
+)
+
+// Defines values for StreamJobEventsParamsFormat.
+const (
+	StreamJobEventsParamsFormatHtml StreamJobEventsParamsFormat = "html"
+	StreamJobEventsParamsFormatJson StreamJobEventsParamsFormat = "json"
+)
+
+// Defines values for GetJobLogParamsLevel.
+const (
+	Error GetJobLogParamsLevel = "error"
+	Info  GetJobLogParamsLevel = "info"
+	Warn  GetJobLogParamsLevel = "warn"
+)
+
+// ActiveLearningRequest defines model for ActiveLearningRequest.
+type ActiveLearningRequest struct {
+	Confidence      *float32                           `json:"confidence,omitempty"`
+	ConflictIou     *float32                           `json:"conflict_iou,omitempty"`
+	JobId           *string                            `json:"job_id,omitempty"`
+	Limit           *int                               `json:"limit,omitempty"`
+	MaxConfidence   *float32                           `json:"max_confidence,omitempty"`
+	MinConfidence   *float32                           `json:"min_confidence,omitempty"`
+	Project         *int                               `json:"project,omitempty"`
+	RarityThreshold *float32                           `json:"rarity_threshold,omitempty"`
+	Source          *string                            `json:"source,omitempty"`
+	Strategies      *[]ActiveLearningRequestStrategies `json:"strategies,omitempty"`
+}
+
+// ActiveLearningRequestStrategies defines model for ActiveLearningRequest.Strategies.
+type ActiveLearningRequestStrategies string
+
+// ActiveLearningResult defines model for ActiveLearningResult.
+type ActiveLearningResult struct {
+	Frames  *[]FrameScore `json:"frames,omitempty"`
+	JobId   *string       `json:"job_id,omitempty"`
+	Project *int          `json:"project,omitempty"`
+	TaskIds *[]int        `json:"task_ids,omitempty"`
+}
+
+// Artifact defines model for Artifact.
+type Artifact struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+// CacheStats defines model for CacheStats.
+type CacheStats struct {
+	Bytes   *int     `json:"bytes,omitempty"`
+	Entries *int     `json:"entries,omitempty"`
+	HitRate *float32 `json:"hit_rate,omitempty"`
+	Hits    *int     `json:"hits,omitempty"`
+
+	// MaxAge Go duration, the CACHE_MAX_AGE format, e.g. 168h0m0s.
+	MaxAge   *string `json:"max_age,omitempty"`
+	MaxBytes *int    `json:"max_bytes,omitempty"`
+	Misses   *int    `json:"misses,omitempty"`
+}
+
+// ClassMetrics defines model for ClassMetrics.
+type ClassMetrics struct {
+	Class string `json:"class"`
+
+	// Instances Objects of the class in the dataset.
+	Instances int     `json:"instances"`
+	Map50     float32 `json:"map50"`
+	Map5095   float32 `json:"map50_95"`
+	Precision float32 `json:"precision"`
+	Recall    float32 `json:"recall"`
+}
+
+// ClassTimeline defines model for ClassTimeline.
+type ClassTimeline struct {
+	Class     *string     `json:"class,omitempty"`
+	Intervals *[]Interval `json:"intervals,omitempty"`
+}
+
+// ConfusionMatrix matrix[predicted][actual] over labels, the classes plus "background" for missed objects and false positives.
+type ConfusionMatrix struct {
+	Labels []string    `json:"labels"`
+	Matrix [][]float32 `json:"matrix"`
+}
+
+// DetectRequest Detect job as queued for a worker.
+type DetectRequest struct {
+	Confidence *float64           `json:"confidence,omitempty"`
+	Highlights *bool              `json:"highlights,omitempty"`
+	Mode       *DetectRequestMode `json:"mode,omitempty"`
+
+	// Model Model name or path.
+	Model      *string `json:"model,omitempty"`
+	Profile    *string `json:"profile,omitempty"`
+	Renditions *bool   `json:"renditions,omitempty"`
+
+	// Source Url or path of the media.
+	Source  string  `json:"source"`
+	Tracker *string `json:"tracker,omitempty"`
+}
+
+// DetectRequestMode defines model for DetectRequest.Mode.
+type DetectRequestMode string
+
+// Detection A box in yolo's normalized center format.
+type Detection struct {
+	Class      string  `json:"class"`
+	ClassId    int     `json:"class_id"`
+	Confidence float32 `json:"confidence"`
+	Frame      int     `json:"frame"`
+	H          float32 `json:"h"`
+	Time       float32 `json:"time"`
+	TrackId    *int    `json:"track_id,omitempty"`
+	W          float32 `json:"w"`
+	X          float32 `json:"x"`
+	Y          float32 `json:"y"`
+}
+
+// DetectionMetrics defines model for DetectionMetrics.
+type DetectionMetrics struct {
+	Map50     float32 `json:"map50"`
+	Map5095   float32 `json:"map50_95"`
+	Precision float32 `json:"precision"`
+	Recall    float32 `json:"recall"`
+}
+
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	// Error Why a job or request failed.
+	Error JobError `json:"error"`
+}
+
+// Evaluation defines model for Evaluation.
+type Evaluation struct {
+	Classes []ClassMetrics `json:"classes"`
+
+	// ConfusionMatrix matrix[predicted][actual] over labels, the classes plus "background" for missed objects and false positives.
+	ConfusionMatrix *ConfusionMatrix `json:"confusion_matrix,omitempty"`
+	Dataset         string           `json:"dataset"`
+	Map50           float32          `json:"map50"`
+	Map5095         float32          `json:"map50_95"`
+	ModelVersion    string           `json:"model_version"`
+	Precision       float32          `json:"precision"`
+	Recall          float32          `json:"recall"`
+	Split           string           `json:"split"`
+}
+
+// EvaluationRequest defines model for EvaluationRequest.
+type EvaluationRequest struct {
+	// Confidence Minimum confidence of predictions, 0 uses the yolo default.
+	Confidence *float32 `json:"confidence,omitempty"`
+
+	// Dataset A data.yaml, a folder with one, the id of a frames job or a dataset yolo knows like coco8.yaml.
+	Dataset   string  `json:"dataset"`
+	Device    *string `json:"device,omitempty"`
+	ImageSize *int    `json:"image_size,omitempty"`
+
+	// Iou NMS threshold.
+	Iou *float32 `json:"iou,omitempty"`
+
+	// Model A model name from /models or a yolo model like yolov8n.pt, empty uses the default model.
+	Model *string                 `json:"model,omitempty"`
+	Split *EvaluationRequestSplit `json:"split,omitempty"`
+}
+
+// EvaluationRequestSplit defines model for EvaluationRequest.Split.
+type EvaluationRequestSplit string
+
+// EvaluationRow defines model for EvaluationRow.
+type EvaluationRow struct {
+	// Classes mAP50-95 of every class.
+	Classes      map[string]float32 `json:"classes"`
+	Dataset      string             `json:"dataset"`
+	EvaluatedAt  time.Time          `json:"evaluated_at"`
+	JobId        string             `json:"job_id"`
+	Map50        float32            `json:"map50"`
+	Map5095      float32            `json:"map50_95"`
+	Model        string             `json:"model"`
+	ModelVersion string             `json:"model_version"`
+	Precision    float32            `json:"precision"`
+	Recall       float32            `json:"recall"`
+	Split        string             `json:"split"`
+}
+
+// EvaluationTable defines model for EvaluationTable.
+type EvaluationTable struct {
+	Classes []string        `json:"classes"`
+	Rows    []EvaluationRow `json:"rows"`
+}
+
+// FrameExtractionRequest defines model for FrameExtractionRequest.
+type FrameExtractionRequest struct {
+	Confidence     *float32                    `json:"confidence,omitempty"`
+	Dedup          *int                        `json:"dedup,omitempty"`
+	Interval       *float32                    `json:"interval,omitempty"`
+	Labels         *bool                       `json:"labels,omitempty"`
+	Mode           *FrameExtractionRequestMode `json:"mode,omitempty"`
+	SceneThreshold *float32                    `json:"scene_threshold,omitempty"`
+	Source         string                      `json:"source"`
+}
+
+// FrameExtractionRequestMode defines model for FrameExtractionRequest.Mode.
+type FrameExtractionRequestMode string
+
+// FrameScore defines model for FrameScore.
+type FrameScore struct {
+	Frame    *int     `json:"frame,omitempty"`
+	Score    *float32 `json:"score,omitempty"`
+	Strategy *string  `json:"strategy,omitempty"`
+	Time     *float32 `json:"time,omitempty"`
+}
+
+// HighlightOptions defines model for HighlightOptions.
+type HighlightOptions struct {
+	Classes       *[]string `json:"classes,omitempty"`
+	MinConfidence *float32  `json:"min_confidence,omitempty"`
+
+	// MinGap Shortest pause between detections that starts a new clip.
+	MinGap *float32 `json:"min_gap,omitempty"`
+
+	// Padding Seconds kept before and after every interval, 0 cuts exactly at the detections.
+	Padding *float32 `json:"padding,omitempty"`
+}
+
+// Interval defines model for Interval.
+type Interval struct {
+	Count         *int     `json:"count,omitempty"`
+	End           *float32 `json:"end,omitempty"`
+	MaxConfidence *float32 `json:"max_confidence,omitempty"`
+	Start         *float32 `json:"start,omitempty"`
+}
+
+// Job defines model for Job.
+type Job struct {
+	Artifacts  *[]Artifact     `json:"artifacts,omitempty"`
+	CacheKey   *string         `json:"cache_key,omitempty"`
+	Cached     *bool           `json:"cached,omitempty"`
+	Classes    *[]string       `json:"classes,omitempty"`
+	Confidence *float32        `json:"confidence,omitempty"`
+	Counts     *map[string]int `json:"counts,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Detections *[]Detection    `json:"detections,omitempty"`
+	Error      *string         `json:"error,omitempty"`
+	Evaluation *Evaluation     `json:"evaluation,omitempty"`
+	Events     *[]RuleEvent    `json:"events,omitempty"`
+
+	// Failure Why a job or request failed.
+	Failure    *JobError   `json:"failure,omitempty"`
+	Highlights *[]Interval `json:"highlights,omitempty"`
+	Id         string      `json:"id"`
+	Media      *MediaInfo  `json:"media,omitempty"`
+	Mode       *JobMode    `json:"mode,omitempty"`
+	Model      string      `json:"model"`
+	OutputPath *string     `json:"output_path,omitempty"`
+	Profile    *string     `json:"profile,omitempty"`
+	Renditions *bool       `json:"renditions,omitempty"`
+	Source     string      `json:"source"`
+
+	// SourcePath Copy of a remote source kept in the job dir by jobs that read frames from it.
+	SourcePath *string       `json:"source_path,omitempty"`
+	Status     JobStatus     `json:"status"`
+	Summary    *VideoSummary `json:"summary,omitempty"`
+	Tracker    *string       `json:"tracker,omitempty"`
+	Tracks     *[]Track      `json:"tracks,omitempty"`
+	Type       JobType       `json:"type"`
+	VideoPath  *string       `json:"video_path,omitempty"`
+}
+
+// JobMode defines model for Job.Mode.
+type JobMode string
+
+// JobStatus defines model for Job.Status.
+type JobStatus string
+
+// JobType defines model for Job.Type.
+type JobType string
+
+// JobCreated defines model for JobCreated.
+type JobCreated struct {
+	// Events Url of the SSE stream of the job.
+	Events string `json:"events"`
+	Id     string `json:"id"`
+}
+
+// JobError Why a job or request failed.
+type JobError struct {
+	// Code Stable error code.
+	Code JobErrorCode `json:"code"`
+
+	// Detail Underlying cause.
+	Detail *string `json:"detail,omitempty"`
+
+	// Message Message for users.
+	Message string `json:"message"`
+
+	// Retryable Running the same job again can succeed.
+	Retryable bool `json:"retryable"`
+}
+
+// JobErrorCode Stable error code.
+type JobErrorCode string
+
+// JobRequest defines model for JobRequest.
+type JobRequest struct {
+	// Confidence Minimum detection confidence, 0 uses the default.
+	Confidence *float64 `json:"confidence,omitempty"`
+
+	// Highlights "on" cuts highlight clips.
+	Highlights *string `json:"highlights,omitempty"`
+
+	// Message Url or path of the media.
+	Message string          `json:"message"`
+	Mode    *JobRequestMode `json:"mode,omitempty"`
+
+	// Model Model name from /models, empty uses the default.
+	Model *string `json:"model,omitempty"`
+
+	// Profile Transcoding profile name from /profiles.
+	Profile *string `json:"profile,omitempty"`
+
+	// Renditions "on" writes HLS renditions.
+	Renditions *string            `json:"renditions,omitempty"`
+	Tracker    *JobRequestTracker `json:"tracker,omitempty"`
+}
+
+// JobRequestMode defines model for JobRequest.Mode.
+type JobRequestMode string
+
+// JobRequestTracker defines model for JobRequest.Tracker.
+type JobRequestTracker string
+
+// JobUpload defines model for JobUpload.
+type JobUpload struct {
+	// Confidence Minimum detection confidence, 0 uses the default.
+	Confidence *float64 `json:"confidence,omitempty"`
+
+	// File Media to upload instead of a url.
+	File *openapi_types.File `json:"file,omitempty"`
+
+	// Highlights "on" cuts highlight clips.
+	Highlights *string `json:"highlights,omitempty"`
+
+	// Message Url or path of the media, ignored when a file is uploaded.
+	Message *string        `json:"message,omitempty"`
+	Mode    *JobUploadMode `json:"mode,omitempty"`
+
+	// Model Model name from /models, empty uses the default.
+	Model *string `json:"model,omitempty"`
+
+	// Profile Transcoding profile name from /profiles.
+	Profile *string `json:"profile,omitempty"`
+
+	// Renditions "on" writes HLS renditions.
+	Renditions *string           `json:"renditions,omitempty"`
+	Tracker    *JobUploadTracker `json:"tracker,omitempty"`
+}
+
+// JobUploadMode defines model for JobUpload.Mode.
+type JobUploadMode string
+
+// JobUploadTracker defines model for JobUpload.Tracker.
+type JobUploadTracker string
+
+// MediaInfo defines model for MediaInfo.
+type MediaInfo struct {
+	Fps     *float32 `json:"fps,omitempty"`
+	Frames  *int     `json:"frames,omitempty"`
+	Height  *int     `json:"height,omitempty"`
+	IsVideo *bool    `json:"is_video,omitempty"`
+	Name    *string  `json:"name,omitempty"`
+	Width   *int     `json:"width,omitempty"`
+}
+
+// Model defines model for Model.
+type Model struct {
+	Card    *ModelCard `json:"card,omitempty"`
+	Default *bool      `json:"default,omitempty"`
+	Name    string     `json:"name"`
+	Path    string     `json:"path"`
+	Version string     `json:"version"`
+}
+
+// ModelCard defines model for ModelCard.
+type ModelCard struct {
+	BaseModel *string           `json:"base_model,omitempty"`
+	CreatedAt *time.Time        `json:"created_at,omitempty"`
+	Dataset   *string           `json:"dataset,omitempty"`
+	Epochs    *int              `json:"epochs,omitempty"`
+	Metrics   *DetectionMetrics `json:"metrics,omitempty"`
+	Training  *string           `json:"training,omitempty"`
+}
+
+// Point defines model for Point.
+type Point struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+}
+
+// Rule defines model for Rule.
+type Rule struct {
+	Classes       *[]string      `json:"classes,omitempty"`
+	Direction     *RuleDirection `json:"direction,omitempty"`
+	DwellSeconds  *float32       `json:"dwell_seconds,omitempty"`
+	Id            *string        `json:"id,omitempty"`
+	Line          *[]Point       `json:"line,omitempty"`
+	MinConfidence *float32       `json:"min_confidence,omitempty"`
+	Name          *string        `json:"name,omitempty"`
+	Polygon       *[]Point       `json:"polygon,omitempty"`
+	Type          RuleType       `json:"type"`
+}
+
+// RuleDirection defines model for Rule.Direction.
+type RuleDirection string
+
+// RuleType defines model for Rule.Type.
+type RuleType string
+
+// RuleEvent defines model for RuleEvent.
+type RuleEvent struct {
+	Class      *string        `json:"class,omitempty"`
+	Confidence *float32       `json:"confidence,omitempty"`
+	Direction  *string        `json:"direction,omitempty"`
+	Frame      *int           `json:"frame,omitempty"`
+	RuleId     *string        `json:"rule_id,omitempty"`
+	RuleName   *string        `json:"rule_name,omitempty"`
+	Time       *float32       `json:"time,omitempty"`
+	TrackId    *int           `json:"track_id,omitempty"`
+	Type       *RuleEventType `json:"type,omitempty"`
+}
+
+// RuleEventType defines model for RuleEvent.Type.
+type RuleEventType string
+
+// RuleSet defines model for RuleSet.
+type RuleSet struct {
+	Id    *string `json:"id,omitempty"`
+	Rules []Rule  `json:"rules"`
+
+	// Source Source the rules apply to, "*" for every source.
+	Source string `json:"source"`
+}
+
+// StreamConfig defines model for StreamConfig.
+type StreamConfig struct {
+	Confidence     *float32 `json:"confidence,omitempty"`
+	Format         *string  `json:"format,omitempty"`
+	Model          *string  `json:"model,omitempty"`
+	SegmentSeconds *int     `json:"segment_seconds,omitempty"`
+	Source         string   `json:"source"`
+	Window         *int     `json:"window,omitempty"`
+}
+
+// StreamSegment defines model for StreamSegment.
+type StreamSegment struct {
+	Counts     *map[string]int `json:"counts,omitempty"`
+	Detections *int            `json:"detections,omitempty"`
+	Duration   *float32        `json:"duration,omitempty"`
+	Index      *int            `json:"index,omitempty"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	Url        *string         `json:"url,omitempty"`
+}
+
+// StreamStatus defines model for StreamStatus.
+type StreamStatus struct {
+	Config *StreamConfig `json:"config,omitempty"`
+
+	// Dropped Segments skipped because detection fell behind the camera.
+	Dropped     *int             `json:"dropped,omitempty"`
+	Id          *string          `json:"id,omitempty"`
+	LastError   *string          `json:"last_error,omitempty"`
+	PlaylistUrl *string          `json:"playlist_url,omitempty"`
+	Reconnects  *int             `json:"reconnects,omitempty"`
+	Segments    *int             `json:"segments,omitempty"`
+	StartedAt   *time.Time       `json:"started_at,omitempty"`
+	Status      *string          `json:"status,omitempty"`
+	Window      *[]StreamSegment `json:"window,omitempty"`
+}
+
+// SummaryFrame defines model for SummaryFrame.
+type SummaryFrame struct {
+	Class      *string  `json:"class,omitempty"`
+	Confidence *float32 `json:"confidence,omitempty"`
+	Frame      *int     `json:"frame,omitempty"`
+	Time       *float32 `json:"time,omitempty"`
+}
+
+// Track defines model for Track.
+type Track struct {
+	Class          *string       `json:"class,omitempty"`
+	ClassId        *int          `json:"class_id,omitempty"`
+	Detections     *int          `json:"detections,omitempty"`
+	FirstFrame     *int          `json:"first_frame,omitempty"`
+	FirstSeen      *float32      `json:"first_seen,omitempty"`
+	Id             *int          `json:"id,omitempty"`
+	LastFrame      *int          `json:"last_frame,omitempty"`
+	LastSeen       *float32      `json:"last_seen,omitempty"`
+	MeanConfidence *float32      `json:"mean_confidence,omitempty"`
+	Trajectory     *[]TrackPoint `json:"trajectory,omitempty"`
+}
+
+// TrackPoint defines model for TrackPoint.
+type TrackPoint struct {
+	Frame *int     `json:"frame,omitempty"`
+	Time  *float32 `json:"time,omitempty"`
+	X     *float32 `json:"x,omitempty"`
+	Y     *float32 `json:"y,omitempty"`
+}
+
+// Training defines model for Training.
+type Training struct {
+	BaseModel *string        `json:"base_model,omitempty"`
+	Best      *TrainingEpoch `json:"best,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+
+	// Dataset Resolved data.yaml.
+	Dataset *string         `json:"dataset,omitempty"`
+	Epochs  []TrainingEpoch `json:"epochs"`
+
+	// Failure Why a job or request failed.
+	Failure    *JobError        `json:"failure,omitempty"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty"`
+	Id         string           `json:"id"`
+	LogUrl     *string          `json:"log_url,omitempty"`
+	Model      *Model           `json:"model,omitempty"`
+	Request    *TrainingRequest `json:"request,omitempty"`
+	ResultsUrl *string          `json:"results_url,omitempty"`
+	Resumes    *int             `json:"resumes,omitempty"`
+	Status     TrainingStatus   `json:"status"`
+
+	// Trained Yolo finished, the weights can be registered again when that failed.
+	Trained *bool `json:"trained,omitempty"`
+}
+
+// TrainingStatus defines model for Training.Status.
+type TrainingStatus string
+
+// TrainingEpoch defines model for TrainingEpoch.
+type TrainingEpoch struct {
+	BoxLoss   *float32 `json:"box_loss,omitempty"`
+	ClsLoss   *float32 `json:"cls_loss,omitempty"`
+	DflLoss   *float32 `json:"dfl_loss,omitempty"`
+	Epoch     int      `json:"epoch"`
+	Map50     float32  `json:"map50"`
+	Map5095   float32  `json:"map50_95"`
+	Precision float32  `json:"precision"`
+	Recall    float32  `json:"recall"`
+
+	// Values Every column of the results.csv row.
+	Values *map[string]float32 `json:"values,omitempty"`
+}
+
+// TrainingRequest defines model for TrainingRequest.
+type TrainingRequest struct {
+	// Batch Batch size, 0 uses the yolo default and -1 picks one that fits the GPU.
+	Batch *int `json:"batch,omitempty"`
+
+	// Dataset A data.yaml, a folder with one, the id of a frames job or a dataset yolo knows like coco8.yaml.
+	Dataset string `json:"dataset"`
+
+	// Device "cpu", a GPU index or a list like "0,1", empty lets yolo pick.
+	Device *string `json:"device,omitempty"`
+	Epochs *int    `json:"epochs,omitempty"`
+
+	// Hyperparameters Other yolo train arguments, e.g. {"mosaic": 0.5, "optimizer": "AdamW"}. Values are strings, numbers or booleans.
+	Hyperparameters *map[string]interface{} `json:"hyperparameters,omitempty"`
+	ImageSize       *int                    `json:"image_size,omitempty"`
+	LearningRate    *float32                `json:"learning_rate,omitempty"`
+
+	// Model Weights to start from, a model name from /models or a yolo model like yolov8n.pt. Empty uses the default model.
+	Model *string `json:"model,omitempty"`
+
+	// Name Name the trained model is registered as, train-<id> by default.
+	Name *string `json:"name,omitempty"`
+
+	// Patience Stop after this many epochs without improvement, 0 uses the yolo default.
+	Patience *int `json:"patience,omitempty"`
+}
+
+// TranscodeProfile defines model for TranscodeProfile.
+type TranscodeProfile struct {
+	Codec     TranscodeProfileCodec     `json:"codec"`
+	Container TranscodeProfileContainer `json:"container"`
+
+	// Crf Constant rate factor, at most 51 for h264 and hevc and 63 for vp9 and av1.
+	Crf    *int     `json:"crf,omitempty"`
+	Fps    *float32 `json:"fps,omitempty"`
+	Height *int     `json:"height,omitempty"`
+	Name   string   `json:"name"`
+}
+
+// TranscodeProfileCodec defines model for TranscodeProfile.Codec.
+type TranscodeProfileCodec string
+
+// TranscodeProfileContainer defines model for TranscodeProfile.Container.
+type TranscodeProfileContainer string
+
+// VideoSummary defines model for VideoSummary.
+type VideoSummary struct {
+	// ContactSheetUrl JPEG with the top frames of every class.
+	ContactSheetUrl *string          `json:"contact_sheet_url,omitempty"`
+	Duration        *float32         `json:"duration,omitempty"`
+	Timeline        *[]ClassTimeline `json:"timeline,omitempty"`
+
+	// TimelineUrl SVG rendering of the timeline. The job artifacts also list timeline.png and timeline.json.
+	TimelineUrl *string         `json:"timeline_url,omitempty"`
+	TopFrames   *[]SummaryFrame `json:"top_frames,omitempty"`
+}
+
+// WorkerCompletion defines model for WorkerCompletion.
+type WorkerCompletion struct {
+	// Failure Why a job or request failed.
+	Failure *JobError `json:"failure,omitempty"`
+}
+
+// WorkerEvents defines model for WorkerEvents.
+type WorkerEvents struct {
+	// Events Feed events as sent by /jobs/{id}/events?format=json.
+	Events []map[string]interface{} `json:"events"`
+}
+
+// WorkerHeartbeat defines model for WorkerHeartbeat.
+type WorkerHeartbeat struct {
+	// Cancelled The job was cancelled, the worker should stop it.
+	Cancelled bool `json:"cancelled"`
+}
+
+// WorkerTask A leased detect job.
+type WorkerTask struct {
+	// Attempt Workers that leased the job, starting at 1.
+	Attempt int    `json:"attempt"`
+	JobId   string `json:"job_id"`
+
+	// LeaseSeconds How long the lease lasts without a heartbeat.
+	LeaseSeconds int `json:"lease_seconds"`
+
+	// Request Detect job as queued for a worker.
+	Request DetectRequest `json:"request"`
+
+	// SourceUrl Path to download the source from when it's a file on the API server.
+	SourceUrl *string `json:"source_url,omitempty"`
+
+	// Trace W3C trace context of the request that submitted the job.
+	Trace *map[string]string `json:"trace,omitempty"`
+}
+
+// CompareEvaluationsParams defines parameters for CompareEvaluations.
+type CompareEvaluationsParams struct {
+	// Dataset Only evaluations on this dataset.
+	Dataset *string                        `form:"dataset,omitempty" json:"dataset,omitempty"`
+	Split   *CompareEvaluationsParamsSplit `form:"split,omitempty" json:"split,omitempty"`
+
+	// Format html renders the table as a page.
+	Format *CompareEvaluationsParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// CompareEvaluationsParamsSplit defines parameters for CompareEvaluations.
+type CompareEvaluationsParamsSplit string
+
+// CompareEvaluationsParamsFormat defines parameters for CompareEvaluations.
+type CompareEvaluationsParamsFormat string
+
+// ExportJobsParams defines parameters for ExportJobs.
+type ExportJobsParams struct {
+	// Jobs Comma separated job ids.
+	Jobs string `form:"jobs" json:"jobs"`
+
+	// Format Comma separated export formats, all of them when empty.
+	Format *string `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// ListJobsParams defines parameters for ListJobs.
+type ListJobsParams struct {
+	// Limit Maximum number of jobs, 0 lists all.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// StreamJobEventsParams defines parameters for StreamJobEvents.
+type StreamJobEventsParams struct {
+	// After Resume after this event.
+	After  *int                         `form:"after,omitempty" json:"after,omitempty"`
+	Format *StreamJobEventsParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// LastEventID Resume after this event.
+	LastEventID *int `json:"Last-Event-ID,omitempty"`
+}
+
+// StreamJobEventsParamsFormat defines parameters for StreamJobEvents.
+type StreamJobEventsParamsFormat string
+
+// ExportJobParams defines parameters for ExportJob.
+type ExportJobParams struct {
+	// Format Comma separated export formats, all of them when empty.
+	Format *string `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetJobLogParams defines parameters for GetJobLog.
+type GetJobLogParams struct {
+	// Level Drop lines below this level.
+	Level *GetJobLogParamsLevel `form:"level,omitempty" json:"level,omitempty"`
+}
+
+// GetJobLogParamsLevel defines parameters for GetJobLog.
+type GetJobLogParamsLevel string
+
+// LabelStudioPredictJSONBody defines parameters for LabelStudioPredict.
+type LabelStudioPredictJSONBody map[string]interface{}
+
+// LabelStudioSetupJSONBody defines parameters for LabelStudioSetup.
+type LabelStudioSetupJSONBody map[string]interface{}
+
+// LabelStudioWebhookJSONBody defines parameters for LabelStudioWebhook.
+type LabelStudioWebhookJSONBody map[string]interface{}
+
+// StreamTrainingEventsParams defines parameters for StreamTrainingEvents.
+type StreamTrainingEventsParams struct {
+	// After Resume after this epoch.
+	After *int `form:"after,omitempty" json:"after,omitempty"`
+
+	// LastEventID Resume after this epoch.
+	LastEventID *int `json:"Last-Event-ID,omitempty"`
+}
+
+// RegisterTrainingJSONBody defines parameters for RegisterTraining.
+type RegisterTrainingJSONBody struct {
+	// Name Model name, the name of the training request by default.
+	Name *string `json:"name,omitempty"`
+}
+
+// PutWorkerJobArtifactsParams defines parameters for PutWorkerJobArtifacts.
+type PutWorkerJobArtifactsParams struct {
+	// XWorkerID Id of the worker, the lease holder.
+	XWorkerID string `json:"X-Worker-ID"`
+
+	// XWorkerJobDir Job dir on the worker, paths in job.json under it are moved to the job dir here.
+	XWorkerJobDir *string `json:"X-Worker-Job-Dir,omitempty"`
+}
+
+// CompleteWorkerJobParams defines parameters for CompleteWorkerJob.
+type CompleteWorkerJobParams struct {
+	// XWorkerID Id of the worker, the lease holder.
+	XWorkerID string `json:"X-Worker-ID"`
+}
+
+// PostWorkerJobEventsParams defines parameters for PostWorkerJobEvents.
+type PostWorkerJobEventsParams struct {
+	// XWorkerID Id of the worker, the lease holder.
+	XWorkerID string `json:"X-Worker-ID"`
+}
+
+// WorkerHeartbeatParams defines parameters for WorkerHeartbeat.
+type WorkerHeartbeatParams struct {
+	// XWorkerID Id of the worker, the lease holder.
+	XWorkerID string `json:"X-Worker-ID"`
+}
+
+// GetWorkerJobSourceParams defines parameters for GetWorkerJobSource.
+type GetWorkerJobSourceParams struct {
+	// XWorkerID Id of the worker, the lease holder.
+	XWorkerID string `json:"X-Worker-ID"`
+}
+
+// LeaseWorkerJobParams defines parameters for LeaseWorkerJob.
+type LeaseWorkerJobParams struct {
+	// XWorkerID Id of the worker, the lease holder.
+	XWorkerID string `json:"X-Worker-ID"`
+}
+
+// RunActiveLearningJSONRequestBody defines body for RunActiveLearning for application/json ContentType.
+type RunActiveLearningJSONRequestBody = ActiveLearningRequest
+
+// CreateJobJSONRequestBody defines body for CreateJob for application/json ContentType.
+type CreateJobJSONRequestBody = JobRequest
+
+// CreateJobFormdataRequestBody defines body for CreateJob for application/x-www-form-urlencoded ContentType.
+type CreateJobFormdataRequestBody = JobRequest
+
+// CreateJobMultipartRequestBody defines body for CreateJob for multipart/form-data ContentType.
+type CreateJobMultipartRequestBody = JobUpload
+
+// CreateEvaluateJobJSONRequestBody defines body for CreateEvaluateJob for application/json ContentType.
+type CreateEvaluateJobJSONRequestBody = EvaluationRequest
+
+// CreateFramesJobJSONRequestBody defines body for CreateFramesJob for application/json ContentType.
+type CreateFramesJobJSONRequestBody = FrameExtractionRequest
+
+// CutHighlightsJSONRequestBody defines body for CutHighlights for application/json ContentType.
+type CutHighlightsJSONRequestBody = HighlightOptions
+
+// LabelStudioPredictJSONRequestBody defines body for LabelStudioPredict for application/json ContentType.
+type LabelStudioPredictJSONRequestBody LabelStudioPredictJSONBody
+
+// LabelStudioSetupJSONRequestBody defines body for LabelStudioSetup for application/json ContentType.
+type LabelStudioSetupJSONRequestBody LabelStudioSetupJSONBody
+
+// LabelStudioWebhookJSONRequestBody defines body for LabelStudioWebhook for application/json ContentType.
+type LabelStudioWebhookJSONRequestBody LabelStudioWebhookJSONBody
+
+// CreateRuleSetJSONRequestBody defines body for CreateRuleSet for application/json ContentType.
+type CreateRuleSetJSONRequestBody = RuleSet
+
+// UpdateRuleSetJSONRequestBody defines body for UpdateRuleSet for application/json ContentType.
+type UpdateRuleSetJSONRequestBody = RuleSet
+
+// StartStreamJSONRequestBody defines body for StartStream for application/json ContentType.
+type StartStreamJSONRequestBody = StreamConfig
+
+// StartTrainingJSONRequestBody defines body for StartTraining for application/json ContentType.
+type StartTrainingJSONRequestBody = TrainingRequest
+
+// RegisterTrainingJSONRequestBody defines body for RegisterTraining for application/json ContentType.
+type RegisterTrainingJSONRequestBody RegisterTrainingJSONBody
+
+// CompleteWorkerJobJSONRequestBody defines body for CompleteWorkerJob for application/json ContentType.
+type CompleteWorkerJobJSONRequestBody = WorkerCompletion
+
+// PostWorkerJobEventsJSONRequestBody defines body for PostWorkerJobEvents for application/json ContentType.
+type PostWorkerJobEventsJSONRequestBody = WorkerEvents
+
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Doer performs HTTP requests.
+//
+// The standard http.Client implements this interface.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client which conforms to the OpenAPI3 specification for this service.
+type Client struct {
+	// The endpoint of the server conforming to this interface, with scheme,
+	// https://api.deepmap.com for example. This can contain a path relative
+	// to the server, such as https://api.deepmap.com/dev-test, and all the
+	// paths in the swagger spec will be appended to the server.
+	Server string
+
+	// Doer for performing requests, typically a *http.Client with any
+	// customized settings, such as certificate chains.
+	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
+}
+
+// ClientOption allows setting custom parameters during construction
+type ClientOption func(*Client) error
+
+// Creates a new Client, with reasonable defaults
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	// create a client with sane default values
+	client := Client{
+		Server: server,
+	}
+	// mutate client and add all optional params
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	// ensure the server URL always has a trailing slash
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+	// create httpClient, if not already present
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	return &client, nil
+}
+
+// WithHTTPClient allows overriding the default Doer, which is
+// automatically created using http.Client. This is useful for tests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// The interface specification for the client above.
+type ClientInterface interface {
+	// Index request
+	Index(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// RunActiveLearningWithBody request with any body
+	RunActiveLearningWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	RunActiveLearning(ctx context.Context, body RunActiveLearningJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ClearCache request
+	ClearCache(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetCacheStats request
+	GetCacheStats(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DetectSocket request
+	DetectSocket(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Docs request
+	Docs(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CompareEvaluations request
+	CompareEvaluations(ctx context.Context, params *CompareEvaluationsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ExportJobs request
+	ExportJobs(ctx context.Context, params *ExportJobsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListJobs request
+	ListJobs(ctx context.Context, params *ListJobsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CreateJobWithBody request with any body
+	CreateJobWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CreateJob(ctx context.Context, body CreateJobJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CreateJobWithFormdataBody(ctx context.Context, body CreateJobFormdataRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CreateEvaluateJobWithBody request with any body
+	CreateEvaluateJobWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CreateEvaluateJob(ctx context.Context, body CreateEvaluateJobJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CreateFramesJobWithBody request with any body
+	CreateFramesJobWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CreateFramesJob(ctx context.Context, body CreateFramesJobJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetJob request
+	GetJob(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CancelJob request
+	CancelJob(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DownloadDataset request
+	DownloadDataset(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// StreamJobEvents request
+	StreamJobEvents(ctx context.Context, id string, params *StreamJobEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ExportJob request
+	ExportJob(ctx context.Context, id string, params *ExportJobParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CutHighlightsWithBody request with any body
+	CutHighlightsWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CutHighlights(ctx context.Context, id string, body CutHighlightsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetJobLog request
+	GetJobLog(ctx context.Context, id string, params *GetJobLogParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// EvaluateJobRules request
+	EvaluateJobRules(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// LabelStudioHealth request
+	LabelStudioHealth(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// LabelStudioPredictWithBody request with any body
+	LabelStudioPredictWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	LabelStudioPredict(ctx context.Context, body LabelStudioPredictJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// LabelStudioSetupWithBody request with any body
+	LabelStudioSetupWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	LabelStudioSetup(ctx context.Context, body LabelStudioSetupJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// LabelStudioWebhookWithBody request with any body
+	LabelStudioWebhookWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	LabelStudioWebhook(ctx context.Context, body LabelStudioWebhookJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListModels request
+	ListModels(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetOpenAPI request
+	GetOpenAPI(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListProfiles request
+	ListProfiles(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListRuleSets request
+	ListRuleSets(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CreateRuleSetWithBody request with any body
+	CreateRuleSetWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CreateRuleSet(ctx context.Context, body CreateRuleSetJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeleteRuleSet request
+	DeleteRuleSet(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// UpdateRuleSetWithBody request with any body
+	UpdateRuleSetWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	UpdateRuleSet(ctx context.Context, id string, body UpdateRuleSetJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetStaticFile request
+	GetStaticFile(ctx context.Context, path string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListStreams request
+	ListStreams(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// StartStreamWithBody request with any body
+	StartStreamWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	StartStream(ctx context.Context, body StartStreamJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// StopStream request
+	StopStream(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetStream request
+	GetStream(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListTrainings request
+	ListTrainings(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// StartTrainingWithBody request with any body
+	StartTrainingWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	StartTraining(ctx context.Context, body StartTrainingJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetTraining request
+	GetTraining(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CancelTraining request
+	CancelTraining(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// StreamTrainingEvents request
+	StreamTrainingEvents(ctx context.Context, id string, params *StreamTrainingEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// RegisterTrainingWithBody request with any body
+	RegisterTrainingWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	RegisterTraining(ctx context.Context, id string, body RegisterTrainingJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ResumeTraining request
+	ResumeTraining(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PutWorkerJobArtifactsWithBody request with any body
+	PutWorkerJobArtifactsWithBody(ctx context.Context, id string, params *PutWorkerJobArtifactsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CompleteWorkerJobWithBody request with any body
+	CompleteWorkerJobWithBody(ctx context.Context, id string, params *CompleteWorkerJobParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CompleteWorkerJob(ctx context.Context, id string, params *CompleteWorkerJobParams, body CompleteWorkerJobJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostWorkerJobEventsWithBody request with any body
+	PostWorkerJobEventsWithBody(ctx context.Context, id string, params *PostWorkerJobEventsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostWorkerJobEvents(ctx context.Context, id string, params *PostWorkerJobEventsParams, body PostWorkerJobEventsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// WorkerHeartbeat request
+	WorkerHeartbeat(ctx context.Context, id string, params *WorkerHeartbeatParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetWorkerJobSource request
+	GetWorkerJobSource(ctx context.Context, id string, params *GetWorkerJobSourceParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// LeaseWorkerJob request
+	LeaseWorkerJob(ctx context.Context, params *LeaseWorkerJobParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) Index(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewIndexRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) RunActiveLearningWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRunActiveLearningRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) RunActiveLearning(ctx context.Context, body RunActiveLearningJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRunActiveLearningRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ClearCache(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewClearCacheRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetCacheStats(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetCacheStatsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DetectSocket(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDetectSocketRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) Docs(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDocsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CompareEvaluations(ctx context.Context, params *CompareEvaluationsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCompareEvaluationsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ExportJobs(ctx context.Context, params *ExportJobsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewExportJobsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListJobs(ctx context.Context, params *ListJobsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListJobsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateJobWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateJobRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateJob(ctx context.Context, body CreateJobJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateJobRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateJobWithFormdataBody(ctx context.Context, body CreateJobFormdataRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateJobRequestWithFormdataBody(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateEvaluateJobWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateEvaluateJobRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateEvaluateJob(ctx context.Context, body CreateEvaluateJobJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateEvaluateJobRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateFramesJobWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateFramesJobRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateFramesJob(ctx context.Context, body CreateFramesJobJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateFramesJobRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetJob(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetJobRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CancelJob(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCancelJobRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DownloadDataset(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDownloadDatasetRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) StreamJobEvents(ctx context.Context, id string, params *StreamJobEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewStreamJobEventsRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ExportJob(ctx context.Context, id string, params *ExportJobParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewExportJobRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CutHighlightsWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCutHighlightsRequestWithBody(c.Server, id, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CutHighlights(ctx context.Context, id string, body CutHighlightsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCutHighlightsRequest(c.Server, id, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetJobLog(ctx context.Context, id string, params *GetJobLogParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetJobLogRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) EvaluateJobRules(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewEvaluateJobRulesRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) LabelStudioHealth(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewLabelStudioHealthRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) LabelStudioPredictWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewLabelStudioPredictRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) LabelStudioPredict(ctx context.Context, body LabelStudioPredictJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewLabelStudioPredictRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) LabelStudioSetupWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewLabelStudioSetupRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) LabelStudioSetup(ctx context.Context, body LabelStudioSetupJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewLabelStudioSetupRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) LabelStudioWebhookWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewLabelStudioWebhookRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) LabelStudioWebhook(ctx context.Context, body LabelStudioWebhookJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewLabelStudioWebhookRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListModels(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListModelsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetOpenAPI(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetOpenAPIRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListProfiles(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListProfilesRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListRuleSets(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListRuleSetsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateRuleSetWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateRuleSetRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateRuleSet(ctx context.Context, body CreateRuleSetJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateRuleSetRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DeleteRuleSet(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteRuleSetRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UpdateRuleSetWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateRuleSetRequestWithBody(c.Server, id, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UpdateRuleSet(ctx context.Context, id string, body UpdateRuleSetJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateRuleSetRequest(c.Server, id, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetStaticFile(ctx context.Context, path string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetStaticFileRequest(c.Server, path)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListStreams(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListStreamsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) StartStreamWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewStartStreamRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) StartStream(ctx context.Context, body StartStreamJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewStartStreamRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) StopStream(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewStopStreamRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetStream(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetStreamRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListTrainings(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListTrainingsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) StartTrainingWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewStartTrainingRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) StartTraining(ctx context.Context, body StartTrainingJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewStartTrainingRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetTraining(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetTrainingRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CancelTraining(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCancelTrainingRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) StreamTrainingEvents(ctx context.Context, id string, params *StreamTrainingEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewStreamTrainingEventsRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) RegisterTrainingWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRegisterTrainingRequestWithBody(c.Server, id, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) RegisterTraining(ctx context.Context, id string, body RegisterTrainingJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRegisterTrainingRequest(c.Server, id, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ResumeTraining(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewResumeTrainingRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PutWorkerJobArtifactsWithBody(ctx context.Context, id string, params *PutWorkerJobArtifactsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPutWorkerJobArtifactsRequestWithBody(c.Server, id, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CompleteWorkerJobWithBody(ctx context.Context, id string, params *CompleteWorkerJobParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCompleteWorkerJobRequestWithBody(c.Server, id, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CompleteWorkerJob(ctx context.Context, id string, params *CompleteWorkerJobParams, body CompleteWorkerJobJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCompleteWorkerJobRequest(c.Server, id, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostWorkerJobEventsWithBody(ctx context.Context, id string, params *PostWorkerJobEventsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostWorkerJobEventsRequestWithBody(c.Server, id, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostWorkerJobEvents(ctx context.Context, id string, params *PostWorkerJobEventsParams, body PostWorkerJobEventsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostWorkerJobEventsRequest(c.Server, id, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) WorkerHeartbeat(ctx context.Context, id string, params *WorkerHeartbeatParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewWorkerHeartbeatRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetWorkerJobSource(ctx context.Context, id string, params *GetWorkerJobSourceParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetWorkerJobSourceRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) LeaseWorkerJob(ctx context.Context, params *LeaseWorkerJobParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewLeaseWorkerJobRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewIndexRequest generates requests for Index
+func NewIndexRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewRunActiveLearningRequest calls the generic RunActiveLearning builder with application/json body
+func NewRunActiveLearningRequest(server string, body RunActiveLearningJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewRunActiveLearningRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewRunActiveLearningRequestWithBody generates requests for RunActiveLearning with any type of body
+func NewRunActiveLearningRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/active-learning")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewClearCacheRequest generates requests for ClearCache
+func NewClearCacheRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/cache")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetCacheStatsRequest generates requests for GetCacheStats
+func NewGetCacheStatsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/cache")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewDetectSocketRequest generates requests for DetectSocket
+func NewDetectSocketRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/detect")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewDocsRequest generates requests for Docs
+func NewDocsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/docs")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewCompareEvaluationsRequest generates requests for CompareEvaluations
+func NewCompareEvaluationsRequest(server string, params *CompareEvaluationsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/evaluations")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Dataset != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dataset", runtime.ParamLocationQuery, *params.Dataset); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Split != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "split", runtime.ParamLocationQuery, *params.Split); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Format != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "format", runtime.ParamLocationQuery, *params.Format); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewExportJobsRequest generates requests for ExportJobs
+func NewExportJobsRequest(server string, params *ExportJobsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/export")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "jobs", runtime.ParamLocationQuery, params.Jobs); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if params.Format != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "format", runtime.ParamLocationQuery, *params.Format); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListJobsRequest generates requests for ListJobs
+func NewListJobsRequest(server string, params *ListJobsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jobs")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewCreateJobRequest calls the generic CreateJob builder with application/json body
+func NewCreateJobRequest(server string, body CreateJobJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCreateJobRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewCreateJobRequestWithFormdataBody calls the generic CreateJob builder with application/x-www-form-urlencoded body
+func NewCreateJobRequestWithFormdataBody(server string, body CreateJobFormdataRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	bodyStr, err := runtime.MarshalForm(body, nil)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = strings.NewReader(bodyStr.Encode())
+	return NewCreateJobRequestWithBody(server, "application/x-www-form-urlencoded", bodyReader)
+}
+
+// NewCreateJobRequestWithBody generates requests for CreateJob with any type of body
+func NewCreateJobRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jobs")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewCreateEvaluateJobRequest calls the generic CreateEvaluateJob builder with application/json body
+func NewCreateEvaluateJobRequest(server string, body CreateEvaluateJobJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCreateEvaluateJobRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewCreateEvaluateJobRequestWithBody generates requests for CreateEvaluateJob with any type of body
+func NewCreateEvaluateJobRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jobs/evaluate")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewCreateFramesJobRequest calls the generic CreateFramesJob builder with application/json body
+func NewCreateFramesJobRequest(server string, body CreateFramesJobJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCreateFramesJobRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewCreateFramesJobRequestWithBody generates requests for CreateFramesJob with any type of body
+func NewCreateFramesJobRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jobs/frames")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetJobRequest generates requests for GetJob
+func NewGetJobRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jobs/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewCancelJobRequest generates requests for CancelJob
+func NewCancelJobRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jobs/%s/cancel", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewDownloadDatasetRequest generates requests for DownloadDataset
+func NewDownloadDatasetRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jobs/%s/dataset", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewStreamJobEventsRequest generates requests for StreamJobEvents
+func NewStreamJobEventsRequest(server string, id string, params *StreamJobEventsParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jobs/%s/events", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.After != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "after", runtime.ParamLocationQuery, *params.After); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Format != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "format", runtime.ParamLocationQuery, *params.Format); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.LastEventID != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Last-Event-ID", runtime.ParamLocationHeader, *params.LastEventID)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Last-Event-ID", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewExportJobRequest generates requests for ExportJob
+func NewExportJobRequest(server string, id string, params *ExportJobParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jobs/%s/export", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Format != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "format", runtime.ParamLocationQuery, *params.Format); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewCutHighlightsRequest calls the generic CutHighlights builder with application/json body
+func NewCutHighlightsRequest(server string, id string, body CutHighlightsJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCutHighlightsRequestWithBody(server, id, "application/json", bodyReader)
+}
+
+// NewCutHighlightsRequestWithBody generates requests for CutHighlights with any type of body
+func NewCutHighlightsRequestWithBody(server string, id string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jobs/%s/highlights", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetJobLogRequest generates requests for GetJobLog
+func NewGetJobLogRequest(server string, id string, params *GetJobLogParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jobs/%s/logs", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Level != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "level", runtime.ParamLocationQuery, *params.Level); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewEvaluateJobRulesRequest generates requests for EvaluateJobRules
+func NewEvaluateJobRulesRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jobs/%s/rules", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewLabelStudioHealthRequest generates requests for LabelStudioHealth
+func NewLabelStudioHealthRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/labelstudio/health")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewLabelStudioPredictRequest calls the generic LabelStudioPredict builder with application/json body
+func NewLabelStudioPredictRequest(server string, body LabelStudioPredictJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewLabelStudioPredictRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewLabelStudioPredictRequestWithBody generates requests for LabelStudioPredict with any type of body
+func NewLabelStudioPredictRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/labelstudio/predict")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewLabelStudioSetupRequest calls the generic LabelStudioSetup builder with application/json body
+func NewLabelStudioSetupRequest(server string, body LabelStudioSetupJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewLabelStudioSetupRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewLabelStudioSetupRequestWithBody generates requests for LabelStudioSetup with any type of body
+func NewLabelStudioSetupRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/labelstudio/setup")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewLabelStudioWebhookRequest calls the generic LabelStudioWebhook builder with application/json body
+func NewLabelStudioWebhookRequest(server string, body LabelStudioWebhookJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewLabelStudioWebhookRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewLabelStudioWebhookRequestWithBody generates requests for LabelStudioWebhook with any type of body
+func NewLabelStudioWebhookRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/labelstudio/webhook")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewListModelsRequest generates requests for ListModels
+func NewListModelsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/models")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetOpenAPIRequest generates requests for GetOpenAPI
+func NewGetOpenAPIRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/openapi.json")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListProfilesRequest generates requests for ListProfiles
+func NewListProfilesRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/profiles")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListRuleSetsRequest generates requests for ListRuleSets
+func NewListRuleSetsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/rules")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewCreateRuleSetRequest calls the generic CreateRuleSet builder with application/json body
+func NewCreateRuleSetRequest(server string, body CreateRuleSetJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCreateRuleSetRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewCreateRuleSetRequestWithBody generates requests for CreateRuleSet with any type of body
+func NewCreateRuleSetRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/rules")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewDeleteRuleSetRequest generates requests for DeleteRuleSet
+func NewDeleteRuleSetRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/rules/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewUpdateRuleSetRequest calls the generic UpdateRuleSet builder with application/json body
+func NewUpdateRuleSetRequest(server string, id string, body UpdateRuleSetJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewUpdateRuleSetRequestWithBody(server, id, "application/json", bodyReader)
+}
+
+// NewUpdateRuleSetRequestWithBody generates requests for UpdateRuleSet with any type of body
+func NewUpdateRuleSetRequestWithBody(server string, id string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/rules/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetStaticFileRequest generates requests for GetStaticFile
+func NewGetStaticFileRequest(server string, path string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "path", runtime.ParamLocationPath, path)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/static/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListStreamsRequest generates requests for ListStreams
+func NewListStreamsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/streams")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewStartStreamRequest calls the generic StartStream builder with application/json body
+func NewStartStreamRequest(server string, body StartStreamJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewStartStreamRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewStartStreamRequestWithBody generates requests for StartStream with any type of body
+func NewStartStreamRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/streams")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewStopStreamRequest generates requests for StopStream
+func NewStopStreamRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/streams/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetStreamRequest generates requests for GetStream
+func NewGetStreamRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/streams/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListTrainingsRequest generates requests for ListTrainings
+func NewListTrainingsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/trainings")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewStartTrainingRequest calls the generic StartTraining builder with application/json body
+func NewStartTrainingRequest(server string, body StartTrainingJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewStartTrainingRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewStartTrainingRequestWithBody generates requests for StartTraining with any type of body
+func NewStartTrainingRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/trainings")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetTrainingRequest generates requests for GetTraining
+func NewGetTrainingRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/trainings/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewCancelTrainingRequest generates requests for CancelTraining
+func NewCancelTrainingRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/trainings/%s/cancel", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewStreamTrainingEventsRequest generates requests for StreamTrainingEvents
+func NewStreamTrainingEventsRequest(server string, id string, params *StreamTrainingEventsParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/trainings/%s/events", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.After != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "after", runtime.ParamLocationQuery, *params.After); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.LastEventID != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Last-Event-ID", runtime.ParamLocationHeader, *params.LastEventID)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Last-Event-ID", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewRegisterTrainingRequest calls the generic RegisterTraining builder with application/json body
+func NewRegisterTrainingRequest(server string, id string, body RegisterTrainingJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewRegisterTrainingRequestWithBody(server, id, "application/json", bodyReader)
+}
+
+// NewRegisterTrainingRequestWithBody generates requests for RegisterTraining with any type of body
+func NewRegisterTrainingRequestWithBody(server string, id string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/trainings/%s/register", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewResumeTrainingRequest generates requests for ResumeTraining
+func NewResumeTrainingRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/trainings/%s/resume", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPutWorkerJobArtifactsRequestWithBody generates requests for PutWorkerJobArtifacts with any type of body
+func NewPutWorkerJobArtifactsRequestWithBody(server string, id string, params *PutWorkerJobArtifactsParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/workers/jobs/%s/artifacts", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		var headerParam0 string
+
+		headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Worker-ID", runtime.ParamLocationHeader, params.XWorkerID)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("X-Worker-ID", headerParam0)
+
+		if params.XWorkerJobDir != nil {
+			var headerParam1 string
+
+			headerParam1, err = runtime.StyleParamWithLocation("simple", false, "X-Worker-Job-Dir", runtime.ParamLocationHeader, *params.XWorkerJobDir)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Worker-Job-Dir", headerParam1)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewCompleteWorkerJobRequest calls the generic CompleteWorkerJob builder with application/json body
+func NewCompleteWorkerJobRequest(server string, id string, params *CompleteWorkerJobParams, body CompleteWorkerJobJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCompleteWorkerJobRequestWithBody(server, id, params, "application/json", bodyReader)
+}
+
+// NewCompleteWorkerJobRequestWithBody generates requests for CompleteWorkerJob with any type of body
+func NewCompleteWorkerJobRequestWithBody(server string, id string, params *CompleteWorkerJobParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/workers/jobs/%s/complete", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		var headerParam0 string
+
+		headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Worker-ID", runtime.ParamLocationHeader, params.XWorkerID)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("X-Worker-ID", headerParam0)
+
+	}
+
+	return req, nil
+}
+
+// NewPostWorkerJobEventsRequest calls the generic PostWorkerJobEvents builder with application/json body
+func NewPostWorkerJobEventsRequest(server string, id string, params *PostWorkerJobEventsParams, body PostWorkerJobEventsJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostWorkerJobEventsRequestWithBody(server, id, params, "application/json", bodyReader)
+}
+
+// NewPostWorkerJobEventsRequestWithBody generates requests for PostWorkerJobEvents with any type of body
+func NewPostWorkerJobEventsRequestWithBody(server string, id string, params *PostWorkerJobEventsParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/workers/jobs/%s/events", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		var headerParam0 string
+
+		headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Worker-ID", runtime.ParamLocationHeader, params.XWorkerID)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("X-Worker-ID", headerParam0)
+
+	}
+
+	return req, nil
+}
+
+// NewWorkerHeartbeatRequest generates requests for WorkerHeartbeat
+func NewWorkerHeartbeatRequest(server string, id string, params *WorkerHeartbeatParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/workers/jobs/%s/heartbeat", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		var headerParam0 string
+
+		headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Worker-ID", runtime.ParamLocationHeader, params.XWorkerID)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("X-Worker-ID", headerParam0)
+
+	}
+
+	return req, nil
+}
+
+// NewGetWorkerJobSourceRequest generates requests for GetWorkerJobSource
+func NewGetWorkerJobSourceRequest(server string, id string, params *GetWorkerJobSourceParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/workers/jobs/%s/source", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		var headerParam0 string
+
+		headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Worker-ID", runtime.ParamLocationHeader, params.XWorkerID)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("X-Worker-ID", headerParam0)
+
+	}
+
+	return req, nil
+}
+
+// NewLeaseWorkerJobRequest generates requests for LeaseWorkerJob
+func NewLeaseWorkerJobRequest(server string, params *LeaseWorkerJobParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/workers/lease")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		var headerParam0 string
+
+		headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Worker-ID", runtime.ParamLocationHeader, params.XWorkerID)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("X-Worker-ID", headerParam0)
+
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// IndexWithResponse request
+	IndexWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*IndexResponse, error)
+
+	// RunActiveLearningWithBodyWithResponse request with any body
+	RunActiveLearningWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*RunActiveLearningResponse, error)
+
+	RunActiveLearningWithResponse(ctx context.Context, body RunActiveLearningJSONRequestBody, reqEditors ...RequestEditorFn) (*RunActiveLearningResponse, error)
+
+	// ClearCacheWithResponse request
+	ClearCacheWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ClearCacheResponse, error)
+
+	// GetCacheStatsWithResponse request
+	GetCacheStatsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetCacheStatsResponse, error)
+
+	// DetectSocketWithResponse request
+	DetectSocketWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DetectSocketResponse, error)
+
+	// DocsWithResponse request
+	DocsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DocsResponse, error)
+
+	// CompareEvaluationsWithResponse request
+	CompareEvaluationsWithResponse(ctx context.Context, params *CompareEvaluationsParams, reqEditors ...RequestEditorFn) (*CompareEvaluationsResponse, error)
+
+	// ExportJobsWithResponse request
+	ExportJobsWithResponse(ctx context.Context, params *ExportJobsParams, reqEditors ...RequestEditorFn) (*ExportJobsResponse, error)
+
+	// ListJobsWithResponse request
+	ListJobsWithResponse(ctx context.Context, params *ListJobsParams, reqEditors ...RequestEditorFn) (*ListJobsResponse, error)
+
+	// CreateJobWithBodyWithResponse request with any body
+	CreateJobWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateJobResponse, error)
+
+	CreateJobWithResponse(ctx context.Context, body CreateJobJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateJobResponse, error)
+
+	CreateJobWithFormdataBodyWithResponse(ctx context.Context, body CreateJobFormdataRequestBody, reqEditors ...RequestEditorFn) (*CreateJobResponse, error)
+
+	// CreateEvaluateJobWithBodyWithResponse request with any body
+	CreateEvaluateJobWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateEvaluateJobResponse, error)
+
+	CreateEvaluateJobWithResponse(ctx context.Context, body CreateEvaluateJobJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateEvaluateJobResponse, error)
+
+	// CreateFramesJobWithBodyWithResponse request with any body
+	CreateFramesJobWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateFramesJobResponse, error)
+
+	CreateFramesJobWithResponse(ctx context.Context, body CreateFramesJobJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateFramesJobResponse, error)
+
+	// GetJobWithResponse request
+	GetJobWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetJobResponse, error)
+
+	// CancelJobWithResponse request
+	CancelJobWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*CancelJobResponse, error)
+
+	// DownloadDatasetWithResponse request
+	DownloadDatasetWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*DownloadDatasetResponse, error)
+
+	// StreamJobEventsWithResponse request
+	StreamJobEventsWithResponse(ctx context.Context, id string, params *StreamJobEventsParams, reqEditors ...RequestEditorFn) (*StreamJobEventsResponse, error)
+
+	// ExportJobWithResponse request
+	ExportJobWithResponse(ctx context.Context, id string, params *ExportJobParams, reqEditors ...RequestEditorFn) (*ExportJobResponse, error)
+
+	// CutHighlightsWithBodyWithResponse request with any body
+	CutHighlightsWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CutHighlightsResponse, error)
+
+	CutHighlightsWithResponse(ctx context.Context, id string, body CutHighlightsJSONRequestBody, reqEditors ...RequestEditorFn) (*CutHighlightsResponse, error)
+
+	// GetJobLogWithResponse request
+	GetJobLogWithResponse(ctx context.Context, id string, params *GetJobLogParams, reqEditors ...RequestEditorFn) (*GetJobLogResponse, error)
+
+	// EvaluateJobRulesWithResponse request
+	EvaluateJobRulesWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*EvaluateJobRulesResponse, error)
+
+	// LabelStudioHealthWithResponse request
+	LabelStudioHealthWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*LabelStudioHealthResponse, error)
+
+	// LabelStudioPredictWithBodyWithResponse request with any body
+	LabelStudioPredictWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*LabelStudioPredictResponse, error)
+
+	LabelStudioPredictWithResponse(ctx context.Context, body LabelStudioPredictJSONRequestBody, reqEditors ...RequestEditorFn) (*LabelStudioPredictResponse, error)
+
+	// LabelStudioSetupWithBodyWithResponse request with any body
+	LabelStudioSetupWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*LabelStudioSetupResponse, error)
+
+	LabelStudioSetupWithResponse(ctx context.Context, body LabelStudioSetupJSONRequestBody, reqEditors ...RequestEditorFn) (*LabelStudioSetupResponse, error)
+
+	// LabelStudioWebhookWithBodyWithResponse request with any body
+	LabelStudioWebhookWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*LabelStudioWebhookResponse, error)
+
+	LabelStudioWebhookWithResponse(ctx context.Context, body LabelStudioWebhookJSONRequestBody, reqEditors ...RequestEditorFn) (*LabelStudioWebhookResponse, error)
+
+	// ListModelsWithResponse request
+	ListModelsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListModelsResponse, error)
+
+	// GetOpenAPIWithResponse request
+	GetOpenAPIWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetOpenAPIResponse, error)
+
+	// ListProfilesWithResponse request
+	ListProfilesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListProfilesResponse, error)
+
+	// ListRuleSetsWithResponse request
+	ListRuleSetsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListRuleSetsResponse, error)
+
+	// CreateRuleSetWithBodyWithResponse request with any body
+	CreateRuleSetWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateRuleSetResponse, error)
+
+	CreateRuleSetWithResponse(ctx context.Context, body CreateRuleSetJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateRuleSetResponse, error)
+
+	// DeleteRuleSetWithResponse request
+	DeleteRuleSetWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*DeleteRuleSetResponse, error)
+
+	// UpdateRuleSetWithBodyWithResponse request with any body
+	UpdateRuleSetWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateRuleSetResponse, error)
+
+	UpdateRuleSetWithResponse(ctx context.Context, id string, body UpdateRuleSetJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateRuleSetResponse, error)
+
+	// GetStaticFileWithResponse request
+	GetStaticFileWithResponse(ctx context.Context, path string, reqEditors ...RequestEditorFn) (*GetStaticFileResponse, error)
+
+	// ListStreamsWithResponse request
+	ListStreamsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListStreamsResponse, error)
+
+	// StartStreamWithBodyWithResponse request with any body
+	StartStreamWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*StartStreamResponse, error)
+
+	StartStreamWithResponse(ctx context.Context, body StartStreamJSONRequestBody, reqEditors ...RequestEditorFn) (*StartStreamResponse, error)
+
+	// StopStreamWithResponse request
+	StopStreamWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*StopStreamResponse, error)
+
+	// GetStreamWithResponse request
+	GetStreamWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetStreamResponse, error)
+
+	// ListTrainingsWithResponse request
+	ListTrainingsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListTrainingsResponse, error)
+
+	// StartTrainingWithBodyWithResponse request with any body
+	StartTrainingWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*StartTrainingResponse, error)
+
+	StartTrainingWithResponse(ctx context.Context, body StartTrainingJSONRequestBody, reqEditors ...RequestEditorFn) (*StartTrainingResponse, error)
+
+	// GetTrainingWithResponse request
+	GetTrainingWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetTrainingResponse, error)
+
+	// CancelTrainingWithResponse request
+	CancelTrainingWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*CancelTrainingResponse, error)
+
+	// StreamTrainingEventsWithResponse request
+	StreamTrainingEventsWithResponse(ctx context.Context, id string, params *StreamTrainingEventsParams, reqEditors ...RequestEditorFn) (*StreamTrainingEventsResponse, error)
+
+	// RegisterTrainingWithBodyWithResponse request with any body
+	RegisterTrainingWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*RegisterTrainingResponse, error)
+
+	RegisterTrainingWithResponse(ctx context.Context, id string, body RegisterTrainingJSONRequestBody, reqEditors ...RequestEditorFn) (*RegisterTrainingResponse, error)
+
+	// ResumeTrainingWithResponse request
+	ResumeTrainingWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*ResumeTrainingResponse, error)
+
+	// PutWorkerJobArtifactsWithBodyWithResponse request with any body
+	PutWorkerJobArtifactsWithBodyWithResponse(ctx context.Context, id string, params *PutWorkerJobArtifactsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutWorkerJobArtifactsResponse, error)
+
+	// CompleteWorkerJobWithBodyWithResponse request with any body
+	CompleteWorkerJobWithBodyWithResponse(ctx context.Context, id string, params *CompleteWorkerJobParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CompleteWorkerJobResponse, error)
+
+	CompleteWorkerJobWithResponse(ctx context.Context, id string, params *CompleteWorkerJobParams, body CompleteWorkerJobJSONRequestBody, reqEditors ...RequestEditorFn) (*CompleteWorkerJobResponse, error)
+
+	// PostWorkerJobEventsWithBodyWithResponse request with any body
+	PostWorkerJobEventsWithBodyWithResponse(ctx context.Context, id string, params *PostWorkerJobEventsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostWorkerJobEventsResponse, error)
+
+	PostWorkerJobEventsWithResponse(ctx context.Context, id string, params *PostWorkerJobEventsParams, body PostWorkerJobEventsJSONRequestBody, reqEditors ...RequestEditorFn) (*PostWorkerJobEventsResponse, error)
+
+	// WorkerHeartbeatWithResponse request
+	WorkerHeartbeatWithResponse(ctx context.Context, id string, params *WorkerHeartbeatParams, reqEditors ...RequestEditorFn) (*WorkerHeartbeatResponse, error)
+
+	// GetWorkerJobSourceWithResponse request
+	GetWorkerJobSourceWithResponse(ctx context.Context, id string, params *GetWorkerJobSourceParams, reqEditors ...RequestEditorFn) (*GetWorkerJobSourceResponse, error)
+
+	// LeaseWorkerJobWithResponse request
+	LeaseWorkerJobWithResponse(ctx context.Context, params *LeaseWorkerJobParams, reqEditors ...RequestEditorFn) (*LeaseWorkerJobResponse, error)
+}
+
+type IndexResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r IndexResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r IndexResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type RunActiveLearningResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ActiveLearningResult
+}
+
+// Status returns HTTPResponse.Status
+func (r RunActiveLearningResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r RunActiveLearningResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ClearCacheResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r ClearCacheResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ClearCacheResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetCacheStatsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *CacheStats
+}
+
+// Status returns HTTPResponse.Status
+func (r GetCacheStatsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetCacheStatsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DetectSocketResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r DetectSocketResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DetectSocketResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DocsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r DocsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DocsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CompareEvaluationsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *EvaluationTable
+}
+
+// Status returns HTTPResponse.Status
+func (r CompareEvaluationsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CompareEvaluationsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ExportJobsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r ExportJobsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ExportJobsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListJobsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]Job
+}
+
+// Status returns HTTPResponse.Status
+func (r ListJobsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListJobsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CreateJobResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *JobCreated
+	JSON400      *ErrorResponse
+	JSON429      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r CreateJobResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CreateJobResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CreateEvaluateJobResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *Job
+	JSON400      *ErrorResponse
+	JSON429      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r CreateEvaluateJobResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CreateEvaluateJobResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CreateFramesJobResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *Job
+}
+
+// Status returns HTTPResponse.Status
+func (r CreateFramesJobResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CreateFramesJobResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetJobResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Job
+}
+
+// Status returns HTTPResponse.Status
+func (r GetJobResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetJobResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CancelJobResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r CancelJobResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CancelJobResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DownloadDatasetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r DownloadDatasetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DownloadDatasetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type StreamJobEventsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r StreamJobEventsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r StreamJobEventsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ExportJobResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r ExportJobResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ExportJobResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CutHighlightsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Job
+}
+
+// Status returns HTTPResponse.Status
+func (r CutHighlightsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CutHighlightsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetJobLogResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r GetJobLogResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetJobLogResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type EvaluateJobRulesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]RuleEvent
+}
+
+// Status returns HTTPResponse.Status
+func (r EvaluateJobRulesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r EvaluateJobRulesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type LabelStudioHealthResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *map[string]interface{}
+}
+
+// Status returns HTTPResponse.Status
+func (r LabelStudioHealthResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r LabelStudioHealthResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type LabelStudioPredictResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *map[string]interface{}
+}
+
+// Status returns HTTPResponse.Status
+func (r LabelStudioPredictResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r LabelStudioPredictResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type LabelStudioSetupResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *map[string]interface{}
+}
+
+// Status returns HTTPResponse.Status
+func (r LabelStudioSetupResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r LabelStudioSetupResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type LabelStudioWebhookResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *map[string]interface{}
+}
+
+// Status returns HTTPResponse.Status
+func (r LabelStudioWebhookResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r LabelStudioWebhookResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListModelsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]Model
+}
+
+// Status returns HTTPResponse.Status
+func (r ListModelsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListModelsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetOpenAPIResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *map[string]interface{}
+}
+
+// Status returns HTTPResponse.Status
+func (r GetOpenAPIResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetOpenAPIResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListProfilesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]TranscodeProfile
+}
+
+// Status returns HTTPResponse.Status
+func (r ListProfilesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListProfilesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListRuleSetsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]RuleSet
+}
+
+// Status returns HTTPResponse.Status
+func (r ListRuleSetsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListRuleSetsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CreateRuleSetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *RuleSet
+}
+
+// Status returns HTTPResponse.Status
+func (r CreateRuleSetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CreateRuleSetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteRuleSetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteRuleSetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteRuleSetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type UpdateRuleSetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *RuleSet
+}
+
+// Status returns HTTPResponse.Status
+func (r UpdateRuleSetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r UpdateRuleSetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetStaticFileResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r GetStaticFileResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetStaticFileResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListStreamsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]StreamStatus
+}
+
+// Status returns HTTPResponse.Status
+func (r ListStreamsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListStreamsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type StartStreamResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *StreamStatus
+}
+
+// Status returns HTTPResponse.Status
+func (r StartStreamResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r StartStreamResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type StopStreamResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *StreamStatus
+}
+
+// Status returns HTTPResponse.Status
+func (r StopStreamResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r StopStreamResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetStreamResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *StreamStatus
+}
+
+// Status returns HTTPResponse.Status
+func (r GetStreamResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetStreamResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListTrainingsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]Training
+}
+
+// Status returns HTTPResponse.Status
+func (r ListTrainingsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListTrainingsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type StartTrainingResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *Training
+	JSON400      *ErrorResponse
+	JSON429      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r StartTrainingResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r StartTrainingResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetTrainingResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Training
+}
+
+// Status returns HTTPResponse.Status
+func (r GetTrainingResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetTrainingResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CancelTrainingResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Training
+}
+
+// Status returns HTTPResponse.Status
+func (r CancelTrainingResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CancelTrainingResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type StreamTrainingEventsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r StreamTrainingEventsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r StreamTrainingEventsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type RegisterTrainingResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Training
+	JSON400      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r RegisterTrainingResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r RegisterTrainingResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ResumeTrainingResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *Training
+	JSON429      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r ResumeTrainingResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ResumeTrainingResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PutWorkerJobArtifactsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r PutWorkerJobArtifactsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PutWorkerJobArtifactsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CompleteWorkerJobResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r CompleteWorkerJobResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CompleteWorkerJobResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostWorkerJobEventsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r PostWorkerJobEventsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostWorkerJobEventsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type WorkerHeartbeatResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *WorkerHeartbeat
+}
+
+// Status returns HTTPResponse.Status
+func (r WorkerHeartbeatResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r WorkerHeartbeatResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetWorkerJobSourceResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r GetWorkerJobSourceResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetWorkerJobSourceResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type LeaseWorkerJobResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *WorkerTask
+}
+
+// Status returns HTTPResponse.Status
+func (r LeaseWorkerJobResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r LeaseWorkerJobResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// IndexWithResponse request returning *IndexResponse
+func (c *ClientWithResponses) IndexWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*IndexResponse, error) {
+	rsp, err := c.Index(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseIndexResponse(rsp)
+}
+
+// RunActiveLearningWithBodyWithResponse request with arbitrary body returning *RunActiveLearningResponse
+func (c *ClientWithResponses) RunActiveLearningWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*RunActiveLearningResponse, error) {
+	rsp, err := c.RunActiveLearningWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRunActiveLearningResponse(rsp)
+}
+
+func (c *ClientWithResponses) RunActiveLearningWithResponse(ctx context.Context, body RunActiveLearningJSONRequestBody, reqEditors ...RequestEditorFn) (*RunActiveLearningResponse, error) {
+	rsp, err := c.RunActiveLearning(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRunActiveLearningResponse(rsp)
+}
+
+// ClearCacheWithResponse request returning *ClearCacheResponse
+func (c *ClientWithResponses) ClearCacheWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ClearCacheResponse, error) {
+	rsp, err := c.ClearCache(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseClearCacheResponse(rsp)
+}
+
+// GetCacheStatsWithResponse request returning *GetCacheStatsResponse
+func (c *ClientWithResponses) GetCacheStatsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetCacheStatsResponse, error) {
+	rsp, err := c.GetCacheStats(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetCacheStatsResponse(rsp)
+}
+
+// DetectSocketWithResponse request returning *DetectSocketResponse
+func (c *ClientWithResponses) DetectSocketWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DetectSocketResponse, error) {
+	rsp, err := c.DetectSocket(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDetectSocketResponse(rsp)
+}
+
+// DocsWithResponse request returning *DocsResponse
+func (c *ClientWithResponses) DocsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DocsResponse, error) {
+	rsp, err := c.Docs(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDocsResponse(rsp)
+}
+
+// CompareEvaluationsWithResponse request returning *CompareEvaluationsResponse
+func (c *ClientWithResponses) CompareEvaluationsWithResponse(ctx context.Context, params *CompareEvaluationsParams, reqEditors ...RequestEditorFn) (*CompareEvaluationsResponse, error) {
+	rsp, err := c.CompareEvaluations(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCompareEvaluationsResponse(rsp)
+}
+
+// ExportJobsWithResponse request returning *ExportJobsResponse
+func (c *ClientWithResponses) ExportJobsWithResponse(ctx context.Context, params *ExportJobsParams, reqEditors ...RequestEditorFn) (*ExportJobsResponse, error) {
+	rsp, err := c.ExportJobs(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseExportJobsResponse(rsp)
+}
+
+// ListJobsWithResponse request returning *ListJobsResponse
+func (c *ClientWithResponses) ListJobsWithResponse(ctx context.Context, params *ListJobsParams, reqEditors ...RequestEditorFn) (*ListJobsResponse, error) {
+	rsp, err := c.ListJobs(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListJobsResponse(rsp)
+}
+
+// CreateJobWithBodyWithResponse request with arbitrary body returning *CreateJobResponse
+func (c *ClientWithResponses) CreateJobWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateJobResponse, error) {
+	rsp, err := c.CreateJobWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateJobResponse(rsp)
+}
+
+func (c *ClientWithResponses) CreateJobWithResponse(ctx context.Context, body CreateJobJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateJobResponse, error) {
+	rsp, err := c.CreateJob(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateJobResponse(rsp)
+}
+
+func (c *ClientWithResponses) CreateJobWithFormdataBodyWithResponse(ctx context.Context, body CreateJobFormdataRequestBody, reqEditors ...RequestEditorFn) (*CreateJobResponse, error) {
+	rsp, err := c.CreateJobWithFormdataBody(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateJobResponse(rsp)
+}
+
+// CreateEvaluateJobWithBodyWithResponse request with arbitrary body returning *CreateEvaluateJobResponse
+func (c *ClientWithResponses) CreateEvaluateJobWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateEvaluateJobResponse, error) {
+	rsp, err := c.CreateEvaluateJobWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateEvaluateJobResponse(rsp)
+}
+
+func (c *ClientWithResponses) CreateEvaluateJobWithResponse(ctx context.Context, body CreateEvaluateJobJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateEvaluateJobResponse, error) {
+	rsp, err := c.CreateEvaluateJob(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateEvaluateJobResponse(rsp)
+}
+
+// CreateFramesJobWithBodyWithResponse request with arbitrary body returning *CreateFramesJobResponse
+func (c *ClientWithResponses) CreateFramesJobWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateFramesJobResponse, error) {
+	rsp, err := c.CreateFramesJobWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateFramesJobResponse(rsp)
+}
+
+func (c *ClientWithResponses) CreateFramesJobWithResponse(ctx context.Context, body CreateFramesJobJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateFramesJobResponse, error) {
+	rsp, err := c.CreateFramesJob(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateFramesJobResponse(rsp)
+}
+
+// GetJobWithResponse request returning *GetJobResponse
+func (c *ClientWithResponses) GetJobWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetJobResponse, error) {
+	rsp, err := c.GetJob(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetJobResponse(rsp)
+}
+
+// CancelJobWithResponse request returning *CancelJobResponse
+func (c *ClientWithResponses) CancelJobWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*CancelJobResponse, error) {
+	rsp, err := c.CancelJob(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCancelJobResponse(rsp)
+}
+
+// DownloadDatasetWithResponse request returning *DownloadDatasetResponse
+func (c *ClientWithResponses) DownloadDatasetWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*DownloadDatasetResponse, error) {
+	rsp, err := c.DownloadDataset(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDownloadDatasetResponse(rsp)
+}
+
+// StreamJobEventsWithResponse request returning *StreamJobEventsResponse
+func (c *ClientWithResponses) StreamJobEventsWithResponse(ctx context.Context, id string, params *StreamJobEventsParams, reqEditors ...RequestEditorFn) (*StreamJobEventsResponse, error) {
+	rsp, err := c.StreamJobEvents(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStreamJobEventsResponse(rsp)
+}
+
+// ExportJobWithResponse request returning *ExportJobResponse
+func (c *ClientWithResponses) ExportJobWithResponse(ctx context.Context, id string, params *ExportJobParams, reqEditors ...RequestEditorFn) (*ExportJobResponse, error) {
+	rsp, err := c.ExportJob(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseExportJobResponse(rsp)
+}
+
+// CutHighlightsWithBodyWithResponse request with arbitrary body returning *CutHighlightsResponse
+func (c *ClientWithResponses) CutHighlightsWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CutHighlightsResponse, error) {
+	rsp, err := c.CutHighlightsWithBody(ctx, id, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCutHighlightsResponse(rsp)
+}
+
+func (c *ClientWithResponses) CutHighlightsWithResponse(ctx context.Context, id string, body CutHighlightsJSONRequestBody, reqEditors ...RequestEditorFn) (*CutHighlightsResponse, error) {
+	rsp, err := c.CutHighlights(ctx, id, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCutHighlightsResponse(rsp)
+}
+
+// GetJobLogWithResponse request returning *GetJobLogResponse
+func (c *ClientWithResponses) GetJobLogWithResponse(ctx context.Context, id string, params *GetJobLogParams, reqEditors ...RequestEditorFn) (*GetJobLogResponse, error) {
+	rsp, err := c.GetJobLog(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetJobLogResponse(rsp)
+}
+
+// EvaluateJobRulesWithResponse request returning *EvaluateJobRulesResponse
+func (c *ClientWithResponses) EvaluateJobRulesWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*EvaluateJobRulesResponse, error) {
+	rsp, err := c.EvaluateJobRules(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEvaluateJobRulesResponse(rsp)
+}
+
+// LabelStudioHealthWithResponse request returning *LabelStudioHealthResponse
+func (c *ClientWithResponses) LabelStudioHealthWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*LabelStudioHealthResponse, error) {
+	rsp, err := c.LabelStudioHealth(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLabelStudioHealthResponse(rsp)
+}
+
+// LabelStudioPredictWithBodyWithResponse request with arbitrary body returning *LabelStudioPredictResponse
+func (c *ClientWithResponses) LabelStudioPredictWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*LabelStudioPredictResponse, error) {
+	rsp, err := c.LabelStudioPredictWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLabelStudioPredictResponse(rsp)
+}
+
+func (c *ClientWithResponses) LabelStudioPredictWithResponse(ctx context.Context, body LabelStudioPredictJSONRequestBody, reqEditors ...RequestEditorFn) (*LabelStudioPredictResponse, error) {
+	rsp, err := c.LabelStudioPredict(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLabelStudioPredictResponse(rsp)
+}
+
+// LabelStudioSetupWithBodyWithResponse request with arbitrary body returning *LabelStudioSetupResponse
+func (c *ClientWithResponses) LabelStudioSetupWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*LabelStudioSetupResponse, error) {
+	rsp, err := c.LabelStudioSetupWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLabelStudioSetupResponse(rsp)
+}
+
+func (c *ClientWithResponses) LabelStudioSetupWithResponse(ctx context.Context, body LabelStudioSetupJSONRequestBody, reqEditors ...RequestEditorFn) (*LabelStudioSetupResponse, error) {
+	rsp, err := c.LabelStudioSetup(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLabelStudioSetupResponse(rsp)
+}
+
+// LabelStudioWebhookWithBodyWithResponse request with arbitrary body returning *LabelStudioWebhookResponse
+func (c *ClientWithResponses) LabelStudioWebhookWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*LabelStudioWebhookResponse, error) {
+	rsp, err := c.LabelStudioWebhookWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLabelStudioWebhookResponse(rsp)
+}
+
+func (c *ClientWithResponses) LabelStudioWebhookWithResponse(ctx context.Context, body LabelStudioWebhookJSONRequestBody, reqEditors ...RequestEditorFn) (*LabelStudioWebhookResponse, error) {
+	rsp, err := c.LabelStudioWebhook(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLabelStudioWebhookResponse(rsp)
+}
+
+// ListModelsWithResponse request returning *ListModelsResponse
+func (c *ClientWithResponses) ListModelsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListModelsResponse, error) {
+	rsp, err := c.ListModels(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListModelsResponse(rsp)
+}
+
+// GetOpenAPIWithResponse request returning *GetOpenAPIResponse
+func (c *ClientWithResponses) GetOpenAPIWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetOpenAPIResponse, error) {
+	rsp, err := c.GetOpenAPI(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetOpenAPIResponse(rsp)
+}
+
+// ListProfilesWithResponse request returning *ListProfilesResponse
+func (c *ClientWithResponses) ListProfilesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListProfilesResponse, error) {
+	rsp, err := c.ListProfiles(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListProfilesResponse(rsp)
+}
+
+// ListRuleSetsWithResponse request returning *ListRuleSetsResponse
+func (c *ClientWithResponses) ListRuleSetsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListRuleSetsResponse, error) {
+	rsp, err := c.ListRuleSets(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListRuleSetsResponse(rsp)
+}
+
+// CreateRuleSetWithBodyWithResponse request with arbitrary body returning *CreateRuleSetResponse
+func (c *ClientWithResponses) CreateRuleSetWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateRuleSetResponse, error) {
+	rsp, err := c.CreateRuleSetWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateRuleSetResponse(rsp)
+}
+
+func (c *ClientWithResponses) CreateRuleSetWithResponse(ctx context.Context, body CreateRuleSetJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateRuleSetResponse, error) {
+	rsp, err := c.CreateRuleSet(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateRuleSetResponse(rsp)
+}
+
+// DeleteRuleSetWithResponse request returning *DeleteRuleSetResponse
+func (c *ClientWithResponses) DeleteRuleSetWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*DeleteRuleSetResponse, error) {
+	rsp, err := c.DeleteRuleSet(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteRuleSetResponse(rsp)
+}
+
+// UpdateRuleSetWithBodyWithResponse request with arbitrary body returning *UpdateRuleSetResponse
+func (c *ClientWithResponses) UpdateRuleSetWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateRuleSetResponse, error) {
+	rsp, err := c.UpdateRuleSetWithBody(ctx, id, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateRuleSetResponse(rsp)
+}
+
+func (c *ClientWithResponses) UpdateRuleSetWithResponse(ctx context.Context, id string, body UpdateRuleSetJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateRuleSetResponse, error) {
+	rsp, err := c.UpdateRuleSet(ctx, id, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateRuleSetResponse(rsp)
+}
+
+// GetStaticFileWithResponse request returning *GetStaticFileResponse
+func (c *ClientWithResponses) GetStaticFileWithResponse(ctx context.Context, path string, reqEditors ...RequestEditorFn) (*GetStaticFileResponse, error) {
+	rsp, err := c.GetStaticFile(ctx, path, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetStaticFileResponse(rsp)
+}
+
+// ListStreamsWithResponse request returning *ListStreamsResponse
+func (c *ClientWithResponses) ListStreamsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListStreamsResponse, error) {
+	rsp, err := c.ListStreams(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListStreamsResponse(rsp)
+}
+
+// StartStreamWithBodyWithResponse request with arbitrary body returning *StartStreamResponse
+func (c *ClientWithResponses) StartStreamWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*StartStreamResponse, error) {
+	rsp, err := c.StartStreamWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStartStreamResponse(rsp)
+}
+
+func (c *ClientWithResponses) StartStreamWithResponse(ctx context.Context, body StartStreamJSONRequestBody, reqEditors ...RequestEditorFn) (*StartStreamResponse, error) {
+	rsp, err := c.StartStream(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStartStreamResponse(rsp)
+}
+
+// StopStreamWithResponse request returning *StopStreamResponse
+func (c *ClientWithResponses) StopStreamWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*StopStreamResponse, error) {
+	rsp, err := c.StopStream(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStopStreamResponse(rsp)
+}
+
+// GetStreamWithResponse request returning *GetStreamResponse
+func (c *ClientWithResponses) GetStreamWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetStreamResponse, error) {
+	rsp, err := c.GetStream(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetStreamResponse(rsp)
+}
+
+// ListTrainingsWithResponse request returning *ListTrainingsResponse
+func (c *ClientWithResponses) ListTrainingsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListTrainingsResponse, error) {
+	rsp, err := c.ListTrainings(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListTrainingsResponse(rsp)
+}
+
+// StartTrainingWithBodyWithResponse request with arbitrary body returning *StartTrainingResponse
+func (c *ClientWithResponses) StartTrainingWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*StartTrainingResponse, error) {
+	rsp, err := c.StartTrainingWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStartTrainingResponse(rsp)
+}
+
+func (c *ClientWithResponses) StartTrainingWithResponse(ctx context.Context, body StartTrainingJSONRequestBody, reqEditors ...RequestEditorFn) (*StartTrainingResponse, error) {
+	rsp, err := c.StartTraining(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStartTrainingResponse(rsp)
+}
+
+// GetTrainingWithResponse request returning *GetTrainingResponse
+func (c *ClientWithResponses) GetTrainingWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetTrainingResponse, error) {
+	rsp, err := c.GetTraining(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetTrainingResponse(rsp)
+}
+
+// CancelTrainingWithResponse request returning *CancelTrainingResponse
+func (c *ClientWithResponses) CancelTrainingWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*CancelTrainingResponse, error) {
+	rsp, err := c.CancelTraining(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCancelTrainingResponse(rsp)
+}
+
+// StreamTrainingEventsWithResponse request returning *StreamTrainingEventsResponse
+func (c *ClientWithResponses) StreamTrainingEventsWithResponse(ctx context.Context, id string, params *StreamTrainingEventsParams, reqEditors ...RequestEditorFn) (*StreamTrainingEventsResponse, error) {
+	rsp, err := c.StreamTrainingEvents(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStreamTrainingEventsResponse(rsp)
+}
+
+// RegisterTrainingWithBodyWithResponse request with arbitrary body returning *RegisterTrainingResponse
+func (c *ClientWithResponses) RegisterTrainingWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*RegisterTrainingResponse, error) {
+	rsp, err := c.RegisterTrainingWithBody(ctx, id, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRegisterTrainingResponse(rsp)
+}
+
+func (c *ClientWithResponses) RegisterTrainingWithResponse(ctx context.Context, id string, body RegisterTrainingJSONRequestBody, reqEditors ...RequestEditorFn) (*RegisterTrainingResponse, error) {
+	rsp, err := c.RegisterTraining(ctx, id, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRegisterTrainingResponse(rsp)
+}
+
+// ResumeTrainingWithResponse request returning *ResumeTrainingResponse
+func (c *ClientWithResponses) ResumeTrainingWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*ResumeTrainingResponse, error) {
+	rsp, err := c.ResumeTraining(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseResumeTrainingResponse(rsp)
+}
+
+// PutWorkerJobArtifactsWithBodyWithResponse request with arbitrary body returning *PutWorkerJobArtifactsResponse
+func (c *ClientWithResponses) PutWorkerJobArtifactsWithBodyWithResponse(ctx context.Context, id string, params *PutWorkerJobArtifactsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutWorkerJobArtifactsResponse, error) {
+	rsp, err := c.PutWorkerJobArtifactsWithBody(ctx, id, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePutWorkerJobArtifactsResponse(rsp)
+}
+
+// CompleteWorkerJobWithBodyWithResponse request with arbitrary body returning *CompleteWorkerJobResponse
+func (c *ClientWithResponses) CompleteWorkerJobWithBodyWithResponse(ctx context.Context, id string, params *CompleteWorkerJobParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CompleteWorkerJobResponse, error) {
+	rsp, err := c.CompleteWorkerJobWithBody(ctx, id, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCompleteWorkerJobResponse(rsp)
+}
+
+func (c *ClientWithResponses) CompleteWorkerJobWithResponse(ctx context.Context, id string, params *CompleteWorkerJobParams, body CompleteWorkerJobJSONRequestBody, reqEditors ...RequestEditorFn) (*CompleteWorkerJobResponse, error) {
+	rsp, err := c.CompleteWorkerJob(ctx, id, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCompleteWorkerJobResponse(rsp)
+}
+
+// PostWorkerJobEventsWithBodyWithResponse request with arbitrary body returning *PostWorkerJobEventsResponse
+func (c *ClientWithResponses) PostWorkerJobEventsWithBodyWithResponse(ctx context.Context, id string, params *PostWorkerJobEventsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostWorkerJobEventsResponse, error) {
+	rsp, err := c.PostWorkerJobEventsWithBody(ctx, id, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostWorkerJobEventsResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostWorkerJobEventsWithResponse(ctx context.Context, id string, params *PostWorkerJobEventsParams, body PostWorkerJobEventsJSONRequestBody, reqEditors ...RequestEditorFn) (*PostWorkerJobEventsResponse, error) {
+	rsp, err := c.PostWorkerJobEvents(ctx, id, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostWorkerJobEventsResponse(rsp)
+}
+
+// WorkerHeartbeatWithResponse request returning *WorkerHeartbeatResponse
+func (c *ClientWithResponses) WorkerHeartbeatWithResponse(ctx context.Context, id string, params *WorkerHeartbeatParams, reqEditors ...RequestEditorFn) (*WorkerHeartbeatResponse, error) {
+	rsp, err := c.WorkerHeartbeat(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWorkerHeartbeatResponse(rsp)
+}
+
+// GetWorkerJobSourceWithResponse request returning *GetWorkerJobSourceResponse
+func (c *ClientWithResponses) GetWorkerJobSourceWithResponse(ctx context.Context, id string, params *GetWorkerJobSourceParams, reqEditors ...RequestEditorFn) (*GetWorkerJobSourceResponse, error) {
+	rsp, err := c.GetWorkerJobSource(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetWorkerJobSourceResponse(rsp)
+}
+
+// LeaseWorkerJobWithResponse request returning *LeaseWorkerJobResponse
+func (c *ClientWithResponses) LeaseWorkerJobWithResponse(ctx context.Context, params *LeaseWorkerJobParams, reqEditors ...RequestEditorFn) (*LeaseWorkerJobResponse, error) {
+	rsp, err := c.LeaseWorkerJob(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLeaseWorkerJobResponse(rsp)
+}
+
+// ParseIndexResponse parses an HTTP response from a IndexWithResponse call
+func ParseIndexResponse(rsp *http.Response) (*IndexResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &IndexResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseRunActiveLearningResponse parses an HTTP response from a RunActiveLearningWithResponse call
+func ParseRunActiveLearningResponse(rsp *http.Response) (*RunActiveLearningResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RunActiveLearningResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ActiveLearningResult
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseClearCacheResponse parses an HTTP response from a ClearCacheWithResponse call
+func ParseClearCacheResponse(rsp *http.Response) (*ClearCacheResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ClearCacheResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseGetCacheStatsResponse parses an HTTP response from a GetCacheStatsWithResponse call
+func ParseGetCacheStatsResponse(rsp *http.Response) (*GetCacheStatsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetCacheStatsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest CacheStats
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDetectSocketResponse parses an HTTP response from a DetectSocketWithResponse call
+func ParseDetectSocketResponse(rsp *http.Response) (*DetectSocketResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DetectSocketResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseDocsResponse parses an HTTP response from a DocsWithResponse call
+func ParseDocsResponse(rsp *http.Response) (*DocsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DocsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseCompareEvaluationsResponse parses an HTTP response from a CompareEvaluationsWithResponse call
+func ParseCompareEvaluationsResponse(rsp *http.Response) (*CompareEvaluationsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CompareEvaluationsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest EvaluationTable
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case rsp.StatusCode == 200:
+		// Content-type (text/html) unsupported
+
+	}
+
+	return response, nil
+}
+
+// ParseExportJobsResponse parses an HTTP response from a ExportJobsWithResponse call
+func ParseExportJobsResponse(rsp *http.Response) (*ExportJobsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ExportJobsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseListJobsResponse parses an HTTP response from a ListJobsWithResponse call
+func ParseListJobsResponse(rsp *http.Response) (*ListJobsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListJobsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []Job
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCreateJobResponse parses an HTTP response from a CreateJobWithResponse call
+func ParseCreateJobResponse(rsp *http.Response) (*CreateJobResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CreateJobResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest JobCreated
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCreateEvaluateJobResponse parses an HTTP response from a CreateEvaluateJobWithResponse call
+func ParseCreateEvaluateJobResponse(rsp *http.Response) (*CreateEvaluateJobResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CreateEvaluateJobResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest Job
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCreateFramesJobResponse parses an HTTP response from a CreateFramesJobWithResponse call
+func ParseCreateFramesJobResponse(rsp *http.Response) (*CreateFramesJobResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CreateFramesJobResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest Job
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetJobResponse parses an HTTP response from a GetJobWithResponse call
+func ParseGetJobResponse(rsp *http.Response) (*GetJobResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetJobResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Job
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCancelJobResponse parses an HTTP response from a CancelJobWithResponse call
+func ParseCancelJobResponse(rsp *http.Response) (*CancelJobResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CancelJobResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseDownloadDatasetResponse parses an HTTP response from a DownloadDatasetWithResponse call
+func ParseDownloadDatasetResponse(rsp *http.Response) (*DownloadDatasetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DownloadDatasetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseStreamJobEventsResponse parses an HTTP response from a StreamJobEventsWithResponse call
+func ParseStreamJobEventsResponse(rsp *http.Response) (*StreamJobEventsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &StreamJobEventsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseExportJobResponse parses an HTTP response from a ExportJobWithResponse call
+func ParseExportJobResponse(rsp *http.Response) (*ExportJobResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ExportJobResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseCutHighlightsResponse parses an HTTP response from a CutHighlightsWithResponse call
+func ParseCutHighlightsResponse(rsp *http.Response) (*CutHighlightsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CutHighlightsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Job
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetJobLogResponse parses an HTTP response from a GetJobLogWithResponse call
+func ParseGetJobLogResponse(rsp *http.Response) (*GetJobLogResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetJobLogResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseEvaluateJobRulesResponse parses an HTTP response from a EvaluateJobRulesWithResponse call
+func ParseEvaluateJobRulesResponse(rsp *http.Response) (*EvaluateJobRulesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &EvaluateJobRulesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []RuleEvent
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseLabelStudioHealthResponse parses an HTTP response from a LabelStudioHealthWithResponse call
+func ParseLabelStudioHealthResponse(rsp *http.Response) (*LabelStudioHealthResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &LabelStudioHealthResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest map[string]interface{}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseLabelStudioPredictResponse parses an HTTP response from a LabelStudioPredictWithResponse call
+func ParseLabelStudioPredictResponse(rsp *http.Response) (*LabelStudioPredictResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &LabelStudioPredictResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest map[string]interface{}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseLabelStudioSetupResponse parses an HTTP response from a LabelStudioSetupWithResponse call
+func ParseLabelStudioSetupResponse(rsp *http.Response) (*LabelStudioSetupResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &LabelStudioSetupResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest map[string]interface{}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseLabelStudioWebhookResponse parses an HTTP response from a LabelStudioWebhookWithResponse call
+func ParseLabelStudioWebhookResponse(rsp *http.Response) (*LabelStudioWebhookResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &LabelStudioWebhookResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest map[string]interface{}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListModelsResponse parses an HTTP response from a ListModelsWithResponse call
+func ParseListModelsResponse(rsp *http.Response) (*ListModelsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListModelsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []Model
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetOpenAPIResponse parses an HTTP response from a GetOpenAPIWithResponse call
+func ParseGetOpenAPIResponse(rsp *http.Response) (*GetOpenAPIResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetOpenAPIResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest map[string]interface{}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListProfilesResponse parses an HTTP response from a ListProfilesWithResponse call
+func ParseListProfilesResponse(rsp *http.Response) (*ListProfilesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListProfilesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []TranscodeProfile
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListRuleSetsResponse parses an HTTP response from a ListRuleSetsWithResponse call
+func ParseListRuleSetsResponse(rsp *http.Response) (*ListRuleSetsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListRuleSetsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []RuleSet
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCreateRuleSetResponse parses an HTTP response from a CreateRuleSetWithResponse call
+func ParseCreateRuleSetResponse(rsp *http.Response) (*CreateRuleSetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CreateRuleSetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest RuleSet
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDeleteRuleSetResponse parses an HTTP response from a DeleteRuleSetWithResponse call
+func ParseDeleteRuleSetResponse(rsp *http.Response) (*DeleteRuleSetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DeleteRuleSetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseUpdateRuleSetResponse parses an HTTP response from a UpdateRuleSetWithResponse call
+func ParseUpdateRuleSetResponse(rsp *http.Response) (*UpdateRuleSetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &UpdateRuleSetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest RuleSet
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetStaticFileResponse parses an HTTP response from a GetStaticFileWithResponse call
+func ParseGetStaticFileResponse(rsp *http.Response) (*GetStaticFileResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetStaticFileResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseListStreamsResponse parses an HTTP response from a ListStreamsWithResponse call
+func ParseListStreamsResponse(rsp *http.Response) (*ListStreamsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListStreamsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []StreamStatus
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseStartStreamResponse parses an HTTP response from a StartStreamWithResponse call
+func ParseStartStreamResponse(rsp *http.Response) (*StartStreamResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &StartStreamResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest StreamStatus
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseStopStreamResponse parses an HTTP response from a StopStreamWithResponse call
+func ParseStopStreamResponse(rsp *http.Response) (*StopStreamResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &StopStreamResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest StreamStatus
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetStreamResponse parses an HTTP response from a GetStreamWithResponse call
+func ParseGetStreamResponse(rsp *http.Response) (*GetStreamResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetStreamResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest StreamStatus
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListTrainingsResponse parses an HTTP response from a ListTrainingsWithResponse call
+func ParseListTrainingsResponse(rsp *http.Response) (*ListTrainingsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListTrainingsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []Training
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseStartTrainingResponse parses an HTTP response from a StartTrainingWithResponse call
+func ParseStartTrainingResponse(rsp *http.Response) (*StartTrainingResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &StartTrainingResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest Training
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetTrainingResponse parses an HTTP response from a GetTrainingWithResponse call
+func ParseGetTrainingResponse(rsp *http.Response) (*GetTrainingResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetTrainingResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Training
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCancelTrainingResponse parses an HTTP response from a CancelTrainingWithResponse call
+func ParseCancelTrainingResponse(rsp *http.Response) (*CancelTrainingResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CancelTrainingResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Training
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseStreamTrainingEventsResponse parses an HTTP response from a StreamTrainingEventsWithResponse call
+func ParseStreamTrainingEventsResponse(rsp *http.Response) (*StreamTrainingEventsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &StreamTrainingEventsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseRegisterTrainingResponse parses an HTTP response from a RegisterTrainingWithResponse call
+func ParseRegisterTrainingResponse(rsp *http.Response) (*RegisterTrainingResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RegisterTrainingResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Training
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseResumeTrainingResponse parses an HTTP response from a ResumeTrainingWithResponse call
+func ParseResumeTrainingResponse(rsp *http.Response) (*ResumeTrainingResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ResumeTrainingResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest Training
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePutWorkerJobArtifactsResponse parses an HTTP response from a PutWorkerJobArtifactsWithResponse call
+func ParsePutWorkerJobArtifactsResponse(rsp *http.Response) (*PutWorkerJobArtifactsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PutWorkerJobArtifactsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseCompleteWorkerJobResponse parses an HTTP response from a CompleteWorkerJobWithResponse call
+func ParseCompleteWorkerJobResponse(rsp *http.Response) (*CompleteWorkerJobResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CompleteWorkerJobResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParsePostWorkerJobEventsResponse parses an HTTP response from a PostWorkerJobEventsWithResponse call
+func ParsePostWorkerJobEventsResponse(rsp *http.Response) (*PostWorkerJobEventsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostWorkerJobEventsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseWorkerHeartbeatResponse parses an HTTP response from a WorkerHeartbeatWithResponse call
+func ParseWorkerHeartbeatResponse(rsp *http.Response) (*WorkerHeartbeatResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &WorkerHeartbeatResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest WorkerHeartbeat
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetWorkerJobSourceResponse parses an HTTP response from a GetWorkerJobSourceWithResponse call
+func ParseGetWorkerJobSourceResponse(rsp *http.Response) (*GetWorkerJobSourceResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetWorkerJobSourceResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseLeaseWorkerJobResponse parses an HTTP response from a LeaseWorkerJobWithResponse call
+func ParseLeaseWorkerJobResponse(rsp *http.Response) (*LeaseWorkerJobResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &LeaseWorkerJobResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest WorkerTask
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
@@ -0,0 +1,5 @@
+// Package apiv1 is a Go client for version 1 of the HTTP API, generated from
+// api/openapi.json.
+package apiv1
+
+//go:generate oapi-codegen -config config.yaml ../openapi.json
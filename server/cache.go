@@ -0,0 +1,398 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	cacheDir          = "./cache"
+	cacheEntryName    = "entry.json"
+	defaultCacheBytes = 20 << 30
+	defaultCacheAge   = 7 * 24 * time.Hour
+	validatorTimeout  = 10 * time.Second
+)
+
+// errNotCacheable is returned for sources whose content can't be pinned
+// down, like live streams or urls served without an ETag or Last-Modified.
+var errNotCacheable = errors.New("source can't be cached")
+
+// cacheEntry is a stored detection result. The annotated media is kept next
+// to it in cacheDir/<key> so evicting an entry never touches job dirs.
+type cacheEntry struct {
+	Key        string      `json:"key"`
+	Source     string      `json:"source"`
+	Model      string      `json:"model"`
+	CreatedAt  time.Time   `json:"created_at"`
+	LastUsed   time.Time   `json:"last_used"`
+	Hits       int         `json:"hits"`
+	Size       int64       `json:"size"`
+	OutputName string      `json:"output_name"`
+	Media      mediaInfo   `json:"media"`
+	Classes    []string    `json:"classes"`
+	Detections []detection `json:"detections"`
+}
+
+// cacheStats is returned by the stats endpoint.
+type cacheStats struct {
+	Entries  int   `json:"entries"`
+	Bytes    int64 `json:"bytes"`
+	MaxBytes int64 `json:"max_bytes"`
+	// MaxAge uses the CACHE_MAX_AGE format, e.g. "168h0m0s".
+	MaxAge  string  `json:"max_age"`
+	Hits    int     `json:"hits"`
+	Misses  int     `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// resultCache maps the hash of the input media, model version and inference
+// parameters to a previous result. A zero maxBytes disables it.
+type resultCache struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+	maxAge   time.Duration
+	hits     int
+	misses   int
+}
+
+var results = newResultCache()
+
+// newResultCache reads its limits from CACHE_MAX_BYTES and CACHE_MAX_AGE.
+func newResultCache() *resultCache {
+	c := &resultCache{dir: cacheDir, maxBytes: defaultCacheBytes, maxAge: defaultCacheAge}
+	if v := os.Getenv("CACHE_MAX_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			logger.Errorf("invalid CACHE_MAX_BYTES %q: %v", v, err)
+		} else {
+			c.maxBytes = n
+		}
+	}
+	if v := os.Getenv("CACHE_MAX_AGE"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			logger.Errorf("invalid CACHE_MAX_AGE %q: %v", v, err)
+		} else {
+			c.maxAge = d
+		}
+	}
+	return c
+}
+
+func (c *resultCache) enabled() bool {
+	return c.maxBytes > 0
+}
+
+// cacheKey identifies a detection run by what it was run on and how.
+func cacheKey(opts predictOptions) (string, error) {
+	source, err := sourceIdentity(opts.Source)
+	if err != nil {
+		return "", err
+	}
+	parts := []string{source, modelVersion(opts.Model), strconv.FormatFloat(opts.Confidence, 'f', -1, 64), opts.Mode}
+	if opts.Mode == predictModeTrack {
+		parts = append(parts, opts.Tracker)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// sourceIdentity hashes local files by content and normalizes urls so the
+// same media reached through different links shares a key. http urls also
+// carry their validator so a file changed on the server gets a new key.
+func sourceIdentity(source string) (string, error) {
+	if _, err := os.Stat(source); err == nil {
+		f, err := os.Open(source)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		hash := sha256.New()
+		if _, err := io.Copy(hash, f); err != nil {
+			return "", fmt.Errorf("error hashing %v: %v", source, err)
+		}
+		return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+	}
+	id := normalizeURL(source)
+	// a video id always points at the same video
+	if strings.HasPrefix(id, "youtube:") {
+		return id, nil
+	}
+	u, err := url.Parse(source)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", errNotCacheable
+	}
+	validator, err := urlValidator(source)
+	if err != nil {
+		return "", err
+	}
+	return id + "\n" + validator, nil
+}
+
+// urlValidator returns the ETag, or else the Last-Modified date and size,
+// the server reports for a url.
+func urlValidator(source string) (string, error) {
+	client := &http.Client{Timeout: validatorTimeout}
+	resp, err := client.Head(source)
+	if err != nil {
+		return "", fmt.Errorf("error checking %v: %v", source, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errNotCacheable
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		return "etag:" + etag, nil
+	}
+	if modified := resp.Header.Get("Last-Modified"); modified != "" {
+		return fmt.Sprintf("last-modified:%v:%v", modified, resp.ContentLength), nil
+	}
+	return "", errNotCacheable
+}
+
+// normalizeURL lowercases the host, drops fragments, tracking parameters and
+// trailing slashes, and reduces youtube links to their video id.
+func normalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return strings.TrimSpace(raw)
+	}
+	host := strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(u.Host), "www."), "m.")
+	switch host {
+	case "youtu.be":
+		if id := strings.Trim(u.Path, "/"); id != "" {
+			return "youtube:" + id
+		}
+	case "youtube.com", "music.youtube.com":
+		if id := u.Query().Get("v"); id != "" {
+			return "youtube:" + id
+		}
+		for _, prefix := range []string{"/shorts/", "/embed/", "/live/"} {
+			if id, ok := strings.CutPrefix(u.Path, prefix); ok && id != "" {
+				return "youtube:" + strings.Trim(id, "/")
+			}
+		}
+	}
+
+	query := u.Query()
+	for name := range query {
+		if strings.HasPrefix(name, "utm_") || name == "fbclid" || name == "gclid" {
+			query.Del(name)
+		}
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = host
+	u.Fragment = ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	// Encode sorts the parameters
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func (c *resultCache) entryDir(key string) string {
+	return filepath.Join(c.dir, key)
+}
+
+// get returns the entry of key, refreshing its last use. Expired entries are
+// removed and reported as misses.
+func (c *resultCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, err := c.readEntry(key)
+	if err != nil || time.Since(entry.CreatedAt) > c.maxAge {
+		if err == nil {
+			c.remove(key)
+		}
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	entry.Hits++
+	entry.LastUsed = time.Now()
+	if err := c.writeEntry(entry); err != nil {
+		logger.Errorf("error updating cache entry %v: %v", key, err)
+	}
+	return entry, true
+}
+
+// outputPath is where the cached annotated media of an entry lives.
+func (c *resultCache) outputPath(entry *cacheEntry) string {
+	return filepath.Join(c.entryDir(entry.Key), entry.OutputName)
+}
+
+// put stores the result of a finished detection job and evicts entries over
+// the limits.
+func (c *resultCache) put(key string, j *job) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dir := c.entryDir(key)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating cache dir: %v", err)
+	}
+	entry := &cacheEntry{
+		Key:        key,
+		Source:     j.Source,
+		Model:      modelVersion(j.Model),
+		CreatedAt:  time.Now(),
+		LastUsed:   time.Now(),
+		OutputName: filepath.Base(j.OutputPath),
+		Media:      j.Media,
+		Classes:    j.Classes,
+		Detections: j.Detections,
+	}
+	if err := linkOrCopy(j.OutputPath, c.outputPath(entry)); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+	if info, err := os.Stat(c.outputPath(entry)); err == nil {
+		entry.Size = info.Size()
+	}
+	if err := c.writeEntry(entry); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+	c.evict()
+	return nil
+}
+
+func (c *resultCache) readEntry(key string) (*cacheEntry, error) {
+	data, err := os.ReadFile(filepath.Join(c.entryDir(key), cacheEntryName))
+	if err != nil {
+		return nil, err
+	}
+	entry := &cacheEntry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, fmt.Errorf("error decoding cache entry %v: %v", key, err)
+	}
+	return entry, nil
+}
+
+func (c *resultCache) writeEntry(entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.entryDir(entry.Key), cacheEntryName), data, 0644)
+}
+
+func (c *resultCache) remove(key string) {
+	if err := os.RemoveAll(c.entryDir(key)); err != nil {
+		logger.Errorf("error removing cache entry %v: %v", key, err)
+	}
+}
+
+// entries lists every readable entry, least recently used first.
+func (c *resultCache) entries() []*cacheEntry {
+	dirs, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil
+	}
+	list := []*cacheEntry{}
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		entry, err := c.readEntry(d.Name())
+		if err != nil {
+			logger.Errorf("removing unreadable cache entry %v: %v", d.Name(), err)
+			c.remove(d.Name())
+			continue
+		}
+		list = append(list, entry)
+	}
+	sort.Slice(list, func(a, b int) bool {
+		return list[a].LastUsed.Before(list[b].LastUsed)
+	})
+	return list
+}
+
+// evict drops entries older than maxAge, then the least recently used ones
+// until the cache fits in maxBytes.
+func (c *resultCache) evict() {
+	var total int64
+	kept := []*cacheEntry{}
+	for _, entry := range c.entries() {
+		if time.Since(entry.CreatedAt) > c.maxAge {
+			c.remove(entry.Key)
+			continue
+		}
+		total += entry.Size
+		kept = append(kept, entry)
+	}
+	for _, entry := range kept {
+		if total <= c.maxBytes {
+			break
+		}
+		c.remove(entry.Key)
+		total -= entry.Size
+	}
+}
+
+func (c *resultCache) stats() cacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := cacheStats{MaxBytes: c.maxBytes, MaxAge: c.maxAge.String(), Hits: c.hits, Misses: c.misses}
+	for _, entry := range c.entries() {
+		stats.Entries++
+		stats.Bytes += entry.Size
+	}
+	if c.hits+c.misses > 0 {
+		stats.HitRate = float64(c.hits) / float64(c.hits+c.misses)
+	}
+	return stats
+}
+
+func (c *resultCache) clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return os.RemoveAll(c.dir)
+}
+
+// linkOrCopy hard links src to dest, copying when they are on different
+// filesystems.
+func linkOrCopy(src, dest string) error {
+	os.Remove(dest)
+	if err := os.Link(src, dest); err == nil {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("error opening %v: %v", src, err)
+	}
+	defer in.Close()
+	out, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("error creating %v: %v", dest, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("error copying %v: %v", src, err)
+	}
+	return out.Close()
+}
+
+func cacheStatsHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, results.stats())
+}
+
+func clearCacheHandler(w http.ResponseWriter, r *http.Request) {
+	if err := results.clear(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
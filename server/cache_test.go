@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSourceIdentityFollowsURLValidators(t *testing.T) {
+	etag := `"v1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/etag.mp4":
+			w.Header().Set("ETag", etag)
+		case "/modified.mp4":
+			w.Header().Set("Last-Modified", "Mon, 19 Oct 2026 10:00:00 GMT")
+		case "/missing.mp4":
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("video"))
+	}))
+	defer server.Close()
+
+	first, err := sourceIdentity(server.URL + "/etag.mp4?utm_source=mail")
+	if err != nil {
+		t.Fatalf("sourceIdentity: %v", err)
+	}
+	same, _ := sourceIdentity(server.URL + "/etag.mp4")
+	if first != same {
+		t.Errorf("tracking parameters changed the identity: %q vs %q", first, same)
+	}
+	etag = `"v2"`
+	if changed, _ := sourceIdentity(server.URL + "/etag.mp4"); changed == first {
+		t.Errorf("identity %q didn't change with the ETag", changed)
+	}
+
+	if _, err := sourceIdentity(server.URL + "/modified.mp4"); err != nil {
+		t.Errorf("Last-Modified url: %v", err)
+	}
+	for _, source := range []string{server.URL + "/plain.mp4", server.URL + "/missing.mp4", "rtsp://camera/stream"} {
+		if _, err := sourceIdentity(source); err != errNotCacheable {
+			t.Errorf("%v: got %v, want errNotCacheable", source, err)
+		}
+	}
+	if id, err := sourceIdentity("https://youtu.be/abc123"); err != nil || id != "youtube:abc123" {
+		t.Errorf("youtube link: got %q, %v", id, err)
+	}
+}
+
+func TestCacheStatsMaxAge(t *testing.T) {
+	c := &resultCache{dir: t.TempDir(), maxBytes: 1, maxAge: defaultCacheAge}
+	if got := c.stats().MaxAge; got != "168h0m0s" {
+		t.Errorf("max age = %q", got)
+	}
+}
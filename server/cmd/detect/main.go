@@ -0,0 +1,623 @@
+// Command detect is a command-line client for the detection server. It
+// submits a file or url, tails the job's logs and progress, downloads the
+// annotated media and detection exports, and lists past jobs.
+//
+//	detect submit [flags] <file or url>
+//	detect watch [-level warn] <job id>
+//	detect logs [-level warn] <job id>
+//	detect download [-out dir] [-formats coco,csv] <job id>
+//	detect cancel <job id>
+//	detect jobs [-limit n]
+//	detect models
+//
+// The server defaults to DETECT_SERVER, or http://localhost:8080 when unset.
+// Cookies the server sets are kept in the user config dir so the per-client
+// job limit follows the user across runs.
+// The exit status is 1 when a job fails or is cancelled, 2 on usage errors.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	apiv1 "github.com/arkusnexus/ai-demo/server/api/v1"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// errUsage marks errors that should print usage and exit with 2.
+var errUsage = errors.New("usage")
+
+// errJobFailed is returned when a watched job ends failed or cancelled.
+var errJobFailed = errors.New("job failed")
+
+// jobEvent is the JSON form of a job event from /jobs/{id}/events?format=json.
+type jobEvent struct {
+	Seq   int    `json:"seq"`
+	Type  string `json:"type"`
+	JobID string `json:"job_id"`
+	Msg   struct {
+		Message  string  `json:"message"`
+		Progress float64 `json:"progress"`
+		LogLine  string  `json:"log_line"`
+		LogLines []struct {
+			Level string `json:"level"`
+			Text  string `json:"text"`
+		} `json:"log_lines"`
+		VideoURL string `json:"video_url"`
+		ImageURL string `json:"image_url"`
+	} `json:"message"`
+}
+
+// logLevels orders the levels of job log lines.
+var logLevels = map[string]int{"info": 0, "warn": 1, "error": 2}
+
+type cli struct {
+	// server is bound to the -server flag, parse trims it.
+	server string
+	client *apiv1.ClientWithResponses
+	http   *http.Client
+	out    io.Writer
+	// level is the lowest level of log lines watch prints.
+	level string
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:])
+	switch {
+	case err == nil:
+	case errors.Is(err, errUsage):
+		fmt.Fprintln(os.Stderr, err)
+		usage()
+		os.Exit(2)
+	default:
+		fmt.Fprintln(os.Stderr, "detect:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprint(os.Stderr, `usage:
+  detect submit [-model name] [-conf 0.7] [-mode detect|track] [-tracker name]
+                [-profile name] [-highlights] [-renditions] [-wait=true]
+                [-out dir] [-formats coco,yolo,voc,csv] [-level info|warn|error]
+                <file or url>
+  detect watch [-level info|warn|error] <job id>
+  detect logs [-level info|warn|error] <job id>
+  detect download [-out dir] [-formats list] <job id>
+  detect cancel <job id>
+  detect jobs [-limit n]
+  detect models
+
+Every command takes -server, defaulting to $DETECT_SERVER or `+defaultServer+`.
+`)
+}
+
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: missing command", errUsage)
+	}
+	name, args := args[0], args[1:]
+	commands := map[string]func(context.Context, *cli, *flag.FlagSet, []string) error{
+		"submit":   submit,
+		"watch":    watch,
+		"download": download,
+		"cancel":   cancel,
+		"jobs":     jobs,
+		"models":   models,
+		"logs":     logs,
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("%w: unknown command %q", errUsage, name)
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	server := os.Getenv("DETECT_SERVER")
+	if server == "" {
+		server = defaultServer
+	}
+	c := &cli{out: os.Stdout}
+	fs.StringVar(&c.server, "server", server, "url of the detection server")
+	return cmd(ctx, c, fs, args)
+}
+
+// parse parses the flags and creates the client, want is the number of
+// positional arguments the command needs.
+func (c *cli) parse(fs *flag.FlagSet, args []string, want int) error {
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() != want {
+		return fmt.Errorf("%w: %v takes %v argument(s)", errUsage, fs.Name(), want)
+	}
+	c.server = strings.TrimSuffix(c.server, "/")
+	if _, ok := logLevels[c.level]; c.level != "" && !ok {
+		return fmt.Errorf("%w: unknown log level %q", errUsage, c.level)
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return err
+	}
+	c.http = &http.Client{Jar: jar}
+	c.loadCookies()
+	client, err := apiv1.NewClientWithResponses(c.server, apiv1.WithHTTPClient(c.http))
+	if err != nil {
+		return err
+	}
+	c.client = client
+	return nil
+}
+
+// cookiesPath is where the cookies of every server are kept.
+func cookiesPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "detect", "cookies.json"), nil
+}
+
+// loadCookies puts the saved cookies of the server in the jar, a missing or
+// unreadable file just means a new client.
+func (c *cli) loadCookies() {
+	u, err := url.Parse(c.server)
+	if err != nil {
+		return
+	}
+	path, err := cookiesPath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	saved := map[string][]*http.Cookie{}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return
+	}
+	c.http.Jar.SetCookies(u, saved[c.server])
+}
+
+// saveCookies stores the cookies the server set for the next run.
+func (c *cli) saveCookies() error {
+	u, err := url.Parse(c.server)
+	if err != nil {
+		return err
+	}
+	path, err := cookiesPath()
+	if err != nil {
+		return err
+	}
+	saved := map[string][]*http.Cookie{}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &saved)
+	}
+	saved[c.server] = c.http.Jar.Cookies(u)
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func submit(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	model := fs.String("model", "", "model name from the models command")
+	conf := fs.Float64("conf", 0, "minimum confidence, 0 uses the server default")
+	mode := fs.String("mode", "", "detect or track")
+	tracker := fs.String("tracker", "", "tracker config for track mode")
+	profile := fs.String("profile", "", "transcoding profile of the annotated video")
+	highlights := fs.Bool("highlights", false, "cut highlight clips")
+	renditions := fs.Bool("renditions", false, "write HLS renditions")
+	wait := fs.Bool("wait", true, "tail the job until it is done")
+	fs.StringVar(&c.level, "level", "info", "lowest level of log lines to print: info, warn or error")
+	out := fs.String("out", "", "download the results into this dir when done")
+	formats := fs.String("formats", "", "export formats to download, all when empty")
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+	source := fs.Arg(0)
+
+	fields := map[string]string{
+		"model":   *model,
+		"mode":    *mode,
+		"tracker": *tracker,
+		"profile": *profile,
+	}
+	if *conf > 0 {
+		fields["confidence"] = strconv.FormatFloat(*conf, 'f', -1, 64)
+	}
+	if *highlights {
+		fields["highlights"] = "on"
+	}
+	if *renditions {
+		fields["renditions"] = "on"
+	}
+
+	body, contentType, err := jobForm(source, fields)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.CreateJobWithBodyWithResponse(ctx, contentType, body)
+	if err != nil {
+		return err
+	}
+	if err := c.saveCookies(); err != nil {
+		fmt.Fprintln(os.Stderr, "error saving cookies:", err)
+	}
+	if resp.JSON202 == nil {
+		for _, e := range []*apiv1.ErrorResponse{resp.JSON400, resp.JSON429, resp.JSON500} {
+			if e != nil {
+				return fmt.Errorf("error submitting job: %v", formatJobError(e.Error))
+			}
+		}
+		return fmt.Errorf("error submitting job: %v: %s", resp.Status(), bytes.TrimSpace(resp.Body))
+	}
+	id := resp.JSON202.Id
+	fmt.Fprintln(os.Stderr, "job", id)
+	if !*wait {
+		fmt.Fprintln(c.out, id)
+		return nil
+	}
+
+	if err := c.watch(ctx, id); err != nil {
+		return err
+	}
+	if *out != "" {
+		return c.download(ctx, id, *out, *formats)
+	}
+	return nil
+}
+
+// formatJobError prints the message, code and cause of an error, noting
+// when it's worth submitting again.
+func formatJobError(e apiv1.JobError) string {
+	s := fmt.Sprintf("%v (%v)", e.Message, e.Code)
+	if e.Detail != nil && *e.Detail != "" {
+		s += ": " + *e.Detail
+	}
+	if e.Retryable {
+		s += ", retrying may help"
+	}
+	return s
+}
+
+// jobForm builds the multipart body of POST /jobs, uploading source when it
+// is a local file and passing it as the message otherwise. The body is
+// streamed so large files aren't held in memory.
+func jobForm(source string, fields map[string]string) (io.Reader, string, error) {
+	var file *os.File
+	if _, err := os.Stat(source); err == nil {
+		if file, err = os.Open(source); err != nil {
+			return nil, "", err
+		}
+	}
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeJobForm(mw, source, file, fields))
+	}()
+	return pr, mw.FormDataContentType(), nil
+}
+
+func writeJobForm(mw *multipart.Writer, source string, file *os.File, fields map[string]string) error {
+	for k, v := range fields {
+		if v != "" {
+			if err := mw.WriteField(k, v); err != nil {
+				return err
+			}
+		}
+	}
+	if file == nil {
+		if err := mw.WriteField("message", source); err != nil {
+			return err
+		}
+		return mw.Close()
+	}
+	defer file.Close()
+	part, err := mw.CreateFormFile("file", filepath.Base(source))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("error reading %v: %v", source, err)
+	}
+	return mw.Close()
+}
+
+func watch(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	fs.StringVar(&c.level, "level", "info", "lowest level of log lines to print: info, warn or error")
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+	return c.watch(ctx, fs.Arg(0))
+}
+
+// watch tails the events of a job, printing logs to stderr, until it is done.
+// Dropped connections are resumed after the last event seen. It returns
+// errJobFailed when the job did not finish successfully.
+func (c *cli) watch(ctx context.Context, id string) error {
+	after, lastProgress := 0, -1
+	for {
+		done, err := c.tail(ctx, id, &after, &lastProgress)
+		if err != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if done {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "lost the event stream: %v, reconnecting\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+
+	resp, err := c.client.GetJobWithResponse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if resp.JSON200 == nil {
+		return fmt.Errorf("error loading job %v: %v", id, resp.Status())
+	}
+	j := resp.JSON200
+	fmt.Fprintf(os.Stderr, "job %v %v\n", j.Id, j.Status)
+	if j.Status != apiv1.JobStatusDone {
+		if j.Failure != nil {
+			return fmt.Errorf("%w: %v", errJobFailed, formatJobError(*j.Failure))
+		}
+		if j.Error != nil {
+			return fmt.Errorf("%w: %v", errJobFailed, *j.Error)
+		}
+		return fmt.Errorf("%w: %v", errJobFailed, j.Status)
+	}
+	return nil
+}
+
+// tail reads the event stream once, it reports whether the done event was
+// seen.
+func (c *cli) tail(ctx context.Context, id string, after, lastProgress *int) (bool, error) {
+	format := apiv1.StreamJobEventsParamsFormat("json")
+	resp, err := c.client.StreamJobEvents(ctx, id, &apiv1.StreamJobEventsParams{After: after, Format: &format})
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return true, fmt.Errorf("error watching job %v: %v: %s", id, resp.Status, bytes.TrimSpace(body))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if v, ok := strings.CutPrefix(line, "data: "); ok {
+			data.WriteString(v)
+			continue
+		}
+		if line != "" || data.Len() == 0 {
+			continue
+		}
+		var e jobEvent
+		if err := json.Unmarshal([]byte(data.String()), &e); err != nil {
+			return false, fmt.Errorf("invalid event: %v", err)
+		}
+		data.Reset()
+		if e.Seq > 0 {
+			*after = e.Seq
+		}
+		switch e.Type {
+		case "queued":
+			fmt.Fprintf(os.Stderr, "queued %v\n", e.Msg.Message)
+		case "log":
+			for _, line := range e.Msg.LogLines {
+				if logLevels[line.Level] >= logLevels[c.level] {
+					fmt.Fprintln(os.Stderr, line.Text)
+				}
+			}
+		case "error":
+			fmt.Fprintln(os.Stderr, e.Msg.LogLine)
+		case "progress":
+			if p := int(e.Msg.Progress); p != *lastProgress && p%10 == 0 {
+				*lastProgress = p
+				fmt.Fprintf(os.Stderr, "transcoding %v%%\n", p)
+			}
+		case "done":
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+	return false, io.ErrUnexpectedEOF
+}
+
+func download(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	out := fs.String("out", ".", "directory to write the results into")
+	formats := fs.String("formats", "", "export formats, all when empty")
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+	return c.download(ctx, fs.Arg(0), *out, *formats)
+}
+
+// download writes the annotated video or image and the detection exports of
+// a job into dir.
+func (c *cli) download(ctx context.Context, id, dir, formats string) error {
+	resp, err := c.client.GetJobWithResponse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if resp.JSON200 == nil {
+		return fmt.Errorf("error loading job %v: %v", id, resp.Status())
+	}
+	j := resp.JSON200
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	media := ""
+	if j.VideoPath != nil && *j.VideoPath != "" {
+		media = *j.VideoPath
+	} else if j.OutputPath != nil && *j.OutputPath != "" && (j.Media == nil || j.Media.IsVideo == nil || !*j.Media.IsVideo) {
+		media = *j.OutputPath
+	}
+	if media != "" {
+		name := filepath.Base(media)
+		if err := c.fetch(ctx, fmt.Sprintf("/static/jobs/%v/%v", j.Id, url.PathEscape(name)), filepath.Join(dir, j.Id+"-"+name)); err != nil {
+			return err
+		}
+	}
+
+	// only detect jobs keep a log
+	if err := c.fetch(ctx, fmt.Sprintf("/jobs/%v/logs", j.Id), filepath.Join(dir, fmt.Sprintf("job-%v.log", j.Id))); err != nil {
+		fmt.Fprintln(os.Stderr, "skipped the log:", err)
+	}
+
+	exportURL := fmt.Sprintf("/jobs/%v/export", j.Id)
+	if formats != "" {
+		exportURL += "?format=" + url.QueryEscape(formats)
+	}
+	return c.fetch(ctx, exportURL, filepath.Join(dir, fmt.Sprintf("job-%v.zip", j.Id)))
+}
+
+// fetch downloads a server path into a file.
+func (c *cli) fetch(ctx context.Context, path, dest string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.server+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("error downloading %v: %v: %s", path, resp.Status, bytes.TrimSpace(body))
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return fmt.Errorf("error downloading %v: %v", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, dest)
+	return nil
+}
+
+func logs(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	fs.StringVar(&c.level, "level", "", "lowest level of log lines to print: info, warn or error")
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/jobs/%v/logs", url.PathEscape(fs.Arg(0)))
+	if c.level != "" {
+		path += "?level=" + c.level
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.server+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("error loading log: %v: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	_, err = io.Copy(c.out, resp.Body)
+	return err
+}
+
+func cancel(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+	resp, err := c.client.CancelJobWithResponse(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() != http.StatusAccepted {
+		return fmt.Errorf("error cancelling job: %v: %s", resp.Status(), bytes.TrimSpace(resp.Body))
+	}
+	return nil
+}
+
+func jobs(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	limit := fs.Int("limit", 20, "number of jobs to list, 0 lists all")
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	resp, err := c.client.ListJobsWithResponse(ctx, &apiv1.ListJobsParams{Limit: limit})
+	if err != nil {
+		return err
+	}
+	if resp.JSON200 == nil {
+		return fmt.Errorf("error listing jobs: %v: %s", resp.Status(), bytes.TrimSpace(resp.Body))
+	}
+
+	tw := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tCREATED\tSTATUS\tMODEL\tSOURCE")
+	for _, j := range *resp.JSON200 {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\n", j.Id, j.CreatedAt.Local().Format(time.DateTime), j.Status, filepath.Base(j.Model), j.Source)
+	}
+	return tw.Flush()
+}
+
+func models(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	resp, err := c.client.ListModelsWithResponse(ctx)
+	if err != nil {
+		return err
+	}
+	if resp.JSON200 == nil {
+		return fmt.Errorf("error listing models: %v: %s", resp.Status(), bytes.TrimSpace(resp.Body))
+	}
+
+	tw := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tVERSION\tPATH\tDEFAULT")
+	for _, m := range *resp.JSON200 {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\n", m.Name, m.Version, m.Path, m.Default != nil && *m.Default)
+	}
+	return tw.Flush()
+}
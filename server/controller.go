@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	detectionv1alpha1 "github.com/arkusnexus/ai-demo/server/k8s/v1alpha1"
+)
+
+const (
+	defaultJobImage          = "aiarkusnexus/opensource-demo-be:latest"
+	defaultJobServiceAccount = "detection-job"
+	defaultJobBackoffLimit   = 1
+	// jobsMountPath is jobsDir in the server image.
+	jobsMountPath = "/server/static/jobs"
+
+	// exitCodeNotRetryable is the exit code of a one-shot run that failed
+	// with an error retrying won't fix, the pod failure policy fails the Job
+	// right away on it.
+	exitCodeNotRetryable = 2
+
+	detectionJobNameEnv      = "DETECTION_JOB_NAME"
+	detectionJobNamespaceEnv = "DETECTION_JOB_NAMESPACE"
+)
+
+// newControllerScheme has the built in types plus DetectionJob.
+func newControllerScheme() (*runtime.Scheme, error) {
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		return nil, err
+	}
+	if err := detectionv1alpha1.AddToScheme(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// detectionJobReconciler runs every DetectionJob as a Kubernetes Job with the
+// server binary in one-shot mode and keeps its phase in sync with the Job.
+// The pod reports progress and artifacts to the status itself.
+type detectionJobReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Image is the server image jobs run, a DetectionJob can override it.
+	Image string
+	// ServiceAccount is what job pods run as, it must be allowed to patch
+	// detectionjobs/status.
+	ServiceAccount string
+}
+
+// +kubebuilder:rbac:groups=detection.arkusnexus.com,resources=detectionjobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=detection.arkusnexus.com,resources=detectionjobs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=detection.arkusnexus.com,resources=detectionjobs/finalizers,verbs=update
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create
+
+func (r *detectionJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	dj := &detectionv1alpha1.DetectionJob{}
+	if err := r.Get(ctx, req.NamespacedName, dj); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if dj.Status.Phase == detectionv1alpha1.PhaseSucceeded || dj.Status.Phase == detectionv1alpha1.PhaseFailed {
+		return ctrl.Result{}, nil
+	}
+	log := logger.ctx(withLogAttrs(ctx, "detection_job", req.String()))
+	orig := dj.DeepCopy()
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: dj.Namespace, Name: detectionJobName(dj)}, job)
+	switch {
+	case apierrors.IsNotFound(err):
+		job, err = r.newJob(dj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+			return ctrl.Result{}, fmt.Errorf("error creating job: %v", err)
+		}
+		log.Info("created job", "job", job.Name)
+	case err != nil:
+		return ctrl.Result{}, err
+	}
+
+	dj.Status.JobName = job.Name
+	dj.Status.ObservedGeneration = dj.Generation
+	syncJobStatus(&dj.Status, job)
+	if dj.Status.Phase != orig.Status.Phase {
+		log.Info("detection job "+strings.ToLower(dj.Status.Phase), "job", job.Name)
+	}
+	if err := r.Status().Patch(ctx, dj, client.MergeFrom(orig)); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// syncJobStatus derives the phase and times from the Job. The pod fills in
+// the failure message when it gets to report it.
+func syncJobStatus(status *detectionv1alpha1.DetectionJobStatus, job *batchv1.Job) {
+	status.StartTime = job.Status.StartTime
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			status.Phase = detectionv1alpha1.PhaseSucceeded
+			status.CompletionTime = job.Status.CompletionTime
+			return
+		case batchv1.JobFailed:
+			status.Phase = detectionv1alpha1.PhaseFailed
+			status.CompletionTime = &c.LastTransitionTime
+			if status.Message == "" {
+				status.Message = c.Message
+			}
+			return
+		}
+	}
+	status.Phase = detectionv1alpha1.PhasePending
+	if job.Status.Active > 0 {
+		status.Phase = detectionv1alpha1.PhaseRunning
+	}
+}
+
+// detectionJobName is the name of the Job of a DetectionJob, kept short
+// enough for the job-name pod label.
+func detectionJobName(dj *detectionv1alpha1.DetectionJob) string {
+	name := dj.Name
+	if len(name) > 56 {
+		name = strings.TrimRight(name[:56], "-.")
+	}
+	return name + "-detect"
+}
+
+// oneShotArgs are the arguments of "server run" for the spec.
+func oneShotArgs(spec detectionv1alpha1.DetectionJobSpec) []string {
+	args := []string{"run", "-source", spec.Source}
+	add := func(flag, value string) {
+		if value != "" {
+			args = append(args, "-"+flag, value)
+		}
+	}
+	add("model", spec.Model)
+	add("confidence", spec.Confidence)
+	add("mode", spec.Mode)
+	add("tracker", spec.Tracker)
+	add("profile", spec.Profile)
+	if spec.Highlights {
+		args = append(args, "-highlights")
+	}
+	if spec.Renditions {
+		args = append(args, "-renditions")
+	}
+	return args
+}
+
+// newJob builds the Job running a DetectionJob, owned by it so deleting the
+// DetectionJob deletes the Job and its pods.
+func (r *detectionJobReconciler) newJob(dj *detectionv1alpha1.DetectionJob) (*batchv1.Job, error) {
+	image := dj.Spec.Image
+	if image == "" {
+		image = r.Image
+	}
+	backoffLimit := int32(defaultJobBackoffLimit)
+	if dj.Spec.BackoffLimit != nil {
+		backoffLimit = *dj.Spec.BackoffLimit
+	}
+	labels := map[string]string{
+		"app.kubernetes.io/name":       "detection-job",
+		"app.kubernetes.io/managed-by": "detection-controller",
+		"detection.arkusnexus.com/job": dj.Name,
+	}
+
+	container := corev1.Container{
+		Name:  "detect",
+		Image: image,
+		Args:  oneShotArgs(dj.Spec),
+		Env: []corev1.EnvVar{
+			{Name: detectionJobNameEnv, Value: dj.Name},
+			{Name: detectionJobNamespaceEnv, Value: dj.Namespace},
+		},
+		Resources:                dj.Spec.Resources,
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+	pod := corev1.PodSpec{
+		RestartPolicy:      corev1.RestartPolicyNever,
+		ServiceAccountName: r.ServiceAccount,
+		Containers:         []corev1.Container{container},
+	}
+	if dj.Spec.OutputVolumeClaim != "" {
+		pod.Volumes = []corev1.Volume{{
+			Name: "jobs",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: dj.Spec.OutputVolumeClaim},
+			},
+		}}
+		pod.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: "jobs", MountPath: jobsMountPath}}
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      detectionJobName(dj),
+			Namespace: dj.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			// errors like a bad source fail the same way every time
+			PodFailurePolicy: &batchv1.PodFailurePolicy{
+				Rules: []batchv1.PodFailurePolicyRule{{
+					Action: batchv1.PodFailurePolicyActionFailJob,
+					OnExitCodes: &batchv1.PodFailurePolicyOnExitCodesRequirement{
+						ContainerName: &container.Name,
+						Operator:      batchv1.PodFailurePolicyOnExitCodesOpIn,
+						Values:        []int32{exitCodeNotRetryable},
+					},
+				}},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       pod,
+			},
+		},
+	}
+	if err := ctrl.SetControllerReference(dj, job, r.Scheme); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *detectionJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&detectionv1alpha1.DetectionJob{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
+
+// runController is the controller mode of the server binary. JOB_IMAGE and
+// JOB_SERVICE_ACCOUNT set how Jobs run, LEADER_ELECT=true lets several
+// replicas run with one active.
+func runController(ctx context.Context) error {
+	ctrl.SetLogger(logr.FromSlogHandler(logger.Handler()))
+	scheme, err := newControllerScheme()
+	if err != nil {
+		return err
+	}
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+		Scheme:                 scheme,
+		Metrics:                metricsserver.Options{BindAddress: envOr("METRICS_ADDR", ":8081")},
+		HealthProbeBindAddress: envOr("HEALTH_PROBE_ADDR", ":8082"),
+		LeaderElection:         os.Getenv("LEADER_ELECT") == "true",
+		LeaderElectionID:       "detection-controller.detection.arkusnexus.com",
+	})
+	if err != nil {
+		return fmt.Errorf("error creating manager: %v", err)
+	}
+	r := &detectionJobReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Image:          envOr("JOB_IMAGE", defaultJobImage),
+		ServiceAccount: envOr("JOB_SERVICE_ACCOUNT", defaultJobServiceAccount),
+	}
+	if err := r.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("error setting up controller: %v", err)
+	}
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		return err
+	}
+	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		return err
+	}
+	logger.Info("controller is running", "image", r.Image)
+	return mgr.Start(ctx)
+}
+
+// envOr returns the environment variable key or def when it's unset.
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	detectionv1alpha1 "github.com/arkusnexus/ai-demo/server/k8s/v1alpha1"
+)
+
+func newTestReconciler(t *testing.T, objs ...client.Object) *detectionJobReconciler {
+	t.Helper()
+	scheme, err := newControllerScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&detectionv1alpha1.DetectionJob{}, &batchv1.Job{}).
+		WithObjects(objs...).
+		Build()
+	return &detectionJobReconciler{Client: c, Scheme: scheme, Image: "server:test", ServiceAccount: "detection-job"}
+}
+
+func testDetectionJob(name string) *detectionv1alpha1.DetectionJob {
+	return &detectionv1alpha1.DetectionJob{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Generation: 1},
+		Spec:       detectionv1alpha1.DetectionJobSpec{Source: "https://example.com/video.mp4", Model: "yolov8n", Highlights: true},
+	}
+}
+
+func reconcileJob(t *testing.T, r *detectionJobReconciler, name string) *detectionv1alpha1.DetectionJob {
+	t.Helper()
+	key := types.NamespacedName{Namespace: "default", Name: name}
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	dj := &detectionv1alpha1.DetectionJob{}
+	if err := r.Get(context.Background(), key, dj); err != nil {
+		t.Fatal(err)
+	}
+	return dj
+}
+
+func TestReconcileCreatesJobOnce(t *testing.T) {
+	r := newTestReconciler(t, testDetectionJob("street"))
+
+	dj := reconcileJob(t, r, "street")
+	if dj.Status.JobName != "street-detect" || dj.Status.Phase != detectionv1alpha1.PhasePending || dj.Status.ObservedGeneration != 1 {
+		t.Errorf("status = %+v", dj.Status)
+	}
+	job := &batchv1.Job{}
+	if err := r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "street-detect"}, job); err != nil {
+		t.Fatal(err)
+	}
+	if owner := metav1.GetControllerOf(job); owner == nil || owner.Kind != "DetectionJob" || owner.Name != "street" {
+		t.Errorf("job owner = %+v", owner)
+	}
+	container := job.Spec.Template.Spec.Containers[0]
+	args := strings.Join(container.Args, " ")
+	if container.Image != "server:test" || args != "run -source https://example.com/video.mp4 -model yolov8n -highlights" {
+		t.Errorf("container runs %v %v", container.Image, args)
+	}
+
+	// reconciling again finds the Job instead of creating another
+	reconcileJob(t, r, "street")
+	jobs := &batchv1.JobList{}
+	if err := r.List(context.Background(), jobs, client.InNamespace("default")); err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs.Items) != 1 {
+		t.Errorf("%v jobs after two reconciles", len(jobs.Items))
+	}
+}
+
+func TestReconcileFollowsJobStatus(t *testing.T) {
+	r := newTestReconciler(t, testDetectionJob("street"))
+	reconcileJob(t, r, "street")
+
+	key := client.ObjectKey{Namespace: "default", Name: "street-detect"}
+	job := &batchv1.Job{}
+	if err := r.Get(context.Background(), key, job); err != nil {
+		t.Fatal(err)
+	}
+	start := metav1.Now()
+	job.Status.StartTime, job.Status.Active = &start, 1
+	if err := r.Status().Update(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+	if dj := reconcileJob(t, r, "street"); dj.Status.Phase != detectionv1alpha1.PhaseRunning || dj.Status.StartTime == nil {
+		t.Errorf("active job: status = %+v", dj.Status)
+	}
+
+	job.Status.Active = 0
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded", LastTransitionTime: metav1.Now()}}
+	if err := r.Status().Update(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+	dj := reconcileJob(t, r, "street")
+	if dj.Status.Phase != detectionv1alpha1.PhaseFailed || dj.Status.Message != "BackoffLimitExceeded" || dj.Status.CompletionTime == nil {
+		t.Errorf("failed job: status = %+v", dj.Status)
+	}
+}
+
+func TestSyncJobStatus(t *testing.T) {
+	done := metav1.Now()
+	for _, tt := range []struct {
+		name    string
+		job     batchv1.JobStatus
+		message string
+		want    string
+		wantMsg string
+	}{
+		{name: "created", want: detectionv1alpha1.PhasePending},
+		{name: "active", job: batchv1.JobStatus{Active: 1}, want: detectionv1alpha1.PhaseRunning},
+		{name: "complete", job: batchv1.JobStatus{CompletionTime: &done, Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}}, want: detectionv1alpha1.PhaseSucceeded},
+		{name: "failed", job: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "DeadlineExceeded"}}}, want: detectionv1alpha1.PhaseFailed, wantMsg: "DeadlineExceeded"},
+		// the pod's own message wins over the Job condition
+		{name: "failed with message", job: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}}, message: "invalid_source", want: detectionv1alpha1.PhaseFailed, wantMsg: "invalid_source"},
+		{name: "condition not true", job: batchv1.JobStatus{Active: 1, Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionFalse}}}, want: detectionv1alpha1.PhaseRunning},
+	} {
+		status := detectionv1alpha1.DetectionJobStatus{Message: tt.message}
+		syncJobStatus(&status, &batchv1.Job{Status: tt.job})
+		if status.Phase != tt.want || status.Message != tt.wantMsg {
+			t.Errorf("%v: phase %q message %q, want %q %q", tt.name, status.Phase, status.Message, tt.want, tt.wantMsg)
+		}
+		if (tt.want == detectionv1alpha1.PhaseSucceeded || tt.want == detectionv1alpha1.PhaseFailed) && status.CompletionTime == nil {
+			t.Errorf("%v: no completion time", tt.name)
+		}
+	}
+}
+
+func TestDetectionJobNameTruncated(t *testing.T) {
+	long := strings.Repeat("a", 55) + "-" + strings.Repeat("b", 10)
+	name := detectionJobName(&detectionv1alpha1.DetectionJob{ObjectMeta: metav1.ObjectMeta{Name: long}})
+	if name != strings.Repeat("a", 55)+"-detect" {
+		t.Errorf("name = %q", name)
+	}
+	if len(name) > 63 {
+		t.Errorf("name is %v characters", len(name))
+	}
+	full := strings.Repeat("c", 56)
+	if name := detectionJobName(&detectionv1alpha1.DetectionJob{ObjectMeta: metav1.ObjectMeta{Name: full}}); name != full+"-detect" {
+		t.Errorf("56 character name = %q", name)
+	}
+}
+
+func TestStatusReporterClearsEarlierFailure(t *testing.T) {
+	dj := testDetectionJob("street")
+	dj.Status = detectionv1alpha1.DetectionJobStatus{Message: "model_load_failed: no weights", ErrorCode: "model_load_failed"}
+	r := newTestReconciler(t, dj)
+	key := types.NamespacedName{Namespace: "default", Name: "street"}
+	reporter := &statusReporter{client: r.Client, key: key}
+
+	reporter.update(func(s *detectionv1alpha1.DetectionJobStatus) {
+		s.JobID, s.Stage = "0123456789abcdef", "inference"
+	}, true)
+	got := &detectionv1alpha1.DetectionJob{}
+	if err := r.Get(context.Background(), key, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.Message != "" || got.Status.ErrorCode != "" || got.Status.Stage != "inference" {
+		t.Errorf("status after a successful retry = %+v", got.Status)
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// detection is a single bounding box found by yolo. The box is kept in the
+// normalized center format yolo writes to its label files so it can be
+// converted to any export format later on.
+type detection struct {
+	Frame      int     `json:"frame"`
+	Time       float64 `json:"time"`
+	ClassID    int     `json:"class_id"`
+	Class      string  `json:"class"`
+	X          float64 `json:"x"`
+	Y          float64 `json:"y"`
+	W          float64 `json:"w"`
+	H          float64 `json:"h"`
+	Confidence float64 `json:"confidence"`
+	// TrackID is set by yolo track, 0 means the box was not tracked.
+	TrackID int `json:"track_id,omitempty"`
+}
+
+// pixels returns the detection box as absolute xmin, ymin, xmax, ymax.
+func (d detection) pixels(width, height int) (float64, float64, float64, float64) {
+	w := d.W * float64(width)
+	h := d.H * float64(height)
+	xMin := d.X*float64(width) - w/2
+	yMin := d.Y*float64(height) - h/2
+	return xMin, yMin, xMin + w, yMin + h
+}
+
+// mediaInfo describes the media yolo produced for a job.
+type mediaInfo struct {
+	Name    string  `json:"name"`
+	Width   int     `json:"width"`
+	Height  int     `json:"height"`
+	FPS     float64 `json:"fps"`
+	Frames  int     `json:"frames"`
+	IsVideo bool    `json:"is_video"`
+}
+
+// frameName returns the image file name used for a frame in exported datasets.
+func (m mediaInfo) frameName(frame int) string {
+	if !m.IsVideo {
+		return m.Name + ".jpg"
+	}
+	return fmt.Sprintf("%v_%06d.jpg", m.Name, frame)
+}
+
+// probeMedia reads the dimensions and frame rate of a video or image using ffprobe.
+func probeMedia(path string) (mediaInfo, error) {
+	args := []string{"-v", "error", "-select_streams", "v:0", "-show_entries", "stream=width,height,r_frame_rate,nb_frames", "-of", "json", path}
+	out, err := exec.Command("ffprobe", args...).Output()
+	if err != nil {
+		return mediaInfo{}, fmt.Errorf("error probing %v: %v", path, err)
+	}
+
+	var probe struct {
+		Streams []struct {
+			Width      int    `json:"width"`
+			Height     int    `json:"height"`
+			FrameRate  string `json:"r_frame_rate"`
+			FrameCount string `json:"nb_frames"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return mediaInfo{}, fmt.Errorf("error parsing ffprobe output: %v", err)
+	}
+	if len(probe.Streams) == 0 {
+		return mediaInfo{}, fmt.Errorf("no video stream found in %v", path)
+	}
+
+	stream := probe.Streams[0]
+	info := mediaInfo{
+		Name:    strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Width:   stream.Width,
+		Height:  stream.Height,
+		IsVideo: isVideoFile(path),
+	}
+	if num, den, ok := strings.Cut(stream.FrameRate, "/"); ok {
+		n, _ := strconv.ParseFloat(num, 64)
+		d, _ := strconv.ParseFloat(den, 64)
+		if d > 0 {
+			info.FPS = n / d
+		}
+	}
+	info.Frames, _ = strconv.Atoi(stream.FrameCount)
+	return info, nil
+}
+
+func isVideoFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".avi", ".mp4", ".mkv", ".mov", ".webm":
+		return true
+	}
+	return false
+}
+
+// parseLabels reads the label files written by yolo with save_txt=True and
+// save_conf=True, yolo track appends the track id to each line. Video frames
+// are named <stem>_<frame>.txt with 1-based frame numbers; they are stored
+// 0-based.
+func parseLabels(labelsDir string, media mediaInfo, classes []string) ([]detection, error) {
+	entries, err := os.ReadDir(labelsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading labels: %v", err)
+	}
+
+	detections := []detection{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".txt" {
+			continue
+		}
+		frame := 0
+		stem := strings.TrimSuffix(entry.Name(), ".txt")
+		if i := strings.LastIndex(stem, "_"); i >= 0 && media.IsVideo {
+			if n, err := strconv.Atoi(stem[i+1:]); err == nil {
+				frame = n - 1
+			}
+		}
+
+		frameDetections, err := parseLabelFile(filepath.Join(labelsDir, entry.Name()), frame, media, classes)
+		if err != nil {
+			return nil, err
+		}
+		detections = append(detections, frameDetections...)
+	}
+
+	sort.SliceStable(detections, func(i, j int) bool {
+		return detections[i].Frame < detections[j].Frame
+	})
+	return detections, nil
+}
+
+func parseLabelFile(path string, frame int, media mediaInfo, classes []string) ([]detection, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening label file: %v", err)
+	}
+	defer file.Close()
+
+	detections := []detection{}
+	in := bufio.NewScanner(file)
+	for in.Scan() {
+		fields := strings.Fields(in.Text())
+		if len(fields) < 5 {
+			continue
+		}
+		values := make([]float64, len(fields))
+		for i, field := range fields {
+			values[i], err = strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q in %v", field, path)
+			}
+		}
+
+		d := detection{
+			Frame:      frame,
+			ClassID:    int(values[0]),
+			X:          values[1],
+			Y:          values[2],
+			W:          values[3],
+			H:          values[4],
+			Confidence: 1,
+		}
+		if len(values) > 5 {
+			d.Confidence = values[5]
+		}
+		if len(values) > 6 {
+			d.TrackID = int(values[6])
+		}
+		d.Class = className(classes, d.ClassID)
+		if media.FPS > 0 {
+			d.Time = float64(frame) / media.FPS
+		}
+		detections = append(detections, d)
+	}
+	return detections, in.Err()
+}
+
+func className(classes []string, id int) string {
+	if id >= 0 && id < len(classes) {
+		return classes[id]
+	}
+	return fmt.Sprintf("class_%v", id)
+}
+
+var (
+	classNamesMu    sync.Mutex
+	classNamesCache = map[string][]string{}
+)
+
+// modelClassNames asks ultralytics for the class names stored in the model weights.
+func modelClassNames(model string) []string {
+	classNamesMu.Lock()
+	defer classNamesMu.Unlock()
+	if names, ok := classNamesCache[model]; ok {
+		return names
+	}
+
+	script := "import json,sys;from ultralytics import YOLO;print(json.dumps(YOLO(sys.argv[1]).names))"
+	out, err := exec.Command("python3", "-c", script, model).Output()
+	if err != nil {
+		logger.Errorf("error reading class names from %v: %v", model, err)
+		return nil
+	}
+
+	raw := map[string]string{}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &raw); err != nil {
+		logger.Errorf("error parsing class names: %v", err)
+		return nil
+	}
+	names := make([]string, len(raw))
+	for key, name := range raw {
+		id, err := strconv.Atoi(key)
+		if err != nil || id < 0 || id >= len(names) {
+			continue
+		}
+		names[id] = name
+	}
+	classNamesCache[model] = names
+	return names
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPIDocument describes every HTTP route, the client in api/v1 is
+// generated from it.
+//
+//go:embed api/openapi.json
+var openAPIDocument []byte
+
+func openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPIDocument)
+}
+
+func docsHandler(w http.ResponseWriter, r *http.Request) {
+	http.ServeFile(w, r, "templates/docs.html")
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error codes are part of the API, clients switch on them, so they must not
+// change once published.
+const (
+	errCodeInvalidRequest  = "invalid_request"
+	errCodeInvalidSource   = "invalid_source"
+	errCodeDownloadFailed  = "download_failed"
+	errCodeModelLoadFailed = "model_load_failed"
+	errCodeInferenceFailed = "inference_failed"
+	errCodeTranscodeFailed = "transcode_failed"
+	errCodeInvalidDataset  = "invalid_dataset"
+	errCodeTrainingFailed  = "training_failed"
+	errCodeEvalFailed      = "evaluation_failed"
+	errCodeTimeout         = "timeout"
+	errCodeQuotaExceeded   = "quota_exceeded"
+	errCodeCancelled       = "cancelled"
+	errCodeInternal        = "internal"
+
+	defaultJobTimeout    = 2 * time.Hour
+	defaultJobsPerClient = 4
+)
+
+// errorKinds holds the message shown to users and whether running the same
+// job again can succeed.
+var errorKinds = map[string]struct {
+	Message   string
+	Retryable bool
+	Status    int
+}{
+	errCodeInvalidRequest:  {"The request is not valid.", false, http.StatusBadRequest},
+	errCodeInvalidSource:   {"The source could not be read, check the url or file.", false, http.StatusBadRequest},
+	errCodeDownloadFailed:  {"The source could not be downloaded.", true, http.StatusBadGateway},
+	errCodeModelLoadFailed: {"The model could not be loaded.", false, http.StatusInternalServerError},
+	errCodeInferenceFailed: {"Detection failed while running the model.", true, http.StatusInternalServerError},
+	errCodeTranscodeFailed: {"The annotated video could not be transcoded.", true, http.StatusInternalServerError},
+	errCodeInvalidDataset:  {"The dataset could not be read, check its data.yaml.", false, http.StatusBadRequest},
+	errCodeTrainingFailed:  {"Training failed while running the model.", true, http.StatusInternalServerError},
+	errCodeEvalFailed:      {"Evaluation failed while running the model.", true, http.StatusInternalServerError},
+	errCodeTimeout:         {"The job took too long and was stopped.", true, http.StatusGatewayTimeout},
+	errCodeQuotaExceeded:   {"Too many jobs are running, try again when one finishes.", true, http.StatusTooManyRequests},
+	errCodeCancelled:       {"The job was cancelled.", false, http.StatusConflict},
+	errCodeInternal:        {"Something went wrong on the server.", true, http.StatusInternalServerError},
+}
+
+// jobError is an error with a stable code, a message for users and the
+// underlying cause in Detail.
+type jobError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+	Detail    string `json:"detail,omitempty"`
+	err       error
+}
+
+func newJobError(code string, err error) *jobError {
+	kind, ok := errorKinds[code]
+	if !ok {
+		code, kind = errCodeInternal, errorKinds[errCodeInternal]
+	}
+	e := &jobError{Code: code, Message: kind.Message, Retryable: kind.Retryable, err: err}
+	if err != nil {
+		e.Detail = err.Error()
+	}
+	return e
+}
+
+func (e *jobError) Error() string {
+	if e.Detail == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%v %v", e.Message, e.Detail)
+}
+
+func (e *jobError) Unwrap() error {
+	return e.err
+}
+
+// httpStatus is the status API responses use for the error.
+func (e *jobError) httpStatus() int {
+	return errorKinds[e.Code].Status
+}
+
+// asJobError returns err as a jobError, classifying context errors and
+// treating anything else as internal.
+func asJobError(err error) *jobError {
+	var je *jobError
+	if errors.As(err, &je) {
+		return je
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return newJobError(errCodeTimeout, err)
+	case errors.Is(err, context.Canceled):
+		return newJobError(errCodeCancelled, err)
+	}
+	return newJobError(errCodeInternal, err)
+}
+
+// writeError writes err as {"error": jobError} with the status of its code.
+func writeError(w http.ResponseWriter, err error) {
+	je := asJobError(err)
+	writeJSON(w, je.httpStatus(), struct {
+		Error *jobError `json:"error"`
+	}{je})
+}
+
+// predictErrorPatterns map lines of yolo output to the error they mean, the
+// first match wins.
+var predictErrorPatterns = []struct {
+	pattern string
+	code    string
+}{
+	{"UnpicklingError", errCodeModelLoadFailed},
+	{"state_dict", errCodeModelLoadFailed},
+	{"is not a supported model", errCodeModelLoadFailed},
+	{"ConnectionError", errCodeDownloadFailed},
+	{"HTTPError", errCodeDownloadFailed},
+	{"URLError", errCodeDownloadFailed},
+	{"Download failure", errCodeDownloadFailed},
+	{"ERROR: [youtube]", errCodeDownloadFailed},
+	{"does not exist", errCodeInvalidSource},
+	{"No images or videos found", errCodeInvalidSource},
+	{"not a supported format", errCodeInvalidSource},
+	{"Failed to open", errCodeInvalidSource},
+}
+
+// classifyPredictError turns a failed yolo run into a jobError using the
+// last lines it printed.
+func classifyPredictError(ctx context.Context, err error, model string, tail []string) *jobError {
+	if ctx.Err() != nil {
+		return asJobError(ctx.Err())
+	}
+	for i := len(tail) - 1; i >= 0; i-- {
+		line := tail[i]
+		// a missing weights file reads like a missing source
+		if strings.Contains(line, "FileNotFoundError") && strings.Contains(line, model) {
+			return newJobError(errCodeModelLoadFailed, err)
+		}
+		for _, p := range predictErrorPatterns {
+			if strings.Contains(line, p.pattern) {
+				return newJobError(p.code, fmt.Errorf("%v: %v", err, strings.TrimSpace(line)))
+			}
+		}
+	}
+	return newJobError(errCodeInferenceFailed, err)
+}
+
+// checkSource rejects local paths that don't exist before yolo is started,
+// urls and device ids are left for yolo to open.
+func checkSource(source string) error {
+	if strings.Contains(source, "://") || !(strings.HasPrefix(source, "/") || strings.HasPrefix(source, ".")) {
+		return nil
+	}
+	if _, err := os.Stat(source); err != nil {
+		return newJobError(errCodeInvalidSource, err)
+	}
+	return nil
+}
+
+// checkModel makes sure local weights exist, bare names like yolov8n.pt are
+// downloaded by yolo.
+func checkModel(path string) error {
+	if !strings.ContainsRune(path, os.PathSeparator) {
+		return nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return newJobError(errCodeModelLoadFailed, err)
+	}
+	return nil
+}
+
+// jobTimeout is JOB_TIMEOUT as a duration, 0 disables it.
+func jobTimeout() time.Duration {
+	if v := os.Getenv("JOB_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return defaultJobTimeout
+}
+
+// jobsPerClient is how many jobs a client can run at once, JOBS_PER_CLIENT
+// overrides it and 0 disables the limit.
+func jobsPerClient() int {
+	if v := os.Getenv("JOBS_PER_CLIENT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return defaultJobsPerClient
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	evalSplitVal  = "val"
+	evalSplitTest = "test"
+	// evalResultPrefix marks the line evalScript prints its result on.
+	evalResultPrefix = "EVALUATION_RESULT "
+	// evalRunName is the folder in the job dir yolo saves the plots to.
+	evalRunName = "val"
+	// backgroundClass is the last label of the confusion matrix, objects no
+	// prediction matched and predictions that matched no object.
+	backgroundClass = "background"
+	// maxEvaluations is how many evaluations run at once, they share the GPU
+	// with detect jobs and trainings.
+	maxEvaluations = 1
+)
+
+// evaluationSlots holds a value per running evaluation.
+var evaluationSlots = make(chan struct{}, maxEvaluations)
+
+// evalScript runs yolo val through the python api, which unlike the cli
+// returns the per-class metrics and the confusion matrix, and prints them as
+// json.
+const evalScript = `import json, sys
+from ultralytics import YOLO
+model, data, split, imgsz, conf, iou, device, project = sys.argv[1:9]
+args = dict(data=data, split=split, imgsz=int(imgsz), iou=float(iou), project=project, name="` + evalRunName + `", exist_ok=True, plots=True)
+if float(conf) > 0:
+    args["conf"] = float(conf)
+if device:
+    args["device"] = device
+metrics = YOLO(model).val(**args)
+names = metrics.names
+classes = []
+for i, c in enumerate(metrics.ap_class_index):
+    p, r, map50, map50_95 = metrics.box.class_result(i)
+    classes.append({"class": names[int(c)], "precision": float(p), "recall": float(r), "map50": float(map50), "map50_95": float(map50_95)})
+print("` + evalResultPrefix + `" + json.dumps({
+    "precision": float(metrics.box.mp),
+    "recall": float(metrics.box.mr),
+    "map50": float(metrics.box.map50),
+    "map50_95": float(metrics.box.map),
+    "classes": classes,
+    "names": [names[i] for i in sorted(names)],
+    "matrix": metrics.confusion_matrix.matrix.tolist(),
+}))
+`
+
+// evaluationRequest starts an evaluate job.
+type evaluationRequest struct {
+	// Model is a model name from /models or a yolo model like yolov8n.pt,
+	// empty uses the default model.
+	Model string `json:"model"`
+	// Dataset is a data.yaml, a folder with one, the id of a frames job or
+	// the name of a dataset yolo knows like coco8.yaml.
+	Dataset string `json:"dataset"`
+	// Split is the split of the dataset to evaluate on, "val" or "test".
+	Split     string `json:"split"`
+	ImageSize int    `json:"image_size"`
+	// Confidence is the minimum confidence of predictions, 0 uses the yolo
+	// default which keeps nearly all of them as mAP needs.
+	Confidence float64 `json:"confidence"`
+	// IoU is the NMS threshold.
+	IoU    float64 `json:"iou"`
+	Device string  `json:"device"`
+}
+
+func (r *evaluationRequest) setDefaults() {
+	if r.Split == "" {
+		r.Split = evalSplitVal
+	}
+	if r.ImageSize <= 0 {
+		r.ImageSize = defaultTrainImageSize
+	}
+	if r.IoU <= 0 {
+		r.IoU = 0.7
+	}
+}
+
+// evaluation is the result of an evaluate job.
+type evaluation struct {
+	Dataset      string `json:"dataset"`
+	Split        string `json:"split"`
+	ModelVersion string `json:"model_version"`
+	detectionMetrics
+	Classes         []classMetrics   `json:"classes"`
+	ConfusionMatrix *confusionMatrix `json:"confusion_matrix,omitempty"`
+}
+
+// classMetrics are the metrics of the objects of a class.
+type classMetrics struct {
+	Class string `json:"class"`
+	// Instances is the number of objects of the class in the dataset.
+	Instances int `json:"instances"`
+	detectionMetrics
+}
+
+// confusionMatrix counts the objects of every class, Matrix[predicted][actual],
+// over Labels, the classes plus backgroundClass.
+type confusionMatrix struct {
+	Labels []string    `json:"labels"`
+	Matrix [][]float64 `json:"matrix"`
+}
+
+// evalOutput is what evalScript prints.
+type evalOutput struct {
+	detectionMetrics
+	Classes []classMetrics `json:"classes"`
+	Names   []string       `json:"names"`
+	Matrix  [][]float64    `json:"matrix"`
+}
+
+// evaluateJobHandler starts an evaluate job and returns it right away, the job
+// can be polled on GET /jobs/{id} and cancelled like any other. It counts
+// towards the JOBS_PER_CLIENT quota of the client.
+// POST /jobs/evaluate
+func evaluateJobHandler(w http.ResponseWriter, r *http.Request) {
+	req := evaluationRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, newJobError(errCodeInvalidRequest, err))
+		return
+	}
+	req.setDefaults()
+	if req.Dataset == "" {
+		writeError(w, newJobError(errCodeInvalidRequest, fmt.Errorf("dataset is required")))
+		return
+	}
+	if req.Split != evalSplitVal && req.Split != evalSplitTest {
+		writeError(w, newJobError(errCodeInvalidRequest, fmt.Errorf("unknown split: %q", req.Split)))
+		return
+	}
+	if req.Confidence < 0 || req.Confidence > 1 || req.IoU > 1 {
+		writeError(w, newJobError(errCodeInvalidRequest, fmt.Errorf("confidence and iou must be between 0 and 1")))
+		return
+	}
+	dataset, err := resolveDataset(req.Dataset)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	model, err := resolveWeights(req.Model)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	owner := clientID(w, r)
+	if limit := jobsPerClient(); limit > 0 && len(runningFeeds(owner)) >= limit {
+		writeError(w, newJobError(errCodeQuotaExceeded, fmt.Errorf("the limit is %v running jobs per client", limit)))
+		return
+	}
+	select {
+	case evaluationSlots <- struct{}{}:
+	default:
+		writeError(w, newJobError(errCodeQuotaExceeded, fmt.Errorf("%v evaluations are running", maxEvaluations)))
+		return
+	}
+
+	j := newJob(dataset)
+	j.Type = jobTypeEvaluate
+	j.Model = model
+	j.Confidence = req.Confidence
+	if err := j.save(); err != nil {
+		<-evaluationSlots
+		writeError(w, err)
+		return
+	}
+	// the feed bounds the job by JOB_TIMEOUT and lets it be cancelled
+	f := newJobFeed(j, owner)
+	go func() {
+		defer f.publish(feedEventDone, message{JobID: j.ID})
+		defer func() { <-evaluationSlots }()
+		j.publishStarted()
+		if err := j.finish(runEvaluation(f.ctx, j, req)); err != nil {
+			logger.Errorf("evaluate job %v failed: %v", j.ID, err)
+		}
+	}()
+	writeJSON(w, http.StatusAccepted, j)
+}
+
+// runEvaluation runs evalScript for the job and keeps its metrics and plots.
+func runEvaluation(ctx context.Context, j *job, req evaluationRequest) error {
+	ctx = withLogAttrs(ctx, "job_id", j.ID)
+	ctx, span := startSpan(ctx, "evaluation",
+		attribute.String("yolo.model", j.Model),
+		attribute.String("evaluation.dataset", j.Source),
+		attribute.String("evaluation.split", req.Split))
+	project, err := filepath.Abs(j.dir())
+	if err != nil {
+		endSpan(span, err)
+		return err
+	}
+
+	var result string
+	tail := []string{}
+	args := []string{"-c", evalScript, j.Model, j.Source, req.Split, fmt.Sprint(req.ImageSize), fmt.Sprint(req.Confidence), fmt.Sprint(req.IoU), req.Device, project}
+	err = executeCommandWithOutputLogs(ctx, func(line string) {
+		if after, ok := strings.CutPrefix(line, evalResultPrefix); ok {
+			result = after
+			return
+		}
+		tail = append(tail, line)
+		if len(tail) > predictErrorLines {
+			tail = tail[1:]
+		}
+	}, "python3", ultralyticsDir, args)
+	if err == nil && result == "" {
+		err = fmt.Errorf("yolo val printed no result")
+	}
+	if err != nil {
+		err = classifyDatasetError(ctx, err, tail, errCodeEvalFailed)
+		endSpan(span, err)
+		return err
+	}
+	endSpan(span, nil)
+
+	out := evalOutput{}
+	if err := json.Unmarshal([]byte(result), &out); err != nil {
+		return newJobError(errCodeEvalFailed, fmt.Errorf("error decoding result: %v", err))
+	}
+	j.Evaluation = newEvaluation(out, j.Source, req.Split, modelVersion(j.Model))
+	j.Classes = out.Names
+
+	plots, _ := filepath.Glob(filepath.Join(j.dir(), evalRunName, "*.png"))
+	sort.Strings(plots)
+	for _, plot := range plots {
+		name := filepath.Base(plot)
+		j.addArtifact(strings.TrimSuffix(name, ".png"), j.fileURL(evalRunName+"/"+name))
+	}
+	return nil
+}
+
+// newEvaluation fills in the instances of every class from the confusion
+// matrix, whose columns count the objects of a class.
+func newEvaluation(out evalOutput, dataset, split, version string) *evaluation {
+	e := &evaluation{
+		Dataset:          dataset,
+		Split:            split,
+		ModelVersion:     version,
+		detectionMetrics: out.detectionMetrics,
+		Classes:          out.Classes,
+	}
+	if e.Classes == nil {
+		e.Classes = []classMetrics{}
+	}
+	if len(out.Matrix) != len(out.Names)+1 {
+		return e
+	}
+	e.ConfusionMatrix = &confusionMatrix{
+		Labels: append(append([]string{}, out.Names...), backgroundClass),
+		Matrix: out.Matrix,
+	}
+	index := map[string]int{}
+	for i, name := range out.Names {
+		index[name] = i
+	}
+	for i, c := range e.Classes {
+		column, ok := index[c.Class]
+		if !ok {
+			continue
+		}
+		total := 0.0
+		for _, row := range out.Matrix {
+			if column < len(row) {
+				total += row[column]
+			}
+		}
+		e.Classes[i].Instances = int(total)
+	}
+	return e
+}
+
+// evaluationRow is a line of the comparison table.
+type evaluationRow struct {
+	JobID        string    `json:"job_id"`
+	Model        string    `json:"model"`
+	ModelVersion string    `json:"model_version"`
+	Dataset      string    `json:"dataset"`
+	Split        string    `json:"split"`
+	EvaluatedAt  time.Time `json:"evaluated_at"`
+	detectionMetrics
+	// Classes is the mAP50-95 of every class.
+	Classes map[string]float64 `json:"classes"`
+}
+
+// evaluationTable compares model versions on the same datasets.
+type evaluationTable struct {
+	// Classes is every class of the rows, sorted.
+	Classes []string        `json:"classes"`
+	Rows    []evaluationRow `json:"rows"`
+}
+
+// compareEvaluations builds the table from the latest evaluation of every
+// model version on every dataset and split. Rows are grouped by dataset and
+// best mAP50-95 first. An empty dataset or split matches all.
+func compareEvaluations(dataset, split string) (evaluationTable, error) {
+	jobs, err := listJobs(0)
+	if err != nil {
+		return evaluationTable{}, err
+	}
+	table := evaluationTable{Classes: []string{}, Rows: []evaluationRow{}}
+	seen := map[string]bool{}
+	classes := map[string]bool{}
+	// jobs are newest first, so the first one of a version wins
+	for _, j := range jobs {
+		e := j.Evaluation
+		if j.Type != jobTypeEvaluate || j.Status != jobStatusDone || e == nil {
+			continue
+		}
+		if (dataset != "" && e.Dataset != dataset) || (split != "" && e.Split != split) {
+			continue
+		}
+		key := e.ModelVersion + "\x00" + e.Dataset + "\x00" + e.Split
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		row := evaluationRow{
+			JobID:            j.ID,
+			Model:            modelName(j.Model),
+			ModelVersion:     e.ModelVersion,
+			Dataset:          e.Dataset,
+			Split:            e.Split,
+			EvaluatedAt:      j.CreatedAt,
+			detectionMetrics: e.detectionMetrics,
+			Classes:          map[string]float64{},
+		}
+		for _, c := range e.Classes {
+			row.Classes[c.Class] = c.MAP5095
+			classes[c.Class] = true
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	for class := range classes {
+		table.Classes = append(table.Classes, class)
+	}
+	sort.Strings(table.Classes)
+	sort.SliceStable(table.Rows, func(a, b int) bool {
+		ra, rb := table.Rows[a], table.Rows[b]
+		if ra.Dataset != rb.Dataset {
+			return ra.Dataset < rb.Dataset
+		}
+		if ra.Split != rb.Split {
+			return ra.Split < rb.Split
+		}
+		return ra.MAP5095 > rb.MAP5095
+	})
+	return table, nil
+}
+
+// evaluationsHandler returns the comparison table of model versions, as an
+// html page with format=html.
+// GET /evaluations
+func evaluationsHandler(w http.ResponseWriter, r *http.Request) {
+	dataset := r.URL.Query().Get("dataset")
+	if dataset != "" {
+		if resolved, err := resolveDataset(dataset); err == nil {
+			dataset = resolved
+		}
+	}
+	table, err := compareEvaluations(dataset, r.URL.Query().Get("split"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if r.URL.Query().Get("format") != "html" {
+		writeJSON(w, http.StatusOK, table)
+		return
+	}
+	tmpls, err := parsedTemplates()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpls.Lookup("evaluations.html").Execute(w, table); err != nil {
+		logger.Errorf("error rendering evaluations: %v", err)
+	}
+}
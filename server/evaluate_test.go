@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewEvaluation(t *testing.T) {
+	metrics := func(map5095 float64) detectionMetrics { return detectionMetrics{MAP5095: map5095} }
+	for _, tt := range []struct {
+		name          string
+		out           evalOutput
+		wantInstances map[string]int
+		wantMatrix    bool
+	}{
+		{
+			name: "instances from matrix columns",
+			out: evalOutput{
+				Classes: []classMetrics{{Class: "person", detectionMetrics: metrics(0.5)}, {Class: "car", detectionMetrics: metrics(0.4)}},
+				Names:   []string{"person", "car"},
+				// rows are predicted, columns actual, the last is background
+				Matrix: [][]float64{{8, 1, 2}, {0, 5, 1}, {2, 0, 0}},
+			},
+			wantInstances: map[string]int{"person": 10, "car": 6},
+			wantMatrix:    true,
+		},
+		{
+			name: "class without a column",
+			out: evalOutput{
+				Classes: []classMetrics{{Class: "bus"}},
+				Names:   []string{"person"},
+				Matrix:  [][]float64{{3, 0}, {1, 0}},
+			},
+			wantInstances: map[string]int{"bus": 0},
+			wantMatrix:    true,
+		},
+		{
+			name: "matrix of other classes is dropped",
+			out: evalOutput{
+				Classes: []classMetrics{{Class: "person"}},
+				Names:   []string{"person", "car"},
+				Matrix:  [][]float64{{3, 0}, {1, 0}},
+			},
+			wantInstances: map[string]int{"person": 0},
+		},
+		{name: "no classes", out: evalOutput{}, wantInstances: map[string]int{}},
+	} {
+		e := newEvaluation(tt.out, "/data/data.yaml", evalSplitVal, "v1")
+		if e.Dataset != "/data/data.yaml" || e.Split != evalSplitVal || e.ModelVersion != "v1" || e.Classes == nil {
+			t.Errorf("%v: evaluation = %+v", tt.name, e)
+		}
+		got := map[string]int{}
+		for _, c := range e.Classes {
+			got[c.Class] = c.Instances
+		}
+		if !reflect.DeepEqual(got, tt.wantInstances) {
+			t.Errorf("%v: instances = %v, want %v", tt.name, got, tt.wantInstances)
+		}
+		if (e.ConfusionMatrix != nil) != tt.wantMatrix {
+			t.Errorf("%v: confusion matrix = %+v", tt.name, e.ConfusionMatrix)
+		}
+		if e.ConfusionMatrix != nil && e.ConfusionMatrix.Labels[len(e.ConfusionMatrix.Labels)-1] != backgroundClass {
+			t.Errorf("%v: labels = %v", tt.name, e.ConfusionMatrix.Labels)
+		}
+	}
+}
+
+func TestCompareEvaluations(t *testing.T) {
+	chdirTemp(t)
+	start := time.Now()
+	saveEvaluation := func(age int, status, version, dataset, split string, map5095 float64) *job {
+		t.Helper()
+		j := newJob(dataset)
+		j.Type, j.Status, j.Model = jobTypeEvaluate, status, "/models/"+version+".pt"
+		j.CreatedAt = start.Add(-time.Duration(age) * time.Minute)
+		j.Evaluation = &evaluation{Dataset: dataset, Split: split, ModelVersion: version,
+			detectionMetrics: detectionMetrics{MAP5095: map5095},
+			Classes:          []classMetrics{{Class: "person", detectionMetrics: detectionMetrics{MAP5095: map5095}}}}
+		if err := j.save(); err != nil {
+			t.Fatal(err)
+		}
+		return j
+	}
+	newest := saveEvaluation(1, jobStatusDone, "a", "/street.yaml", evalSplitVal, 0.4)
+	saveEvaluation(2, jobStatusDone, "a", "/street.yaml", evalSplitVal, 0.9)
+	b := saveEvaluation(3, jobStatusDone, "b", "/street.yaml", evalSplitVal, 0.6)
+	saveEvaluation(0, jobStatusFailed, "c", "/street.yaml", evalSplitVal, 0.99)
+	test := saveEvaluation(4, jobStatusDone, "a", "/street.yaml", evalSplitTest, 0.3)
+	park := saveEvaluation(5, jobStatusDone, "a", "/park.yaml", evalSplitVal, 0.2)
+	detect := newJob("/street.mp4")
+	detect.Status = jobStatusDone
+	if err := detect.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		dataset, split string
+		want           []string
+	}{
+		// the newest evaluation of a version wins, rows are by dataset, split and best first
+		{"", "", []string{park.ID, test.ID, b.ID, newest.ID}},
+		{"/street.yaml", "", []string{test.ID, b.ID, newest.ID}},
+		{"/street.yaml", evalSplitTest, []string{test.ID}},
+		{"/other.yaml", "", []string{}},
+	} {
+		table, err := compareEvaluations(tt.dataset, tt.split)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, row := range table.Rows {
+			got = append(got, row.JobID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("dataset %q split %q: rows %v, want %v", tt.dataset, tt.split, got, tt.want)
+		}
+		if len(tt.want) > 0 && !reflect.DeepEqual(table.Classes, []string{"person"}) {
+			t.Errorf("classes = %v", table.Classes)
+		}
+	}
+}
+
+// startEvaluation posts an evaluation for client, it's cancelled and waited
+// for at the end of the test so it gives its slot back.
+func startEvaluation(t *testing.T, client string) *httptest.ResponseRecorder {
+	t.Helper()
+	body, _ := json.Marshal(evaluationRequest{Dataset: "coco8.yaml", Model: "yolov8n.pt"})
+	r := httptest.NewRequest(http.MethodPost, "/jobs/evaluate", bytes.NewReader(body))
+	r.AddCookie(&http.Cookie{Name: clientCookieName, Value: client})
+	w := httptest.NewRecorder()
+	evaluateJobHandler(w, r)
+	if w.Code == http.StatusAccepted {
+		j := job{}
+		json.Unmarshal(w.Body.Bytes(), &j)
+		t.Cleanup(func() { stopEvaluation(t, j.ID) })
+	}
+	return w
+}
+
+// stopEvaluation cancels an evaluation and waits for it to finish.
+func stopEvaluation(t *testing.T, id string) {
+	t.Helper()
+	cancelJob(id)
+	f := findFeed(id)
+	deadline := time.Now().Add(5 * time.Second)
+	for !f.finished() {
+		if time.Now().After(deadline) {
+			t.Fatalf("evaluation %v still running", id)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestEvaluateJobLimitsAndCancel(t *testing.T) {
+	chdirTemp(t)
+	fakeCommand(t, "python3", `exec sleep 30`)
+	t.Setenv("JOBS_PER_CLIENT", "1")
+	client, other := strings.Repeat("a", 16), strings.Repeat("b", 16)
+
+	w := startEvaluation(t, client)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("start: %v %v", w.Code, w.Body)
+	}
+	j := job{}
+	json.Unmarshal(w.Body.Bytes(), &j)
+	if w := startEvaluation(t, client); w.Code != http.StatusTooManyRequests || !strings.Contains(w.Body.String(), "per client") {
+		t.Errorf("second job of the client: %v %v", w.Code, w.Body)
+	}
+	if w := startEvaluation(t, other); w.Code != http.StatusTooManyRequests || !strings.Contains(w.Body.String(), "evaluations are running") {
+		t.Errorf("evaluation of another client: %v %v", w.Code, w.Body)
+	}
+
+	stopEvaluation(t, j.ID)
+	saved, err := loadJob(j.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Status != jobStatusCancelled {
+		t.Errorf("cancelled evaluation is %v", saved.Status)
+	}
+
+	// the slot is free again
+	if w := startEvaluation(t, other); w.Code != http.StatusAccepted {
+		t.Fatalf("start after cancel: %v %v", w.Code, w.Body)
+	}
+}
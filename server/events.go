@@ -0,0 +1,440 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/nats-io/nats.go"
+)
+
+// eventSchemaVersion is bumped on any breaking change to busEvent or its payloads.
+const eventSchemaVersion = 1
+
+const (
+	eventJobStarted   = "job.started"
+	eventJobFinished  = "job.finished"
+	eventJobFailed    = "job.failed"
+	eventJobCancelled = "job.cancelled"
+	eventDetections   = "detections"
+	eventRulePrefix   = "rule."
+
+	eventsOutboxDir = "./events/outbox"
+	deliveryTimeout = 10 * time.Second
+	// maxFramesPerEvent caps how many frames a detections event carries.
+	maxFramesPerEvent = 100
+)
+
+// busEvent is the envelope of every message published to the bus. Payload is
+// one of jobStartedPayload, jobFinishedPayload, jobFailedPayload (also used by
+// job.cancelled), detectionsPayload or ruleEvent depending on Type. Delivery is at-least-once so
+// consumers should drop ids they have already seen.
+type busEvent struct {
+	Version int         `json:"version"`
+	ID      string      `json:"id"`
+	Type    string      `json:"type"`
+	Time    time.Time   `json:"time"`
+	JobID   string      `json:"job_id"`
+	Source  string      `json:"source"`
+	Model   string      `json:"model"`
+	Payload interface{} `json:"payload"`
+}
+
+type jobStartedPayload struct {
+	Type    string `json:"type"`
+	Mode    string `json:"mode"`
+	Tracker string `json:"tracker,omitempty"`
+}
+
+type jobFinishedPayload struct {
+	Detections int            `json:"detections"`
+	Tracks     int            `json:"tracks"`
+	Counts     map[string]int `json:"counts"`
+	Events     int            `json:"events"`
+}
+
+type jobFailedPayload struct {
+	Error string `json:"error"`
+}
+
+// detectionsPayload is a batch of consecutive frames with detections.
+type detectionsPayload struct {
+	FirstFrame int            `json:"first_frame"`
+	LastFrame  int            `json:"last_frame"`
+	Frames     []framePayload `json:"frames"`
+}
+
+type framePayload struct {
+	Frame      int         `json:"frame"`
+	Time       float64     `json:"time"`
+	Detections []detection `json:"detections"`
+}
+
+// eventTransport sends a message and only returns nil once the broker has it.
+type eventTransport interface {
+	send(topic string, data []byte) error
+	close()
+}
+
+type mqttTransport struct {
+	client paho.Client
+}
+
+func newMQTTTransport(brokerURL string) (*mqttTransport, error) {
+	clientID := os.Getenv("EVENTS_CLIENT_ID")
+	if clientID == "" {
+		clientID = "ai-demo-server-" + newJobID()
+	}
+	opts := paho.NewClientOptions().
+		AddBroker(brokerURL).
+		SetClientID(clientID).
+		SetAutoReconnect(true).
+		SetConnectRetry(true)
+	client := paho.NewClient(opts)
+	// with connect retry the token only completes once connected, so don't
+	// block startup on a broker that is down
+	client.Connect()
+	return &mqttTransport{client: client}, nil
+}
+
+// send publishes with QoS 1 so the broker acknowledges every message.
+func (t *mqttTransport) send(topic string, data []byte) error {
+	if !t.client.IsConnectionOpen() {
+		return fmt.Errorf("mqtt broker not connected")
+	}
+	token := t.client.Publish(topic, 1, false, data)
+	if !token.WaitTimeout(deliveryTimeout) {
+		return fmt.Errorf("timed out publishing to %v", topic)
+	}
+	return token.Error()
+}
+
+func (t *mqttTransport) close() {
+	t.client.Disconnect(250)
+}
+
+type natsTransport struct {
+	conn *nats.Conn
+}
+
+func newNATSTransport(serverURL string) (*natsTransport, error) {
+	conn, err := nats.Connect(serverURL, nats.MaxReconnects(-1), nats.RetryOnFailedConnect(true))
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to nats: %v", err)
+	}
+	return &natsTransport{conn: conn}, nil
+}
+
+// send waits for the server to process the message with a flush.
+func (t *natsTransport) send(topic string, data []byte) error {
+	if err := t.conn.Publish(topic, data); err != nil {
+		return err
+	}
+	return t.conn.FlushTimeout(deliveryTimeout)
+}
+
+func (t *natsTransport) close() {
+	t.conn.Close()
+}
+
+// eventPublisher writes every event to a local outbox before sending it so
+// nothing is lost while the broker is unreachable; files are removed once
+// the broker acknowledges them.
+type eventPublisher struct {
+	transport eventTransport
+	topic     *template.Template
+	outbox    string
+	wake      chan struct{}
+	mu        sync.Mutex
+}
+
+// outboxEntry is the on-disk form of an event waiting to be delivered.
+type outboxEntry struct {
+	Topic string          `json:"topic"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// topicData is what EVENTS_TOPIC templates can use, values are made safe to
+// use as topic or subject tokens.
+type topicData struct {
+	Type   string
+	JobID  string
+	Source string
+	Model  string
+}
+
+var events *eventPublisher
+
+// setupEvents configures the publisher from the environment:
+//
+//	EVENTS_URL            mqtt://host:1883, tcp://host:1883 or nats://host:4222
+//	EVENTS_TOPIC          topic template, e.g. detections/{{.Source}}/{{.Model}}/{{.Type}}
+//	EVENTS_EMBEDDED_MQTT  address to run an in-process MQTT broker on, e.g. :1883
+func setupEvents() error {
+	if addr := os.Getenv("EVENTS_EMBEDDED_MQTT"); addr != "" {
+		if _, err := startEmbeddedBroker(addr); err != nil {
+			return err
+		}
+	}
+
+	brokerURL := os.Getenv("EVENTS_URL")
+	if brokerURL == "" {
+		return nil
+	}
+	parsed, err := url.Parse(brokerURL)
+	if err != nil {
+		return fmt.Errorf("invalid EVENTS_URL: %v", err)
+	}
+
+	var transport eventTransport
+	topic := os.Getenv("EVENTS_TOPIC")
+	switch parsed.Scheme {
+	case "mqtt", "tcp", "ssl", "mqtts", "ws", "wss":
+		transport, err = newMQTTTransport(brokerURL)
+		if topic == "" {
+			topic = "detections/{{.Source}}/{{.Model}}/{{.Type}}"
+		}
+	case "nats", "tls":
+		transport, err = newNATSTransport(brokerURL)
+		if topic == "" {
+			topic = "detections.{{.Source}}.{{.Model}}.{{.Type}}"
+		}
+	default:
+		return fmt.Errorf("unsupported events scheme: %q", parsed.Scheme)
+	}
+	if err != nil {
+		return err
+	}
+
+	events, err = newEventPublisher(transport, topic, eventsOutboxDir)
+	if err != nil {
+		return err
+	}
+	logger.Infof("publishing events to %v", parsed.Redacted())
+	return nil
+}
+
+// startEmbeddedBroker runs an MQTT broker inside the server, handy for local
+// testing without any infrastructure.
+func startEmbeddedBroker(addr string) (*mochi.Server, error) {
+	server := mochi.New(nil)
+	if err := server.AddHook(new(auth.AllowHook), nil); err != nil {
+		return nil, err
+	}
+	if err := server.AddListener(listeners.NewTCP(listeners.Config{ID: "embedded", Address: addr})); err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := server.Serve(); err != nil {
+			logger.Errorf("embedded mqtt broker stopped: %v", err)
+		}
+	}()
+	logger.Infof("embedded mqtt broker listening on %v", addr)
+	return server, nil
+}
+
+func newEventPublisher(transport eventTransport, topic, outbox string) (*eventPublisher, error) {
+	tmpl, err := template.New("topic").Parse(topic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid topic template: %v", err)
+	}
+	if err := os.MkdirAll(outbox, 0755); err != nil {
+		return nil, fmt.Errorf("error creating outbox: %v", err)
+	}
+	p := &eventPublisher{
+		transport: transport,
+		topic:     tmpl,
+		outbox:    outbox,
+		wake:      make(chan struct{}, 1),
+	}
+	go p.deliver()
+	return p, nil
+}
+
+var topicUnsafe = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+func topicToken(s string) string {
+	s = strings.Trim(topicUnsafe.ReplaceAllString(s, "_"), "_")
+	if len(s) > 64 {
+		s = s[:64]
+	}
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
+func (p *eventPublisher) publish(e busEvent) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		logger.Errorf("error encoding event: %v", err)
+		return
+	}
+	var topic bytes.Buffer
+	err = p.topic.Execute(&topic, topicData{
+		Type:   topicToken(e.Type),
+		JobID:  e.JobID,
+		Source: topicToken(e.Source),
+		Model:  topicToken(strings.TrimSuffix(filepath.Base(e.Model), filepath.Ext(e.Model))),
+	})
+	if err != nil {
+		logger.Errorf("error rendering topic: %v", err)
+		return
+	}
+
+	entry, err := json.Marshal(outboxEntry{Topic: topic.String(), Data: data})
+	if err != nil {
+		logger.Errorf("error encoding event: %v", err)
+		return
+	}
+	name := fmt.Sprintf("%020d-%v.json", e.Time.UnixNano(), e.ID)
+	p.mu.Lock()
+	err = os.WriteFile(filepath.Join(p.outbox, name), entry, 0644)
+	p.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error writing event to outbox: %v", err)
+		return
+	}
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// deliver sends outbox entries in order, retrying with a backoff while the
+// broker is unavailable.
+func (p *eventPublisher) deliver() {
+	delay := time.Second
+	for {
+		if p.flush() {
+			delay = time.Second
+			<-p.wake
+			continue
+		}
+		select {
+		case <-p.wake:
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, time.Minute)
+	}
+}
+
+// flush reports whether the outbox was fully delivered.
+func (p *eventPublisher) flush() bool {
+	names, err := filepath.Glob(filepath.Join(p.outbox, "*.json"))
+	if err != nil {
+		logger.Errorf("error reading outbox: %v", err)
+		return false
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			logger.Errorf("error reading %v: %v", name, err)
+			continue
+		}
+		entry := outboxEntry{}
+		if err := json.Unmarshal(data, &entry); err != nil {
+			logger.Errorf("dropping unreadable event %v: %v", name, err)
+			os.Remove(name)
+			continue
+		}
+		if err := p.transport.send(entry.Topic, entry.Data); err != nil {
+			logger.Errorf("error publishing event, will retry: %v", err)
+			return false
+		}
+		os.Remove(name)
+	}
+	return true
+}
+
+func newBusEvent(eventType, jobID, source, model string, payload interface{}) busEvent {
+	return busEvent{
+		Version: eventSchemaVersion,
+		ID:      newJobID(),
+		Type:    eventType,
+		Time:    time.Now().UTC(),
+		JobID:   jobID,
+		Source:  source,
+		Model:   model,
+		Payload: payload,
+	}
+}
+
+// publishEvent is a no-op when no bus is configured.
+func publishEvent(e busEvent) {
+	if events == nil {
+		return
+	}
+	events.publish(e)
+}
+
+// publishDetections emits the frames with detections in batches of up to
+// maxFramesPerEvent frames, so a long video doesn't flood the outbox.
+func publishDetections(jobID, source, model string, detections []detection) {
+	if events == nil {
+		return
+	}
+	frames := map[int][]detection{}
+	order := []int{}
+	for _, d := range detections {
+		if _, ok := frames[d.Frame]; !ok {
+			order = append(order, d.Frame)
+		}
+		frames[d.Frame] = append(frames[d.Frame], d)
+	}
+	sort.Ints(order)
+	for start := 0; start < len(order); start += maxFramesPerEvent {
+		batch := order[start:min(start+maxFramesPerEvent, len(order))]
+		payload := detectionsPayload{FirstFrame: batch[0], LastFrame: batch[len(batch)-1]}
+		for _, frame := range batch {
+			payload.Frames = append(payload.Frames, framePayload{Frame: frame, Time: frames[frame][0].Time, Detections: frames[frame]})
+		}
+		publishEvent(newBusEvent(eventDetections, jobID, source, model, payload))
+	}
+}
+
+// publishStarted announces a job before it runs.
+func (j *job) publishStarted() {
+	publishEvent(newBusEvent(eventJobStarted, j.ID, j.Source, j.Model, jobStartedPayload{
+		Type:    j.Type,
+		Mode:    j.Mode,
+		Tracker: j.Tracker,
+	}))
+}
+
+// publishResult emits the detections, rule events and final state of a job.
+func (j *job) publishResult() {
+	switch j.Status {
+	case jobStatusFailed:
+		publishEvent(newBusEvent(eventJobFailed, j.ID, j.Source, j.Model, jobFailedPayload{Error: j.Error}))
+		return
+	case jobStatusCancelled:
+		publishEvent(newBusEvent(eventJobCancelled, j.ID, j.Source, j.Model, jobFailedPayload{Error: j.Error}))
+		return
+	}
+	publishDetections(j.ID, j.Source, j.Model, j.Detections)
+	for _, e := range j.Events {
+		publishEvent(newBusEvent(eventRulePrefix+e.Type, j.ID, j.Source, j.Model, e))
+	}
+	publishEvent(newBusEvent(eventJobFinished, j.ID, j.Source, j.Model, jobFinishedPayload{
+		Detections: len(j.Detections),
+		Tracks:     len(j.Tracks),
+		Counts:     j.Counts,
+		Events:     len(j.Events),
+	}))
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+type receivedEvent struct {
+	topic string
+	event busEvent
+	raw   json.RawMessage
+}
+
+// freeAddr returns a local address nothing listens on.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// subscribeEvents connects to the broker at addr and forwards every message
+// under detections/ to the returned channel.
+func subscribeEvents(t *testing.T, addr string) chan receivedEvent {
+	t.Helper()
+	received := make(chan receivedEvent, 16)
+	opts := paho.NewClientOptions().AddBroker("tcp://" + addr).SetClientID("events-test")
+	client := paho.NewClient(opts)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		token := client.Connect()
+		if token.WaitTimeout(time.Second) && token.Error() == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("error connecting to the embedded broker: %v", token.Error())
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Cleanup(func() { client.Disconnect(0) })
+
+	token := client.Subscribe("detections/#", 1, func(_ paho.Client, m paho.Message) {
+		var payload struct {
+			busEvent
+			Payload json.RawMessage `json:"payload"`
+		}
+		if err := json.Unmarshal(m.Payload(), &payload); err != nil {
+			t.Errorf("error decoding event on %v: %v", m.Topic(), err)
+			return
+		}
+		received <- receivedEvent{topic: m.Topic(), event: payload.busEvent, raw: payload.Payload}
+	})
+	if !token.WaitTimeout(5*time.Second) || token.Error() != nil {
+		t.Fatalf("error subscribing: %v", token.Error())
+	}
+	return received
+}
+
+func nextEvent(t *testing.T, received chan receivedEvent) receivedEvent {
+	t.Helper()
+	select {
+	case e := <-received:
+		return e
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for an event")
+		return receivedEvent{}
+	}
+}
+
+func TestPublishJobEventsToEmbeddedBroker(t *testing.T) {
+	chdirTemp(t)
+	addr := freeAddr(t)
+	broker, err := startEmbeddedBroker(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+	received := subscribeEvents(t, addr)
+
+	transport, err := newMQTTTransport("tcp://" + addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err = newEventPublisher(transport, "detections/{{.Source}}/{{.Model}}/{{.Type}}", "outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		events = nil
+		transport.close()
+	}()
+
+	j := newJob("rtsp://cam/1")
+	j.Model = "/models/yolov8n.pt"
+	// enough frames for two detections events
+	for frame := 1; frame <= maxFramesPerEvent+20; frame++ {
+		j.Detections = append(j.Detections, detection{Frame: frame, Time: float64(frame) / 10, Class: "person", Confidence: 0.9})
+	}
+	j.Counts = classCounts(j.Detections, nil)
+	j.publishStarted()
+	if err := j.finish(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	const prefix = "detections/rtsp_cam_1/yolov8n/"
+	started := nextEvent(t, received)
+	if started.topic != prefix+topicToken(eventJobStarted) || started.event.Type != eventJobStarted {
+		t.Fatalf("first event %v on %v, want %v", started.event.Type, started.topic, eventJobStarted)
+	}
+	if started.event.Version != eventSchemaVersion || started.event.JobID != j.ID || started.event.Source != "rtsp://cam/1" {
+		t.Errorf("envelope = %+v", started.event)
+	}
+	startedPayload := jobStartedPayload{}
+	json.Unmarshal(started.raw, &startedPayload)
+	if startedPayload.Type != jobTypeDetect || startedPayload.Mode != predictModeDetect {
+		t.Errorf("job.started payload = %+v", startedPayload)
+	}
+
+	frames := 0
+	for _, want := range [][2]int{{1, maxFramesPerEvent}, {maxFramesPerEvent + 1, maxFramesPerEvent + 20}} {
+		e := nextEvent(t, received)
+		if e.topic != prefix+topicToken(eventDetections) {
+			t.Fatalf("got %v on %v, want detections", e.event.Type, e.topic)
+		}
+		payload := detectionsPayload{}
+		if err := json.Unmarshal(e.raw, &payload); err != nil {
+			t.Fatal(err)
+		}
+		if payload.FirstFrame != want[0] || payload.LastFrame != want[1] || len(payload.Frames) != want[1]-want[0]+1 {
+			t.Errorf("batch covers %v-%v with %v frames, want %v-%v", payload.FirstFrame, payload.LastFrame, len(payload.Frames), want[0], want[1])
+		}
+		frames += len(payload.Frames)
+	}
+	if frames != len(j.Detections) {
+		t.Errorf("%v frames published, want %v", frames, len(j.Detections))
+	}
+
+	finished := nextEvent(t, received)
+	if finished.topic != prefix+topicToken(eventJobFinished) {
+		t.Fatalf("got %v on %v, want %v", finished.event.Type, finished.topic, eventJobFinished)
+	}
+	finishedPayload := jobFinishedPayload{}
+	json.Unmarshal(finished.raw, &finishedPayload)
+	if finishedPayload.Detections != len(j.Detections) || finishedPayload.Counts["person"] != len(j.Detections) {
+		t.Errorf("job.finished payload = %+v", finishedPayload)
+	}
+}
+
+// flakyTransport fails every send until it's brought up.
+type flakyTransport struct {
+	mu   sync.Mutex
+	up   bool
+	sent []string
+}
+
+func (f *flakyTransport) send(topic string, data []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.up {
+		return errors.New("broker unreachable")
+	}
+	e := busEvent{}
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	f.sent = append(f.sent, e.ID)
+	return nil
+}
+
+func (f *flakyTransport) close() {}
+
+func (f *flakyTransport) setUp() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.up = true
+}
+
+func (f *flakyTransport) delivered() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.sent...)
+}
+
+func TestOutboxRedeliversAfterBrokerOutage(t *testing.T) {
+	outbox := t.TempDir()
+	transport := &flakyTransport{}
+	p, err := newEventPublisher(transport, "{{.Type}}", outbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []string{}
+	for _, eventType := range []string{eventJobStarted, eventJobFinished} {
+		e := newBusEvent(eventType, "job", "rtsp://cam/1", "m", nil)
+		ids = append(ids, e.ID)
+		p.publish(e)
+	}
+	if names, _ := filepath.Glob(filepath.Join(outbox, "*.json")); len(names) != 2 {
+		t.Fatalf("%v events in the outbox while the broker is down, want 2", len(names))
+	}
+	if sent := transport.delivered(); len(sent) != 0 {
+		t.Fatalf("%v events sent while the broker is down", len(sent))
+	}
+
+	// the next publish wakes the publisher, older events go out first
+	transport.setUp()
+	e := newBusEvent(eventJobStarted, "next", "rtsp://cam/1", "m", nil)
+	ids = append(ids, e.ID)
+	p.publish(e)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(transport.delivered()) < len(ids) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if sent := transport.delivered(); strings.Join(sent, ",") != strings.Join(ids, ",") {
+		t.Fatalf("delivered %v, want %v in order", sent, ids)
+	}
+	if names, _ := filepath.Glob(filepath.Join(outbox, "*.json")); len(names) != 0 {
+		t.Errorf("%v events left in the outbox", len(names))
+	}
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// exporter writes a job's detections into an archive under prefix.
+type exporter func(zw *zip.Writer, prefix string, j *job) error
+
+var exporters = map[string]exporter{
+	"coco": exportCOCO,
+	"yolo": exportYOLO,
+	"voc":  exportVOC,
+	"csv":  exportCSV,
+}
+
+var exportFormats = []string{"coco", "yolo", "voc", "csv"}
+
+// exportJobHandler serves every requested format for a single job as a zip.
+// GET /jobs/{id}/export?format=coco,yolo,voc,csv
+func exportJobHandler(w http.ResponseWriter, r *http.Request) {
+	j, err := loadJob(mux.Vars(r)["id"])
+	if err != nil {
+		logger.Errorf("error loading job: %v", err)
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+	writeExportArchive(w, r, fmt.Sprintf("job-%v.zip", j.ID), []*job{j})
+}
+
+// exportBatchHandler serves several jobs in one zip, one folder per job.
+// GET /export?jobs=id1,id2&format=coco,csv
+func exportBatchHandler(w http.ResponseWriter, r *http.Request) {
+	jobs, err := loadJobs(r.URL.Query().Get("jobs"))
+	if err != nil {
+		logger.Errorf("error loading jobs: %v", err)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	writeExportArchive(w, r, "jobs.zip", jobs)
+}
+
+func writeExportArchive(w http.ResponseWriter, r *http.Request, fileName string, jobs []*job) {
+	formats, err := parseExportFormats(r.URL.Query().Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// spool the archive to disk first so a failing exporter can still be
+	// reported with a proper status instead of a truncated zip, without
+	// holding a whole batch in memory
+	tmp, err := os.CreateTemp("", "export-*.zip")
+	if err != nil {
+		logger.Errorf("error creating archive: %v", err)
+		http.Error(w, "error exporting detections", http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	zw := zip.NewWriter(tmp)
+	for _, j := range jobs {
+		prefix := ""
+		if len(jobs) > 1 {
+			prefix = j.ID
+		}
+		for _, format := range formats {
+			if err := exporters[format](zw, prefix, j); err != nil {
+				logger.Errorf("error exporting job %v as %v: %v", j.ID, format, err)
+				http.Error(w, "error exporting detections", http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+	if err := zw.Close(); err != nil {
+		logger.Errorf("error closing archive: %v", err)
+		http.Error(w, "error exporting detections", http.StatusInternalServerError)
+		return
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		logger.Errorf("error reading archive: %v", err)
+		http.Error(w, "error exporting detections", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	if _, err := io.Copy(w, tmp); err != nil {
+		logger.Errorf("error writing archive: %v", err)
+	}
+}
+
+func parseExportFormats(raw string) ([]string, error) {
+	if raw == "" || raw == "all" {
+		return exportFormats, nil
+	}
+	formats := []string{}
+	for _, format := range strings.Split(raw, ",") {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if _, ok := exporters[format]; !ok {
+			return nil, fmt.Errorf("unknown export format: %q", format)
+		}
+		formats = append(formats, format)
+	}
+	return formats, nil
+}
+
+func createArchiveFile(zw *zip.Writer, name ...string) (io.Writer, error) {
+	return zw.Create(path.Join(name...))
+}
+
+// detectionsByFrame groups detections by frame keeping frames sorted.
+func detectionsByFrame(j *job) ([]int, map[int][]detection) {
+	frames := []int{}
+	byFrame := map[int][]detection{}
+	for _, d := range j.Detections {
+		if _, ok := byFrame[d.Frame]; !ok {
+			frames = append(frames, d.Frame)
+		}
+		byFrame[d.Frame] = append(byFrame[d.Frame], d)
+	}
+	sort.Ints(frames)
+	return frames, byFrame
+}
+
+// exportClasses returns the class list of a job, including ids that only
+// appear in detections when the model names could not be read.
+func exportClasses(j *job) []string {
+	classes := append([]string{}, j.Classes...)
+	for _, d := range j.Detections {
+		for len(classes) <= d.ClassID {
+			classes = append(classes, className(nil, len(classes)))
+		}
+	}
+	return classes
+}
+
+type cocoDataset struct {
+	Images      []cocoImage      `json:"images"`
+	Annotations []cocoAnnotation `json:"annotations"`
+	Categories  []cocoCategory   `json:"categories"`
+}
+
+type cocoImage struct {
+	ID       int    `json:"id"`
+	FileName string `json:"file_name"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+type cocoAnnotation struct {
+	ID         int       `json:"id"`
+	ImageID    int       `json:"image_id"`
+	CategoryID int       `json:"category_id"`
+	BBox       []float64 `json:"bbox"`
+	Area       float64   `json:"area"`
+	IsCrowd    int       `json:"iscrowd"`
+	Score      float64   `json:"score"`
+}
+
+type cocoCategory struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// exportCOCO writes coco/annotations.json. Category ids are the yolo class ids
+// plus one since COCO reserves 0 for the background.
+func exportCOCO(zw *zip.Writer, prefix string, j *job) error {
+	dataset := cocoDataset{
+		Images:      []cocoImage{},
+		Annotations: []cocoAnnotation{},
+		Categories:  []cocoCategory{},
+	}
+	for id, name := range exportClasses(j) {
+		dataset.Categories = append(dataset.Categories, cocoCategory{ID: id + 1, Name: name})
+	}
+
+	frames, byFrame := detectionsByFrame(j)
+	for i, frame := range frames {
+		imageID := i + 1
+		dataset.Images = append(dataset.Images, cocoImage{
+			ID:       imageID,
+			FileName: j.Media.frameName(frame),
+			Width:    j.Media.Width,
+			Height:   j.Media.Height,
+		})
+		for _, d := range byFrame[frame] {
+			xMin, yMin, xMax, yMax := d.pixels(j.Media.Width, j.Media.Height)
+			dataset.Annotations = append(dataset.Annotations, cocoAnnotation{
+				ID:         len(dataset.Annotations) + 1,
+				ImageID:    imageID,
+				CategoryID: d.ClassID + 1,
+				BBox:       []float64{xMin, yMin, xMax - xMin, yMax - yMin},
+				Area:       (xMax - xMin) * (yMax - yMin),
+				Score:      d.Confidence,
+			})
+		}
+	}
+
+	f, err := createArchiveFile(zw, prefix, "coco", "annotations.json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(dataset)
+}
+
+// yoloDataYAML describes a dataset laid out as images/ and labels/ folders.
+func yoloDataYAML(classes []string) string {
+	return "path: .\ntrain: images\nval: images\n" + yoloClassesYAML(classes)
+}
+
+// yoloClassesYAML lists the classes of a dataset in data.yaml format.
+func yoloClassesYAML(classes []string) string {
+	names := make([]string, len(classes))
+	for i, name := range classes {
+		names[i] = strconv.Quote(name)
+	}
+	return fmt.Sprintf("nc: %v\nnames: [%v]\n", len(classes), strings.Join(names, ", "))
+}
+
+// exportYOLO writes one label file per frame in the standard five column
+// format plus a data.yaml with the classes. The archive holds no images so
+// data.yaml has no train/val splits, a frames job builds a full dataset.
+func exportYOLO(zw *zip.Writer, prefix string, j *job) error {
+	f, err := createArchiveFile(zw, prefix, "yolo", "data.yaml")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, yoloClassesYAML(exportClasses(j))); err != nil {
+		return err
+	}
+
+	frames, byFrame := detectionsByFrame(j)
+	for _, frame := range frames {
+		labelName := strings.TrimSuffix(j.Media.frameName(frame), ".jpg") + ".txt"
+		f, err := createArchiveFile(zw, prefix, "yolo", "labels", labelName)
+		if err != nil {
+			return err
+		}
+		for _, d := range byFrame[frame] {
+			line := fmt.Sprintf("%d %.6f %.6f %.6f %.6f\n", d.ClassID, d.X, d.Y, d.W, d.H)
+			if _, err := io.WriteString(f, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type vocAnnotation struct {
+	XMLName  xml.Name    `xml:"annotation"`
+	Folder   string      `xml:"folder"`
+	Filename string      `xml:"filename"`
+	Size     vocSize     `xml:"size"`
+	Objects  []vocObject `xml:"object"`
+}
+
+type vocSize struct {
+	Width  int `xml:"width"`
+	Height int `xml:"height"`
+	Depth  int `xml:"depth"`
+}
+
+type vocObject struct {
+	Name       string  `xml:"name"`
+	Pose       string  `xml:"pose"`
+	Truncated  int     `xml:"truncated"`
+	Difficult  int     `xml:"difficult"`
+	Confidence float64 `xml:"confidence"`
+	BndBox     vocBox  `xml:"bndbox"`
+}
+
+type vocBox struct {
+	XMin int `xml:"xmin"`
+	YMin int `xml:"ymin"`
+	XMax int `xml:"xmax"`
+	YMax int `xml:"ymax"`
+}
+
+// exportVOC writes one Pascal VOC xml file per frame.
+func exportVOC(zw *zip.Writer, prefix string, j *job) error {
+	frames, byFrame := detectionsByFrame(j)
+	for _, frame := range frames {
+		imageName := j.Media.frameName(frame)
+		annotation := vocAnnotation{
+			Folder:   "JPEGImages",
+			Filename: imageName,
+			Size:     vocSize{Width: j.Media.Width, Height: j.Media.Height, Depth: 3},
+		}
+		for _, d := range byFrame[frame] {
+			xMin, yMin, xMax, yMax := d.pixels(j.Media.Width, j.Media.Height)
+			annotation.Objects = append(annotation.Objects, vocObject{
+				Name:       d.Class,
+				Pose:       "Unspecified",
+				Confidence: d.Confidence,
+				BndBox:     vocBox{XMin: int(xMin), YMin: int(yMin), XMax: int(xMax), YMax: int(yMax)},
+			})
+		}
+
+		f, err := createArchiveFile(zw, prefix, "voc", "Annotations", strings.TrimSuffix(imageName, ".jpg")+".xml")
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, xml.Header); err != nil {
+			return err
+		}
+		encoder := xml.NewEncoder(f)
+		encoder.Indent("", "  ")
+		if err := encoder.Encode(annotation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportCSV writes every detection as a flat row with its box in pixels.
+func exportCSV(zw *zip.Writer, prefix string, j *job) error {
+	f, err := createArchiveFile(zw, prefix, "detections.csv")
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	w.Write([]string{"frame", "time", "class", "x_min", "y_min", "x_max", "y_max", "confidence"})
+	for _, d := range j.Detections {
+		xMin, yMin, xMax, yMax := d.pixels(j.Media.Width, j.Media.Height)
+		w.Write([]string{
+			strconv.Itoa(d.Frame),
+			strconv.FormatFloat(d.Time, 'f', 3, 64),
+			d.Class,
+			strconv.FormatFloat(xMin, 'f', 1, 64),
+			strconv.FormatFloat(yMin, 'f', 1, 64),
+			strconv.FormatFloat(xMax, 'f', 1, 64),
+			strconv.FormatFloat(yMax, 'f', 1, 64),
+			strconv.FormatFloat(d.Confidence, 'f', 4, 64),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
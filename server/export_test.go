@@ -0,0 +1,47 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestExportJobArchive(t *testing.T) {
+	chdirTemp(t)
+	j := newJob("rtsp://cam/1")
+	j.Classes = []string{"person"}
+	j.Media = mediaInfo{Name: "cam", Width: 640, Height: 480, FPS: 10, Frames: 2, IsVideo: true}
+	j.Detections = []detection{{Frame: 1, Class: "person", X: 0.5, Y: 0.5, W: 0.1, H: 0.2, Confidence: 0.9}}
+	if err := j.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/jobs/"+j.ID+"/export?format=coco,csv", nil), map[string]string{"id": j.ID})
+	w := httptest.NewRecorder()
+	exportJobHandler(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %v %s", w.Code, w.Body)
+	}
+	if w.Header().Get("Content-Length") != strconv.Itoa(w.Body.Len()) {
+		t.Errorf("Content-Length %v for %v bytes", w.Header().Get("Content-Length"), w.Body.Len())
+	}
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zr.File) < 2 {
+		t.Errorf("archive has %v files", len(zr.File))
+	}
+
+	r = mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/jobs/"+j.ID+"/export?format=gif", nil), map[string]string{"id": j.ID})
+	w = httptest.NewRecorder()
+	exportJobHandler(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unknown format: got %v", w.Code)
+	}
+}
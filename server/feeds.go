@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	feedEventQueued   = "queued"
+	feedEventLog      = "log"
+	feedEventProgress = "progress"
+	feedEventRules    = "rules"
+	feedEventResult   = "result"
+	feedEventError    = "error"
+	feedEventDone     = "done"
+
+	// maxFeedEvents bounds the replay buffer, the oldest events are dropped.
+	maxFeedEvents = 5000
+	// feedRetention is how long a finished feed stays around for late
+	// reconnects before the job is only available from disk.
+	feedRetention = 30 * time.Minute
+	feedSubBuffer = 256
+
+	clientCookieName = "client_id"
+)
+
+// feedEvent is one update of a running job. Seq increases by one for every
+// event of a job so clients can resume after the last one they saw.
+type feedEvent struct {
+	Seq   int       `json:"seq"`
+	Type  string    `json:"type"`
+	JobID string    `json:"job_id"`
+	Time  time.Time `json:"time"`
+	Msg   message   `json:"message"`
+}
+
+// jobFeed buffers the events of a job and fans them out to subscribers, so a
+// job keeps running when its websocket goes away and can be picked up again.
+type jobFeed struct {
+	mu        sync.Mutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+	JobID     string
+	Owner     string
+	Source    string
+	CreatedAt time.Time
+	events    []feedEvent
+	next      int
+	subs      map[chan feedEvent]struct{}
+	done      bool
+}
+
+var feeds = struct {
+	sync.Mutex
+	m map[string]*jobFeed
+}{m: map[string]*jobFeed{}}
+
+// newJobFeed registers the feed of a job started by owner.
+func newJobFeed(j *job, owner string) *jobFeed {
+	ctx, cancel := context.WithCancel(context.Background())
+	if timeout := jobTimeout(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	}
+	f := &jobFeed{
+		ctx:       ctx,
+		cancel:    cancel,
+		JobID:     j.ID,
+		Owner:     owner,
+		Source:    j.Source,
+		CreatedAt: time.Now(),
+		next:      1,
+		subs:      map[chan feedEvent]struct{}{},
+	}
+	feeds.Lock()
+	feeds.m[j.ID] = f
+	feeds.Unlock()
+	return f
+}
+
+func findFeed(jobID string) *jobFeed {
+	feeds.Lock()
+	defer feeds.Unlock()
+	return feeds.m[jobID]
+}
+
+// runningFeeds lists the unfinished jobs of owner, oldest first.
+func runningFeeds(owner string) []*jobFeed {
+	feeds.Lock()
+	defer feeds.Unlock()
+	list := []*jobFeed{}
+	for _, f := range feeds.m {
+		if owner != "" && f.Owner == owner && !f.finished() {
+			list = append(list, f)
+		}
+	}
+	sort.Slice(list, func(a, b int) bool {
+		return list[a].CreatedAt.Before(list[b].CreatedAt)
+	})
+	return list
+}
+
+// cancelJob stops a running job, it returns false when the job is not running.
+func cancelJob(jobID string) bool {
+	f := findFeed(jobID)
+	if f == nil || f.finished() {
+		return false
+	}
+	f.cancel()
+	return true
+}
+
+func cancelJobHandler(w http.ResponseWriter, r *http.Request) {
+	if !cancelJob(mux.Vars(r)["id"]) {
+		http.Error(w, "job is not running", http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (f *jobFeed) finished() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.done
+}
+
+// publish appends an event and hands it to every subscriber. Subscribers that
+// fall behind are dropped instead of blocking the job, they resume from the
+// buffer.
+func (f *jobFeed) publish(eventType string, msg message) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.done {
+		return
+	}
+	e := feedEvent{Seq: f.next, Type: eventType, JobID: f.JobID, Time: time.Now(), Msg: msg}
+	f.next++
+	f.events = append(f.events, e)
+	if len(f.events) > maxFeedEvents {
+		f.events = f.events[len(f.events)-maxFeedEvents:]
+	}
+	for ch := range f.subs {
+		select {
+		case ch <- e:
+		default:
+			delete(f.subs, ch)
+			close(ch)
+		}
+	}
+	if eventType == feedEventDone {
+		f.done = true
+		f.cancel()
+		for ch := range f.subs {
+			delete(f.subs, ch)
+			close(ch)
+		}
+		time.AfterFunc(feedRetention, func() {
+			feeds.Lock()
+			delete(feeds.m, f.JobID)
+			feeds.Unlock()
+		})
+	}
+}
+
+// subscribe returns the buffered events after seq and a channel with the ones
+// that follow. The channel is closed when the job is done or the subscriber
+// was dropped.
+func (f *jobFeed) subscribe(after int) ([]feedEvent, chan feedEvent, func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	past := []feedEvent{}
+	for _, e := range f.events {
+		if e.Seq > after {
+			past = append(past, e)
+		}
+	}
+	ch := make(chan feedEvent, feedSubBuffer)
+	if f.done {
+		close(ch)
+		return past, ch, func() {}
+	}
+	f.subs[ch] = struct{}{}
+	cancel := func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if _, ok := f.subs[ch]; ok {
+			delete(f.subs, ch)
+			close(ch)
+		}
+	}
+	return past, ch, cancel
+}
+
+// watch sends every event after seq until the job is done, ctx is cancelled
+// or send fails. Dropped subscriptions are resumed from the last event sent.
+func (f *jobFeed) watch(ctx context.Context, after int, send func(feedEvent) error) error {
+	for {
+		past, ch, cancel := f.subscribe(after)
+		for _, e := range past {
+			if err := send(e); err != nil {
+				cancel()
+				return err
+			}
+			after = e.Seq
+		}
+	live:
+		for {
+			select {
+			case <-ctx.Done():
+				cancel()
+				return ctx.Err()
+			case e, ok := <-ch:
+				if !ok {
+					break live
+				}
+				if err := send(e); err != nil {
+					cancel()
+					return err
+				}
+				after = e.Seq
+			}
+		}
+		cancel()
+		if f.finished() {
+			// pick up events published between the drop and done
+			past, _, _ := f.subscribe(after)
+			for _, e := range past {
+				if err := send(e); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+}
+
+// frames renders an event into the html fragments the index page swaps in.
+func (e feedEvent) frames() [][]byte {
+	switch e.Type {
+	case feedEventQueued:
+		return [][]byte{getTemplate("templates/queued.html", e.Msg)}
+	case feedEventLog, feedEventError:
+		return [][]byte{getTemplate("templates/log.html", e.Msg)}
+	case feedEventProgress:
+		return [][]byte{getTemplate("templates/progress.html", e.Msg)}
+	case feedEventRules:
+		return [][]byte{getTemplate("templates/events.html", e.Msg)}
+	case feedEventResult:
+		return [][]byte{getTemplate("templates/video.html", e.Msg)}
+	case feedEventDone:
+		return [][]byte{getTemplate("templates/done.html", e.Msg), []byte("DONE.")}
+	}
+	return nil
+}
+
+// clientID returns the id stored in the client cookie, setting a new one
+// when w is not nil and the request has none.
+func clientID(w http.ResponseWriter, r *http.Request) string {
+	if c, err := r.Cookie(clientCookieName); err == nil && jobIDPattern.MatchString(c.Value) {
+		return c.Value
+	}
+	if w == nil {
+		return ""
+	}
+	id := newJobID()
+	http.SetCookie(w, &http.Cookie{
+		Name:     clientCookieName,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   int((365 * 24 * time.Hour).Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return id
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math/bits"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	frameModeInterval  = "interval"
+	frameModeScene     = "scene"
+	frameModeDetection = "detection"
+)
+
+// frameExtraction configures a frames job, which samples images from a video
+// into a dataset-ready folder:
+//
+//	dataset/images/*.jpg
+//	dataset/labels/*.txt  (when Labels is set)
+//	dataset/data.yaml
+type frameExtraction struct {
+	Source string `json:"source"`
+	// Mode is one of "interval", "scene" or "detection".
+	Mode string `json:"mode"`
+	// Interval is the seconds between sampled frames. In detection mode it is
+	// the minimum gap between two frames taken from the same detection run.
+	Interval float64 `json:"interval"`
+	// SceneThreshold is the ffmpeg scene score (0-1) that counts as a cut.
+	SceneThreshold float64 `json:"scene_threshold"`
+	// Dedup is the max hamming distance between frame hashes considered the
+	// same image, negative disables deduplication.
+	Dedup int `json:"dedup"`
+	// Labels pre-fills yolo labels for every frame with the current model.
+	Labels     bool    `json:"labels"`
+	Confidence float64 `json:"confidence"`
+}
+
+func (f *frameExtraction) setDefaults() {
+	if f.Mode == "" {
+		f.Mode = frameModeInterval
+	}
+	if f.Interval <= 0 {
+		f.Interval = 1
+	}
+	if f.SceneThreshold <= 0 {
+		f.SceneThreshold = 0.3
+	}
+	if f.Dedup == 0 {
+		f.Dedup = 5
+	}
+	if f.Confidence <= 0 {
+		f.Confidence = defaultConfidence
+	}
+}
+
+// framesJobHandler starts a frames job and returns its id right away, the job
+// can be polled on GET /jobs/{id}.
+// POST /jobs/frames
+func framesJobHandler(w http.ResponseWriter, r *http.Request) {
+	opts := frameExtraction{}
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	opts.setDefaults()
+	switch opts.Mode {
+	case frameModeInterval, frameModeScene, frameModeDetection:
+	default:
+		http.Error(w, fmt.Sprintf("unknown mode: %q", opts.Mode), http.StatusBadRequest)
+		return
+	}
+	if opts.Source == "" {
+		http.Error(w, "source is required", http.StatusBadRequest)
+		return
+	}
+	if err := checkSource(opts.Source); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	j := newJob(opts.Source)
+	j.Type = jobTypeFrames
+	j.Confidence = opts.Confidence
+	if err := j.save(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// the feed bounds the job by JOB_TIMEOUT and lets it be cancelled
+	f := newJobFeed(j, "")
+	go func() {
+		defer f.publish(feedEventDone, message{JobID: j.ID})
+		j.publishStarted()
+		if err := j.finish(runFrameExtraction(f.ctx, j, opts)); err != nil {
+			logger.Errorf("frames job %v failed: %v", j.ID, err)
+		}
+	}()
+	writeJSON(w, http.StatusAccepted, j)
+}
+
+func runFrameExtraction(ctx context.Context, j *job, opts frameExtraction) error {
+	imagesDir := filepath.Join(j.dir(), "dataset", "images")
+	labelsDir := filepath.Join(j.dir(), "dataset", "labels")
+	if err := os.MkdirAll(imagesDir, 0755); err != nil {
+		return fmt.Errorf("error creating dataset dir: %v", err)
+	}
+
+	var err error
+	switch opts.Mode {
+	case frameModeInterval:
+		err = sampleFrames(ctx, opts.Source, imagesDir, fmt.Sprintf("fps=1/%v", opts.Interval))
+	case frameModeScene:
+		err = sampleFrames(ctx, opts.Source, imagesDir, fmt.Sprintf("select='gt(scene,%v)'", opts.SceneThreshold))
+	case frameModeDetection:
+		err = sampleDetectionFrames(ctx, j, opts, imagesDir)
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.Dedup > 0 {
+		removed, err := dedupFrames(imagesDir, opts.Dedup)
+		if err != nil {
+			return err
+		}
+		logger.Infof("frames job %v: removed %v near-identical frames", j.ID, removed)
+	}
+
+	if opts.Labels {
+		if err := labelFrames(ctx, j, imagesDir, labelsDir); err != nil {
+			return err
+		}
+	}
+	if j.Classes == nil {
+		j.Classes = modelClassNames(j.Model)
+	}
+	dataYAML := yoloDataYAML(j.Classes)
+	if err := os.WriteFile(filepath.Join(j.dir(), "dataset", "data.yaml"), []byte(dataYAML), 0644); err != nil {
+		return fmt.Errorf("error writing data.yaml: %v", err)
+	}
+
+	j.addArtifact("dataset", fmt.Sprintf("/jobs/%v/dataset", j.ID))
+	return nil
+}
+
+// sampleFrames writes the frames passing the ffmpeg filter to imagesDir.
+func sampleFrames(ctx context.Context, source, imagesDir, filter string) error {
+	stem := strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+	args := []string{"-i", source, "-vf", filter, "-vsync", "vfr", "-q:v", "2", filepath.Join(imagesDir, stem+"_%06d.jpg")}
+	return executeCommand(ctx, "ffmpeg", ".", args)
+}
+
+// sampleDetectionFrames runs yolo on the source and keeps frames with
+// detections, at most one every Interval seconds. Remote sources are saved
+// first so the frames come from the media yolo ran on, without boxes.
+func sampleDetectionFrames(ctx context.Context, j *job, opts frameExtraction, imagesDir string) error {
+	if err := saveSource(ctx, j); err != nil {
+		return err
+	}
+	if err := j.runDetection(ctx, func(line string) {}); err != nil {
+		return err
+	}
+
+	frames, _ := detectionsByFrame(j)
+	last := -1.0
+	for _, frame := range frames {
+		at := float64(frame)
+		if j.Media.FPS > 0 {
+			at = float64(frame) / j.Media.FPS
+		}
+		if last >= 0 && at-last < opts.Interval {
+			continue
+		}
+		last = at
+		if err := extractFrame(ctx, j, frame, filepath.Join(imagesDir, j.Media.frameName(frame))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// labelFrames runs the current model over the dataset images and keeps the
+// label files it writes next to them.
+func labelFrames(ctx context.Context, j *job, imagesDir, labelsDir string) error {
+	project, err := filepath.Abs(j.dir())
+	if err != nil {
+		return err
+	}
+	source, err := filepath.Abs(imagesDir)
+	if err != nil {
+		return err
+	}
+	args := []string{
+		"detect",
+		"predict",
+		fmt.Sprintf("model='%v'", j.Model),
+		fmt.Sprintf("source='%v'", source),
+		fmt.Sprintf("conf=%v", j.Confidence),
+		"imgsz=640",
+		fmt.Sprintf("project='%v'", project),
+		"name='labels-run'",
+		"exist_ok=True",
+		"save=False",
+		"save_txt=True",
+	}
+	if err := executeCommandWithOutputLogs(ctx, func(line string) {}, "yolo", ultralyticsDir, args); err != nil {
+		return err
+	}
+
+	runDir := filepath.Join(j.dir(), "labels-run")
+	defer os.RemoveAll(runDir)
+	if err := os.RemoveAll(labelsDir); err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(runDir, "labels"), labelsDir); os.IsNotExist(err) {
+		return os.MkdirAll(labelsDir, 0755)
+	} else if err != nil {
+		return fmt.Errorf("error moving labels: %v", err)
+	}
+	return nil
+}
+
+// dedupFrames removes frames whose difference hash is within maxDistance bits
+// of a frame already kept, and returns how many were removed.
+func dedupFrames(imagesDir string, maxDistance int) (int, error) {
+	entries, err := os.ReadDir(imagesDir)
+	if err != nil {
+		return 0, err
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	kept := []uint64{}
+	removed := 0
+	for _, name := range names {
+		path := filepath.Join(imagesDir, name)
+		hash, err := differenceHash(path)
+		if err != nil {
+			logger.Errorf("error hashing %v: %v", path, err)
+			continue
+		}
+		duplicate := false
+		for _, other := range kept {
+			if bits.OnesCount64(hash^other) <= maxDistance {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			kept = append(kept, hash)
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// differenceHash is a 64 bit dHash: the image is reduced to 9x8 gray cells
+// and each bit tells whether a cell is brighter than its right neighbour.
+func differenceHash(path string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return 0, err
+	}
+
+	bounds := img.Bounds()
+	var cells [8][9]float64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 9; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/9
+			x1 := bounds.Min.X + (x+1)*bounds.Dx()/9
+			y0 := bounds.Min.Y + y*bounds.Dy()/8
+			y1 := bounds.Min.Y + (y+1)*bounds.Dy()/8
+			cells[y][x] = averageGray(img, x0, y0, x1, y1)
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if cells[y][x] > cells[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash, nil
+}
+
+// averageGray samples at most 8x8 pixels of a cell to keep hashing cheap.
+func averageGray(img image.Image, x0, y0, x1, y1 int) float64 {
+	stepX := max((x1-x0)/8, 1)
+	stepY := max((y1-y0)/8, 1)
+	total, count := 0.0, 0
+	for y := y0; y < y1; y += stepY {
+		for x := x0; x < x1; x += stepX {
+			r, g, b, _ := img.At(x, y).RGBA()
+			total += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
+
+// datasetHandler serves the dataset folder of a frames job as a zip.
+// GET /jobs/{id}/dataset
+func datasetHandler(w http.ResponseWriter, r *http.Request) {
+	j, err := loadJob(mux.Vars(r)["id"])
+	if err != nil || j.Type != jobTypeFrames {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+	if j.Status != jobStatusDone {
+		http.Error(w, fmt.Sprintf("job is %v", j.Status), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "dataset-"+j.ID+".zip"))
+	zw := zip.NewWriter(w)
+	if err := zipDir(zw, filepath.Join(j.dir(), "dataset"), "dataset"); err != nil {
+		logger.Errorf("error archiving dataset of job %v: %v", j.ID, err)
+		return
+	}
+	if err := zw.Close(); err != nil {
+		logger.Errorf("error closing archive: %v", err)
+	}
+}
+
+// zipDir adds every file under dir to the archive below prefix.
+func zipDir(zw *zip.Writer, dir, prefix string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w, err := createArchiveFile(zw, prefix, filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(w, f)
+		return err
+	})
+}
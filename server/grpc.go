@@ -0,0 +1,270 @@
+package main
+
+//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative proto/detection/v1/detection.proto
+
+import (
+	"context"
+	"net"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	detectionv1 "github.com/arkusnexus/ai-demo/server/proto/detection/v1"
+)
+
+const (
+	defaultGRPCAddr = ":9090"
+	// grpcClientIDKey is the metadata key clients set to share a quota
+	// across connections.
+	grpcClientIDKey = "client-id"
+)
+
+// detectionService implements the gRPC API on top of the same job feeds the
+// websocket and SSE handlers use.
+type detectionService struct {
+	detectionv1.UnimplementedDetectionServiceServer
+}
+
+// newGRPCServer returns a server with the detection service and reflection
+// registered.
+func newGRPCServer() *grpc.Server {
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(grpcUnaryTelemetry),
+		grpc.StreamInterceptor(grpcStreamTelemetry))
+	detectionv1.RegisterDetectionServiceServer(s, &detectionService{})
+	reflection.Register(s)
+	return s
+}
+
+// serveGRPC listens on GRPC_ADDR, or :9090 when unset.
+func serveGRPC() error {
+	addr := os.Getenv("GRPC_ADDR")
+	if addr == "" {
+		addr = defaultGRPCAddr
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	logger.Infof("gRPC server is running on %v", addr)
+	return newGRPCServer().Serve(lis)
+}
+
+func (s *detectionService) SubmitJob(ctx context.Context, req *detectionv1.SubmitJobRequest) (*detectionv1.SubmitJobResponse, error) {
+	f, err := startDetectJob(ctx, detectRequest{
+		Source:     req.Source,
+		Model:      req.Model,
+		Confidence: req.Confidence,
+		Mode:       req.Mode,
+		Tracker:    req.Tracker,
+		Profile:    req.Profile,
+		Highlights: req.Highlights,
+		Renditions: req.Renditions,
+	}, grpcClientID(ctx))
+	if err != nil {
+		return nil, grpcError(asJobError(err))
+	}
+	return &detectionv1.SubmitJobResponse{JobId: f.JobID}, nil
+}
+
+// grpcClientID identifies the caller for the per-client job quota, by the
+// client-id metadata when set and by the peer host otherwise.
+func grpcClientID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(grpcClientIDKey); len(ids) > 0 && ids[0] != "" {
+			return "grpc:" + ids[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		return "grpc:" + host
+	}
+	return ""
+}
+
+func (s *detectionService) WatchJob(req *detectionv1.WatchJobRequest, stream detectionv1.DetectionService_WatchJobServer) error {
+	f := findFeed(req.JobId)
+	if f == nil {
+		// the feed is gone, the result is still on disk
+		j, err := loadJob(req.JobId)
+		if err != nil {
+			return status.Errorf(codes.NotFound, "job %v not found", req.JobId)
+		}
+		if err := stream.Send(&detectionv1.JobEvent{JobId: j.ID, Type: detectionv1.EventType_EVENT_TYPE_RESULT, Time: timestamppb.Now(),
+			Payload: &detectionv1.JobEvent_Result{Result: jobResultProto(j)}}); err != nil {
+			return err
+		}
+		return stream.Send(&detectionv1.JobEvent{JobId: j.ID, Type: detectionv1.EventType_EVENT_TYPE_DONE, Time: timestamppb.Now()})
+	}
+
+	err := f.watch(stream.Context(), int(req.AfterSeq), func(e feedEvent) error {
+		return stream.Send(jobEventProto(e))
+	})
+	if err != nil && stream.Context().Err() != nil {
+		return status.FromContextError(err).Err()
+	}
+	return err
+}
+
+func (s *detectionService) GetResult(ctx context.Context, req *detectionv1.GetResultRequest) (*detectionv1.JobResult, error) {
+	if !jobIDPattern.MatchString(req.JobId) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid job id %q", req.JobId)
+	}
+	j, err := loadJob(req.JobId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "job %v not found", req.JobId)
+	}
+	return jobResultProto(j), nil
+}
+
+func (s *detectionService) ListModels(ctx context.Context, req *detectionv1.ListModelsRequest) (*detectionv1.ListModelsResponse, error) {
+	resp := &detectionv1.ListModelsResponse{}
+	for _, m := range listModels() {
+		resp.Models = append(resp.Models, &detectionv1.Model{Name: m.Name, Path: m.Path, Version: m.Version, Default: m.Default})
+	}
+	return resp, nil
+}
+
+func (s *detectionService) CancelJob(ctx context.Context, req *detectionv1.CancelJobRequest) (*detectionv1.CancelJobResponse, error) {
+	return &detectionv1.CancelJobResponse{Cancelled: cancelJob(req.JobId)}, nil
+}
+
+var eventTypes = map[string]detectionv1.EventType{
+	feedEventQueued:   detectionv1.EventType_EVENT_TYPE_QUEUED,
+	feedEventLog:      detectionv1.EventType_EVENT_TYPE_LOG,
+	feedEventProgress: detectionv1.EventType_EVENT_TYPE_PROGRESS,
+	feedEventRules:    detectionv1.EventType_EVENT_TYPE_RULES,
+	feedEventResult:   detectionv1.EventType_EVENT_TYPE_RESULT,
+	feedEventError:    detectionv1.EventType_EVENT_TYPE_ERROR,
+	feedEventDone:     detectionv1.EventType_EVENT_TYPE_DONE,
+}
+
+func jobEventProto(e feedEvent) *detectionv1.JobEvent {
+	pe := &detectionv1.JobEvent{Seq: int64(e.Seq), JobId: e.JobID, Type: eventTypes[e.Type], Time: timestamppb.New(e.Time)}
+	switch e.Type {
+	case feedEventLog:
+		logs := &detectionv1.LogLines{}
+		for _, line := range e.Msg.LogLines {
+			logs.Lines = append(logs.Lines, &detectionv1.LogLine{Time: timestamppb.New(line.Time), Level: line.Level, Text: line.Text})
+		}
+		pe.Payload = &detectionv1.JobEvent_Logs{Logs: logs}
+	case feedEventError:
+		pe.Payload = &detectionv1.JobEvent_Error{Error: e.Msg.LogLine}
+		pe.Failure = jobErrorProto(e.Msg.Error)
+	case feedEventProgress:
+		pe.Payload = &detectionv1.JobEvent_Progress{Progress: e.Msg.Progress}
+	case feedEventRules:
+		pe.Payload = &detectionv1.JobEvent_Rules{Rules: &detectionv1.RuleEvents{Events: ruleEventsProto(e.Msg.Events)}}
+	case feedEventResult:
+		if j, err := loadJob(e.JobID); err == nil {
+			pe.Payload = &detectionv1.JobEvent_Result{Result: jobResultProto(j)}
+		}
+	}
+	return pe
+}
+
+func jobResultProto(j *job) *detectionv1.JobResult {
+	msg := j.resultMessage()
+	r := &detectionv1.JobResult{
+		JobId:     j.ID,
+		Status:    j.Status,
+		Error:     j.Error,
+		Source:    j.Source,
+		Model:     j.Model,
+		Mode:      j.Mode,
+		CreatedAt: timestamppb.New(j.CreatedAt),
+		Media: &detectionv1.Media{
+			Name:    j.Media.Name,
+			Width:   int32(j.Media.Width),
+			Height:  int32(j.Media.Height),
+			Fps:     j.Media.FPS,
+			Frames:  int32(j.Media.Frames),
+			IsVideo: j.Media.IsVideo,
+		},
+		Counts:    map[string]int32{},
+		Events:    ruleEventsProto(j.Events),
+		VideoUrl:  msg.VideoURL,
+		ImageUrl:  msg.ImageURL,
+		ExportUrl: msg.ExportURL,
+		Failure:   jobErrorProto(j.Failure),
+	}
+	for class, count := range j.Counts {
+		r.Counts[class] = int32(count)
+	}
+	for _, d := range j.Detections {
+		r.Detections = append(r.Detections, &detectionv1.Detection{
+			Frame:      int32(d.Frame),
+			Time:       d.Time,
+			ClassId:    int32(d.ClassID),
+			Class:      d.Class,
+			X:          d.X,
+			Y:          d.Y,
+			W:          d.W,
+			H:          d.H,
+			Confidence: d.Confidence,
+			TrackId:    int32(d.TrackID),
+		})
+	}
+	for _, a := range j.Artifacts {
+		r.Artifacts = append(r.Artifacts, &detectionv1.Artifact{Name: a.Name, Url: a.URL})
+	}
+	return r
+}
+
+func jobErrorProto(e *jobError) *detectionv1.JobError {
+	if e == nil {
+		return nil
+	}
+	return &detectionv1.JobError{Code: e.Code, Message: e.Message, Retryable: e.Retryable, Detail: e.Detail}
+}
+
+var grpcCodes = map[string]codes.Code{
+	errCodeInvalidRequest:  codes.InvalidArgument,
+	errCodeInvalidSource:   codes.InvalidArgument,
+	errCodeModelLoadFailed: codes.FailedPrecondition,
+	errCodeQuotaExceeded:   codes.ResourceExhausted,
+	errCodeTimeout:         codes.DeadlineExceeded,
+	errCodeCancelled:       codes.Canceled,
+	errCodeDownloadFailed:  codes.Unavailable,
+}
+
+// grpcError converts a jobError into a status, the jobError code is kept in
+// the details.
+func grpcError(e *jobError) error {
+	code, ok := grpcCodes[e.Code]
+	if !ok {
+		code = codes.Internal
+	}
+	st, err := status.New(code, e.Error()).WithDetails(jobErrorProto(e))
+	if err != nil {
+		return status.Error(code, e.Error())
+	}
+	return st.Err()
+}
+
+func ruleEventsProto(events []ruleEvent) []*detectionv1.RuleEvent {
+	list := []*detectionv1.RuleEvent{}
+	for _, e := range events {
+		list = append(list, &detectionv1.RuleEvent{
+			RuleId:     e.RuleID,
+			RuleName:   e.RuleName,
+			Type:       e.Type,
+			TrackId:    int32(e.TrackID),
+			Class:      e.Class,
+			Frame:      int32(e.Frame),
+			Time:       e.Time,
+			Confidence: e.Confidence,
+			Direction:  e.Direction,
+		})
+	}
+	return list
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	detectionv1 "github.com/arkusnexus/ai-demo/server/proto/detection/v1"
+)
+
+// newTestGRPCClient serves newGRPCServer over an in-memory listener.
+func newTestGRPCClient(t *testing.T) detectionv1.DetectionServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := newGRPCServer()
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return detectionv1.NewDetectionServiceClient(conn)
+}
+
+// recordingTransport hands every published event to a channel.
+type recordingTransport struct {
+	sent chan busEvent
+}
+
+func (r *recordingTransport) send(topic string, data []byte) error {
+	e := busEvent{}
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	r.sent <- e
+	return nil
+}
+
+func (r *recordingTransport) close() {}
+
+func recordEvents(t *testing.T) chan busEvent {
+	t.Helper()
+	transport := &recordingTransport{sent: make(chan busEvent, 16)}
+	p, err := newEventPublisher(transport, "{{.Type}}", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	events = p
+	t.Cleanup(func() { events = nil })
+	return transport.sent
+}
+
+func watchUntilDone(t *testing.T, client detectionv1.DetectionServiceClient, jobID string, after int64) []*detectionv1.JobEvent {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := client.WatchJob(ctx, &detectionv1.WatchJobRequest{JobId: jobID, AfterSeq: after})
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := []*detectionv1.JobEvent{}
+	for {
+		e, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("watch %v: %v", jobID, err)
+		}
+		list = append(list, e)
+		if e.Type == detectionv1.EventType_EVENT_TYPE_DONE {
+			return list
+		}
+	}
+}
+
+func TestGRPCSubmitWatchAndCancel(t *testing.T) {
+	chdirTemp(t)
+	streamTestModel(t)
+	// queued jobs wait for a worker until they're cancelled
+	t.Setenv("JOB_DISPATCH", dispatchRemote)
+	sent := recordEvents(t)
+	client := newTestGRPCClient(t)
+	ctx := context.Background()
+
+	submitted, err := client.SubmitJob(ctx, &detectionv1.SubmitJobRequest{Source: "rtsp://camera/stream", Model: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream, err := client.WatchJob(ctx, &detectionv1.WatchJobRequest{JobId: submitted.JobId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	queued, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queued.Type != detectionv1.EventType_EVENT_TYPE_QUEUED || queued.Seq != 1 {
+		t.Fatalf("first event = %v seq %v, want queued seq 1", queued.Type, queued.Seq)
+	}
+
+	cancelled, err := client.CancelJob(ctx, &detectionv1.CancelJobRequest{JobId: submitted.JobId})
+	if err != nil || !cancelled.Cancelled {
+		t.Fatalf("cancel: %v, %v", cancelled, err)
+	}
+	all := watchUntilDone(t, client, submitted.JobId, 0)
+	var failure *detectionv1.JobError
+	for _, e := range all {
+		if e.Type == detectionv1.EventType_EVENT_TYPE_ERROR {
+			failure = e.Failure
+		}
+	}
+	if failure == nil || failure.Code != errCodeCancelled {
+		t.Errorf("failure = %v, want %v", failure, errCodeCancelled)
+	}
+
+	// resuming after the queued event replays everything but it
+	resumed := watchUntilDone(t, client, submitted.JobId, queued.Seq)
+	if len(resumed) != len(all)-1 || resumed[0].Seq != queued.Seq+1 {
+		t.Errorf("resumed %v events from seq %v, want %v from seq %v", len(resumed), resumed[0].Seq, len(all)-1, queued.Seq+1)
+	}
+
+	if again, err := client.CancelJob(ctx, &detectionv1.CancelJobRequest{JobId: submitted.JobId}); err != nil || again.Cancelled {
+		t.Errorf("cancelling a finished job: %v, %v", again, err)
+	}
+	result, err := client.GetResult(ctx, &detectionv1.GetResultRequest{JobId: submitted.JobId})
+	if err != nil || result.Status != jobStatusCancelled {
+		t.Errorf("result: %v, %v", result, err)
+	}
+
+	for {
+		select {
+		case e := <-sent:
+			if e.Type == eventJobCancelled && e.JobID == submitted.JobId {
+				return
+			}
+			if e.Type == eventJobFinished || e.Type == eventJobFailed {
+				t.Fatalf("got %v for a cancelled job", e.Type)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("job.cancelled was not published")
+		}
+	}
+}
+
+func TestGRPCSubmitQuotaPerClient(t *testing.T) {
+	chdirTemp(t)
+	streamTestModel(t)
+	t.Setenv("JOB_DISPATCH", dispatchRemote)
+	t.Setenv("JOBS_PER_CLIENT", "1")
+	client := newTestGRPCClient(t)
+
+	submit := func(clientID string) (*detectionv1.SubmitJobResponse, error) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), grpcClientIDKey, clientID)
+		return client.SubmitJob(ctx, &detectionv1.SubmitJobRequest{Source: "rtsp://camera/stream", Model: "m"})
+	}
+	first, err := submit("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancelJob(first.JobId)
+	if _, err := submit("a"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("second job of the same client: %v", err)
+	}
+	other, err := submit("b")
+	if err != nil {
+		t.Fatalf("job of another client: %v", err)
+	}
+	cancelJob(other.JobId)
+}
+
+func TestGRPCGetResultErrors(t *testing.T) {
+	chdirTemp(t)
+	client := newTestGRPCClient(t)
+	ctx := context.Background()
+
+	if _, err := client.GetResult(ctx, &detectionv1.GetResultRequest{JobId: "../etc"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("bad id: %v", err)
+	}
+	if _, err := client.GetResult(ctx, &detectionv1.GetResultRequest{JobId: newJobID()}); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown id: %v", err)
+	}
+	if _, err := client.SubmitJob(ctx, &detectionv1.SubmitJobRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("missing source: %v", err)
+	}
+}
+
+func TestGRPCErrorCodes(t *testing.T) {
+	for code, want := range map[string]codes.Code{
+		errCodeInvalidRequest:  codes.InvalidArgument,
+		errCodeQuotaExceeded:   codes.ResourceExhausted,
+		errCodeCancelled:       codes.Canceled,
+		errCodeTimeout:         codes.DeadlineExceeded,
+		errCodeInferenceFailed: codes.Internal,
+	} {
+		st := status.Convert(grpcError(&jobError{Code: code, Message: "boom"}))
+		if st.Code() != want {
+			t.Errorf("%v: got %v, want %v", code, st.Code(), want)
+		}
+		details := st.Details()
+		if len(details) != 1 {
+			t.Errorf("%v: %v details", code, len(details))
+			continue
+		}
+		if je, ok := details[0].(*detectionv1.JobError); !ok || je.Code != code {
+			t.Errorf("%v: details = %v", code, details[0])
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultHighlightPadding = 1.0
+	defaultHighlightMinGap  = 2.0
+	highlightReelName       = "highlights.mp4"
+)
+
+// highlightOptions controls how detections are turned into clips.
+type highlightOptions struct {
+	// Padding is the seconds kept before and after every interval.
+	Padding float64 `json:"padding"`
+	// MinGap is the shortest pause between detections that starts a new clip.
+	MinGap float64 `json:"min_gap"`
+	// Classes limits the clips to these classes, empty means all of them.
+	Classes       []string `json:"classes"`
+	MinConfidence float64  `json:"min_confidence"`
+}
+
+// newHighlightOptions returns the defaults, requests are decoded over them
+// so an explicit 0 padding or gap is kept.
+func newHighlightOptions() highlightOptions {
+	return highlightOptions{Padding: defaultHighlightPadding, MinGap: defaultHighlightMinGap}
+}
+
+func (o highlightOptions) validate() error {
+	if o.Padding < 0 {
+		return fmt.Errorf("padding can't be negative")
+	}
+	if o.MinGap < 0 {
+		return fmt.Errorf("min_gap can't be negative")
+	}
+	return nil
+}
+
+// highlightIntervals merges the detections matching opts into padded
+// intervals clamped to the video duration. Intervals last at least
+// minLength, one frame, so ffmpeg never gets an empty clip.
+func highlightIntervals(detections []detection, duration, minLength float64, opts highlightOptions) []interval {
+	selected := []detection{}
+	for _, d := range detections {
+		if len(opts.Classes) > 0 && !slices.ContainsFunc(opts.Classes, func(class string) bool { return strings.EqualFold(class, d.Class) }) {
+			continue
+		}
+		if d.Confidence < opts.MinConfidence {
+			continue
+		}
+		selected = append(selected, d)
+	}
+
+	intervals := []interval{}
+	for _, iv := range mergeIntervals(selected, opts.MinGap) {
+		iv.Start = max(iv.Start-opts.Padding, 0)
+		iv.End = max(iv.End+opts.Padding, iv.Start+minLength)
+		if duration > 0 {
+			iv.End = min(iv.End, duration)
+			iv.Start = max(min(iv.Start, iv.End-minLength), 0)
+		}
+		if iv.End <= iv.Start {
+			continue
+		}
+		// padding can make neighbours overlap
+		last := len(intervals) - 1
+		if last >= 0 && iv.Start <= intervals[last].End {
+			intervals[last].End = max(intervals[last].End, iv.End)
+			intervals[last].Count += iv.Count
+			intervals[last].MaxConfidence = max(intervals[last].MaxConfidence, iv.MaxConfidence)
+			continue
+		}
+		intervals = append(intervals, iv)
+	}
+	return intervals
+}
+
+// duration returns the length of the job video in seconds.
+func (j *job) duration() float64 {
+	if j.Media.FPS > 0 {
+		return float64(j.Media.Frames) / j.Media.FPS
+	}
+	return 0
+}
+
+// playableVideo returns the transcoded video of the job when there is one.
+func (j *job) playableVideo() string {
+	if j.VideoPath != "" {
+		return j.VideoPath
+	}
+	return j.OutputPath
+}
+
+// cutHighlights writes one clip per interval into the highlights dir plus a
+// reel of all of them, and lists them as artifacts.
+func (j *job) cutHighlights(ctx context.Context, opts highlightOptions) error {
+	if !j.Media.IsVideo {
+		return fmt.Errorf("job %v is not a video", j.ID)
+	}
+	dir := filepath.Join(j.dir(), "highlights")
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("error clearing highlights dir: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating highlights dir: %v", err)
+	}
+	j.Artifacts = slices.DeleteFunc(j.Artifacts, func(a artifact) bool {
+		return strings.HasPrefix(a.URL, j.fileURL("highlights/"))
+	})
+
+	minLength := 0.0
+	if j.Media.FPS > 0 {
+		minLength = 1 / j.Media.FPS
+	}
+	j.Highlights = highlightIntervals(j.Detections, j.duration(), minLength, opts)
+	if len(j.Highlights) == 0 {
+		return nil
+	}
+
+	source := j.playableVideo()
+	var list strings.Builder
+	for i, iv := range j.Highlights {
+		name := fmt.Sprintf("clip_%03d.mp4", i+1)
+		args := []string{"-y", "-ss", fmt.Sprintf("%.3f", iv.Start), "-i", source, "-t", fmt.Sprintf("%.3f", iv.End-iv.Start),
+			"-vcodec", "libx264", "-vprofile", "high", "-crf", "23", "-an", filepath.Join(dir, name)}
+		if err := executeCommand(ctx, "ffmpeg", ".", args); err != nil {
+			return fmt.Errorf("error cutting clip %v: %v", name, err)
+		}
+		fmt.Fprintf(&list, "file '%v'\n", name)
+		j.addArtifact(name, j.fileURL("highlights/"+name))
+	}
+
+	listPath := filepath.Join(dir, "clips.txt")
+	if err := os.WriteFile(listPath, []byte(list.String()), 0644); err != nil {
+		return fmt.Errorf("error writing clip list: %v", err)
+	}
+	defer os.Remove(listPath)
+	// the clips share codec settings so they can be joined without re-encoding
+	args := []string{"-y", "-f", "concat", "-safe", "0", "-i", listPath, "-c", "copy", filepath.Join(dir, highlightReelName)}
+	if err := executeCommand(ctx, "ffmpeg", ".", args); err != nil {
+		return fmt.Errorf("error joining clips: %v", err)
+	}
+	j.addArtifact(highlightReelName, j.fileURL("highlights/"+highlightReelName))
+	return nil
+}
+
+// highlightsURL returns the url of the highlight reel, empty if none was cut.
+func (j *job) highlightsURL() string {
+	if len(j.Highlights) == 0 {
+		return ""
+	}
+	return j.fileURL("highlights/" + highlightReelName)
+}
+
+// highlightsHandler re-cuts the highlights of a finished job with the
+// options in the request body.
+func highlightsHandler(w http.ResponseWriter, r *http.Request) {
+	j, err := loadJob(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+	opts := newHighlightOptions()
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil && err != io.EOF {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := opts.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := j.cutHighlights(r.Context(), opts); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := j.save(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, j)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHighlightIntervals(t *testing.T) {
+	at := func(class string, times ...float64) []detection {
+		list := []detection{}
+		for _, time := range times {
+			list = append(list, detection{Class: class, Time: time, Confidence: 0.9})
+		}
+		return list
+	}
+	noPadding := highlightOptions{MinGap: 2}
+	for _, tt := range []struct {
+		name       string
+		detections []detection
+		duration   float64
+		minLength  float64
+		opts       highlightOptions
+		want       [][2]float64
+	}{
+		{name: "padded and merged", detections: at("person", 1, 2, 8), duration: 20, minLength: 0.1,
+			opts: highlightOptions{Padding: 1, MinGap: 2}, want: [][2]float64{{0, 3}, {7, 9}}},
+		{name: "padding joins neighbours", detections: at("person", 2, 6), duration: 20, minLength: 0.1,
+			opts: highlightOptions{Padding: 2, MinGap: 1}, want: [][2]float64{{0, 8}}},
+		{name: "single frame lasts a frame", detections: at("person", 5), duration: 20, minLength: 0.1,
+			opts: noPadding, want: [][2]float64{{5, 5.1}}},
+		{name: "last frame stays inside the video", detections: at("person", 10), duration: 10, minLength: 0.1,
+			opts: noPadding, want: [][2]float64{{9.9, 10}}},
+		{name: "zero length without fps is skipped", detections: at("person", 5), duration: 0,
+			opts: noPadding, want: [][2]float64{}},
+		{name: "classes match case-insensitively", detections: append(at("Person", 1), at("car", 5)...), duration: 20, minLength: 0.1,
+			opts: highlightOptions{MinGap: 2, Padding: 0.5, Classes: []string{"person"}}, want: [][2]float64{{0.5, 1.5}}},
+	} {
+		got := highlightIntervals(tt.detections, tt.duration, tt.minLength, tt.opts)
+		if len(got) != len(tt.want) {
+			t.Errorf("%v: got %+v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i, iv := range got {
+			if math.Abs(iv.Start-tt.want[i][0]) > 1e-9 || math.Abs(iv.End-tt.want[i][1]) > 1e-9 {
+				t.Errorf("%v: interval %v is [%v, %v], want %v", tt.name, i, iv.Start, iv.End, tt.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,476 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ultralyticsDir is where yolo runs, the working dir of the ultralytics
+// image. Tests point it somewhere else.
+var ultralyticsDir = "/usr/src/ultralytics"
+
+const (
+	defaultModel      = "/server/best.pt"
+	defaultConfidence = 0.70
+	jobsDir           = "./static/jobs"
+	jobFileName       = "job.json"
+	defaultJobsLimit  = 50
+	uploadsDir        = "./uploads"
+	maxUploadMemory   = 32 << 20
+	predictErrorLines = 20
+)
+
+const (
+	jobTypeDetect = "detect"
+	jobTypeFrames = "frames"
+	// jobTypeEvaluate runs yolo val for a model on a dataset.
+	jobTypeEvaluate = "evaluate"
+
+	jobStatusRunning = "running"
+	jobStatusDone    = "done"
+	jobStatusFailed  = "failed"
+	// jobStatusCancelled is a job stopped with CancelJob.
+	jobStatusCancelled = "cancelled"
+)
+
+var jobIDPattern = regexp.MustCompile(`^[a-f0-9]{16}$`)
+
+// job holds everything produced by a single run. Its files live in
+// jobsDir/<id> so results can be downloaded after the websocket is gone.
+type job struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	Status     string      `json:"status"`
+	Error      string      `json:"error,omitempty"`
+	Failure    *jobError   `json:"failure,omitempty"`
+	Source     string      `json:"source"`
+	Model      string      `json:"model"`
+	Confidence float64     `json:"confidence"`
+	Mode       string      `json:"mode"`
+	Tracker    string      `json:"tracker,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+	Media      mediaInfo   `json:"media"`
+	Classes    []string    `json:"classes"`
+	Detections []detection `json:"detections"`
+	Tracks     []track     `json:"tracks,omitempty"`
+	// Counts is unique objects per class in track mode and detections per
+	// class otherwise.
+	Counts     map[string]int `json:"counts"`
+	Events     []ruleEvent    `json:"events,omitempty"`
+	Summary    *videoSummary  `json:"summary,omitempty"`
+	Highlights []interval     `json:"highlights,omitempty"`
+	OutputPath string         `json:"output_path"`
+	CacheKey   string         `json:"cache_key,omitempty"`
+	Cached     bool           `json:"cached,omitempty"`
+	// SourcePath is a copy of a remote source kept in the job dir by the jobs
+	// that read frames from it.
+	SourcePath string `json:"source_path,omitempty"`
+	// Profile is the transcoding profile of the playable video at VideoPath.
+	Profile    string     `json:"profile,omitempty"`
+	Renditions bool       `json:"renditions,omitempty"`
+	VideoPath  string     `json:"video_path,omitempty"`
+	Artifacts  []artifact `json:"artifacts,omitempty"`
+	// Evaluation is the result of an evaluate job.
+	Evaluation *evaluation `json:"evaluation,omitempty"`
+}
+
+// artifact is a downloadable file produced by a job.
+type artifact struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func newJob(source string) *job {
+	return &job{
+		ID:         newJobID(),
+		Type:       jobTypeDetect,
+		Status:     jobStatusRunning,
+		Source:     source,
+		Model:      defaultModel,
+		Confidence: defaultConfidence,
+		Mode:       predictModeDetect,
+		CreatedAt:  time.Now(),
+	}
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("error generating job id: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+func jobDir(id string) string {
+	return filepath.Join(jobsDir, id)
+}
+
+func (j *job) dir() string {
+	return jobDir(j.ID)
+}
+
+// saveUpload stores uploaded media under uploadsDir and returns its absolute
+// path, yolo runs from another directory.
+func saveUpload(src io.Reader, name string) (string, error) {
+	dir := filepath.Join(uploadsDir, newJobID())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("error creating upload dir: %v", err)
+	}
+	path, err := filepath.Abs(filepath.Join(dir, filepath.Base(name)))
+	if err != nil {
+		return "", err
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("error creating upload: %v", err)
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, src); err != nil {
+		return "", fmt.Errorf("error saving upload: %v", err)
+	}
+	return path, nil
+}
+
+// fileURL returns the static url of a file stored in the job dir.
+func (j *job) fileURL(name string) string {
+	return fmt.Sprintf("/static/jobs/%v/%v", j.ID, name)
+}
+
+// runDetection runs yolo for the job source and collects its detections.
+// Every line printed by yolo is handed to onLine.
+func (j *job) runDetection(ctx context.Context, onLine func(string)) error {
+	source := j.Source
+	if j.SourcePath != "" {
+		source = j.SourcePath
+	}
+	opts := predictOptions{
+		Model:      j.Model,
+		Source:     source,
+		Confidence: j.Confidence,
+		Mode:       j.Mode,
+		Tracker:    j.Tracker,
+	}
+	result, err := j.cachedPredict(ctx, opts, onLine)
+	if err != nil {
+		return err
+	}
+	j.OutputPath = result.OutputPath
+	j.Media = result.Media
+	j.Classes = result.Classes
+	j.Detections = result.Detections
+	if j.Mode == predictModeTrack {
+		j.Tracks = buildTracks(j.Detections)
+	}
+	j.Counts = classCounts(j.Detections, j.Tracks)
+	j.applyRules()
+	logger.ctx(ctx).Info("detections parsed", "job_id", j.ID, "detections", len(j.Detections), "tracks", len(j.Tracks))
+
+	return j.save()
+}
+
+// cachedPredict returns the result of an identical earlier run when the
+// cache has one, otherwise it runs predict and stores the result.
+func (j *job) cachedPredict(ctx context.Context, opts predictOptions, onLine func(string)) (predictResult, error) {
+	if !results.enabled() {
+		return predict(ctx, opts, j.dir(), onLine)
+	}
+	key, err := cacheKey(opts)
+	if errors.Is(err, errNotCacheable) {
+		logger.ctx(ctx).Info("not caching result", "job_id", j.ID, "source", opts.Source)
+		return predict(ctx, opts, j.dir(), onLine)
+	}
+	if err != nil {
+		logger.Errorf("error computing cache key: %v", err)
+		return predict(ctx, opts, j.dir(), onLine)
+	}
+	j.CacheKey = key
+
+	if entry, ok := results.get(key); ok {
+		onLine(fmt.Sprintf("using cached result from %v", entry.CreatedAt.Format(time.RFC3339)))
+		outputPath := filepath.Join(j.dir(), entry.OutputName)
+		if err := os.MkdirAll(j.dir(), 0755); err != nil {
+			return predictResult{}, fmt.Errorf("error creating job dir: %v", err)
+		}
+		if err := linkOrCopy(results.outputPath(entry), outputPath); err == nil {
+			j.Cached = true
+			return predictResult{OutputPath: outputPath, Media: entry.Media, Classes: entry.Classes, Detections: entry.Detections}, nil
+		}
+		logger.Errorf("error restoring cached output of %v, running predict", key)
+	}
+
+	result, err := predict(ctx, opts, j.dir(), onLine)
+	if err != nil {
+		return result, err
+	}
+	j.OutputPath = result.OutputPath
+	j.Media, j.Classes, j.Detections = result.Media, result.Classes, result.Detections
+	if err := results.put(key, j); err != nil {
+		logger.Errorf("error caching result of job %v: %v", j.ID, err)
+	}
+	return result, nil
+}
+
+// predictResult is what a yolo predict run left in its output dir.
+type predictResult struct {
+	OutputPath string
+	Media      mediaInfo
+	Classes    []string
+	Detections []detection
+}
+
+// predictOptions are the yolo parameters shared by every kind of run.
+type predictOptions struct {
+	Model      string
+	Source     string
+	Confidence float64
+	// Mode is "detect" for yolo predict or "track" for yolo track.
+	Mode string
+	// Tracker is the tracker config used in track mode.
+	Tracker string
+}
+
+// predict runs yolo on the source saving the annotated media and labels to outDir.
+func predict(ctx context.Context, opts predictOptions, outDir string, onLine func(string)) (predictResult, error) {
+	result := predictResult{}
+	outDir, err := filepath.Abs(outDir)
+	if err != nil {
+		return result, fmt.Errorf("error resolving output dir: %v", err)
+	}
+
+	args := []string{
+		"detect",
+		"predict",
+		fmt.Sprintf("model='%v'", opts.Model),
+		fmt.Sprintf("source='%v'", opts.Source),
+		fmt.Sprintf("conf=%v", opts.Confidence),
+		"imgsz=640",
+		fmt.Sprintf("project='%v'", filepath.Dir(outDir)),
+		fmt.Sprintf("name='%v'", filepath.Base(outDir)),
+		"exist_ok=True",
+		"save_txt=True",
+		"save_conf=True",
+	}
+	if opts.Mode == predictModeTrack {
+		args[1] = "track"
+		args = append(args, fmt.Sprintf("tracker='%v'", opts.Tracker))
+	}
+	if err := checkModel(opts.Model); err != nil {
+		return result, err
+	}
+	// the last lines tell why yolo failed
+	tail := []string{}
+	onOutput := func(line string) {
+		tail = append(tail, line)
+		if len(tail) > predictErrorLines {
+			tail = tail[1:]
+		}
+		onLine(line)
+	}
+	if err := runPredict(ctx, args, opts, onOutput); err != nil {
+		return result, classifyPredictError(ctx, err, opts.Model, tail)
+	}
+
+	_, span := startSpan(ctx, "video discovery", attribute.String("output.dir", outDir))
+	result.OutputPath, result.Media, err = discoverOutput(outDir)
+	endSpan(span, err)
+	if err != nil {
+		return result, err
+	}
+	result.Classes = modelClassNames(opts.Model)
+	result.Detections, err = parseLabels(filepath.Join(outDir, "labels"), result.Media, result.Classes)
+	return result, err
+}
+
+// runPredict runs the yolo command in an inference span. Remote sources get
+// a download span too, it lasts until yolo reports the first frame or image,
+// which is when it's done fetching the source.
+func runPredict(ctx context.Context, args []string, opts predictOptions, onLine func(string)) error {
+	ctx, span := startSpan(ctx, "inference",
+		attribute.String("yolo.mode", opts.Mode),
+		attribute.String("yolo.model", opts.Model),
+		attribute.Float64("yolo.confidence", opts.Confidence))
+	var download trace.Span
+	if strings.Contains(opts.Source, "://") {
+		_, download = startSpan(ctx, "download", attribute.String("url.full", opts.Source))
+	}
+	err := executeCommandWithOutputLogs(ctx, func(line string) {
+		if download != nil && predictProgressPattern.MatchString(line) {
+			download.End()
+			download = nil
+		}
+		onLine(line)
+	}, "yolo", ultralyticsDir, args)
+	if download != nil {
+		endSpan(download, err)
+	}
+	endSpan(span, err)
+	return err
+}
+
+// predictProgressPattern matches the line yolo prints per frame or image.
+var predictProgressPattern = regexp.MustCompile(`^(video|image) \d+/\d+`)
+
+// discoverOutput finds the media yolo saved in outDir and probes it.
+func discoverOutput(outDir string) (string, mediaInfo, error) {
+	outputPath, err := findVideoFile(outDir)
+	if err != nil {
+		return "", mediaInfo{}, newJobError(errCodeInferenceFailed, fmt.Errorf("error finding output file: %v", err))
+	}
+	media, err := probeMedia(outputPath)
+	if err != nil {
+		return "", mediaInfo{}, newJobError(errCodeInferenceFailed, err)
+	}
+	return outputPath, media, nil
+}
+
+func (j *job) addArtifact(name, url string) {
+	j.Artifacts = append(j.Artifacts, artifact{Name: name, URL: url})
+}
+
+// finish records the outcome of the job and saves it.
+func (j *job) finish(err error) error {
+	j.Status = jobStatusDone
+	if err != nil {
+		j.Status = jobStatusFailed
+		if errors.Is(err, context.Canceled) {
+			j.Status = jobStatusCancelled
+		}
+		j.Error = err.Error()
+		j.Failure = asJobError(err)
+	}
+	if saveErr := j.save(); saveErr != nil {
+		logger.Errorf("error saving job %v: %v", j.ID, saveErr)
+	}
+	errorCode := ""
+	if j.Failure != nil {
+		errorCode = j.Failure.Code
+	}
+	logger.Info("job finished", "job_id", j.ID, "type", j.Type, "status", j.Status, "error_code", errorCode)
+	j.publishResult()
+	return err
+}
+
+func (j *job) save() error {
+	if err := os.MkdirAll(j.dir(), 0755); err != nil {
+		return fmt.Errorf("error creating job dir: %v", err)
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding job: %v", err)
+	}
+	return os.WriteFile(filepath.Join(j.dir(), jobFileName), data, 0644)
+}
+
+func loadJob(id string) (*job, error) {
+	if !jobIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("invalid job id: %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(jobDir(id), jobFileName))
+	if err != nil {
+		return nil, fmt.Errorf("error reading job %v: %v", id, err)
+	}
+	j := &job{}
+	if err := json.Unmarshal(data, j); err != nil {
+		return nil, fmt.Errorf("error decoding job %v: %v", id, err)
+	}
+	return j, nil
+}
+
+// listJobs returns the saved jobs newest first, without their detections
+// and tracks.
+func listJobs(limit int) ([]*job, error) {
+	entries, err := os.ReadDir(jobsDir)
+	if os.IsNotExist(err) {
+		return []*job{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading jobs: %v", err)
+	}
+	jobs := []*job{}
+	for _, entry := range entries {
+		if !entry.IsDir() || !jobIDPattern.MatchString(entry.Name()) {
+			continue
+		}
+		j, err := loadJob(entry.Name())
+		if err != nil {
+			continue
+		}
+		j.Detections, j.Tracks = nil, nil
+		jobs = append(jobs, j)
+	}
+	sort.Slice(jobs, func(a, b int) bool {
+		return jobs[a].CreatedAt.After(jobs[b].CreatedAt)
+	})
+	if limit > 0 && len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+// listJobsHandler returns the latest jobs, limit defaults to 50.
+// GET /jobs
+func listJobsHandler(w http.ResponseWriter, r *http.Request) {
+	limit := defaultJobsLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	jobs, err := listJobs(limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, jobs)
+}
+
+// getJobHandler returns a job as json.
+// GET /jobs/{id}
+func getJobHandler(w http.ResponseWriter, r *http.Request) {
+	j, err := loadJob(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, j)
+}
+
+// loadJobs loads a comma separated list of job ids.
+func loadJobs(ids string) ([]*job, error) {
+	jobs := []*job{}
+	for _, id := range strings.Split(ids, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		j, err := loadJob(id)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("no job ids given")
+	}
+	return jobs, nil
+}
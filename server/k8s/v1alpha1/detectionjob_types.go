@@ -0,0 +1,142 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Phases of a DetectionJob.
+const (
+	PhasePending   = "Pending"
+	PhaseRunning   = "Running"
+	PhaseSucceeded = "Succeeded"
+	PhaseFailed    = "Failed"
+)
+
+// Stages reported in the status while the job runs.
+const (
+	StageInference = "inference"
+	StageTranscode = "transcode"
+	StageDone      = "done"
+)
+
+// DetectionJobSpec is the detect job to run, with the same fields the HTTP
+// and gRPC APIs take, plus how to run its pod.
+type DetectionJobSpec struct {
+	// Source is the url or path of the video or image.
+	// +kubebuilder:validation:MinLength=1
+	Source string `json:"source"`
+	// Model is the name or path of the weights, the server default when
+	// empty.
+	// +optional
+	Model string `json:"model,omitempty"`
+	// Confidence is the minimum detection confidence, between 0 and 1.
+	// +kubebuilder:validation:Pattern=`^(0(\.[0-9]+)?|1(\.0+)?)$`
+	// +optional
+	Confidence string `json:"confidence,omitempty"`
+	// Mode is detect or track.
+	// +kubebuilder:validation:Enum=detect;track
+	// +optional
+	Mode string `json:"mode,omitempty"`
+	// +optional
+	Tracker string `json:"tracker,omitempty"`
+	// Profile is the transcoding profile of the playable video.
+	// +optional
+	Profile string `json:"profile,omitempty"`
+	// +optional
+	Highlights bool `json:"highlights,omitempty"`
+	// +optional
+	Renditions bool `json:"renditions,omitempty"`
+
+	// Image overrides the server image the controller runs jobs with.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// OutputVolumeClaim is a PersistentVolumeClaim mounted as the jobs dir,
+	// so the artifacts outlive the pod. Artifact paths are relative to it.
+	// +optional
+	OutputVolumeClaim string `json:"outputVolumeClaim,omitempty"`
+	// Resources of the pod, set nvidia.com/gpu to run on a GPU node.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// BackoffLimit is how many times a pod that failed with a retryable
+	// error is retried, 1 by default.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+}
+
+// Artifact is a file produced by the job.
+type Artifact struct {
+	Name string `json:"name"`
+	// Path is relative to the output volume.
+	Path string `json:"path"`
+}
+
+// DetectionJobStatus is reported by the controller, phase and times, and by
+// the pod running the job, the rest.
+type DetectionJobStatus struct {
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// JobName is the Kubernetes Job running the detect job.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+	// JobID is the id of the detect job, the name of its dir in the output
+	// volume.
+	// +optional
+	JobID string `json:"jobID,omitempty"`
+	// Stage is inference, transcode or done.
+	// +optional
+	Stage string `json:"stage,omitempty"`
+	// Progress is the percent done of the stage.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Progress int32 `json:"progress,omitempty"`
+	// Message is why the job failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// ErrorCode is the code of the failure, see the HTTP API errors.
+	// +optional
+	ErrorCode string `json:"errorCode,omitempty"`
+	// +optional
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+	// Counts is objects per class.
+	// +optional
+	Counts map[string]int32 `json:"counts,omitempty"`
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// DetectionJob runs a detect job declared as a Kubernetes object.
+//
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=dj
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Stage",type=string,JSONPath=`.status.stage`
+// +kubebuilder:printcolumn:name="Progress",type=integer,JSONPath=`.status.progress`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type DetectionJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DetectionJobSpec   `json:"spec,omitempty"`
+	Status DetectionJobStatus `json:"status,omitempty"`
+}
+
+// DetectionJobList is a list of DetectionJobs.
+//
+// +kubebuilder:object:root=true
+type DetectionJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DetectionJob `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DetectionJob{}, &DetectionJobList{})
+}
@@ -0,0 +1,9 @@
+// Package v1alpha1 holds the DetectionJob custom resource, a detect job
+// declared as a Kubernetes object and run by the controller of the server
+// binary.
+//
+// +kubebuilder:object:generate=true
+// +groupName=detection.arkusnexus.com
+package v1alpha1
+
+//go:generate controller-gen object paths=./...
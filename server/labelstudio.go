@@ -0,0 +1,433 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Label Studio ML backend contract, see
+// https://labelstud.io/guide/ml_create. Label Studio is pointed at
+// http://<server>:8080/labelstudio and calls the routes below.
+func registerLabelStudioRoutes(router *mux.Router) {
+	ls := router.PathPrefix("/labelstudio").Subrouter()
+	ls.HandleFunc("/health", labelStudioHealthHandler).Methods("GET")
+	ls.HandleFunc("/setup", labelStudioSetupHandler).Methods("POST")
+	ls.HandleFunc("/predict", labelStudioPredictHandler).Methods("POST")
+	ls.HandleFunc("/webhook", labelStudioWebhookHandler).Methods("POST")
+}
+
+// labelStudioProject is what Label Studio tells us about a project on /setup.
+type labelStudioProject struct {
+	ID           string
+	Hostname     string
+	AccessToken  string
+	ModelVersion string
+	Config       labelConfig
+}
+
+var (
+	labelStudioMu       sync.Mutex
+	labelStudioProjects = map[string]*labelStudioProject{}
+)
+
+// labelConfig holds the parts of a Label Studio labeling config we need to
+// build RectangleLabels predictions.
+type labelConfig struct {
+	FromName string
+	ToName   string
+	DataKey  string
+	Labels   []string
+}
+
+func parseLabelConfig(raw string) labelConfig {
+	config := labelConfig{FromName: "label", ToName: "image", DataKey: "image"}
+	if raw == "" {
+		return config
+	}
+
+	var view struct {
+		Images []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:"value,attr"`
+		} `xml:"Image"`
+		Rectangles []struct {
+			Name   string `xml:"name,attr"`
+			ToName string `xml:"toName,attr"`
+			Labels []struct {
+				Value string `xml:"value,attr"`
+			} `xml:"Label"`
+		} `xml:"RectangleLabels"`
+	}
+	if err := xml.Unmarshal([]byte(raw), &view); err != nil {
+		logger.Errorf("error parsing label config: %v", err)
+		return config
+	}
+
+	if len(view.Rectangles) > 0 {
+		rect := view.Rectangles[0]
+		config.FromName = rect.Name
+		config.ToName = rect.ToName
+		for _, label := range rect.Labels {
+			config.Labels = append(config.Labels, label.Value)
+		}
+	}
+	for _, image := range view.Images {
+		if image.Name == config.ToName {
+			config.DataKey = strings.TrimPrefix(image.Value, "$")
+		}
+	}
+	return config
+}
+
+// label returns the config label matching a model class, ignoring case.
+func (c labelConfig) label(class string) (string, bool) {
+	if len(c.Labels) == 0 {
+		return class, true
+	}
+	for _, label := range c.Labels {
+		if strings.EqualFold(label, class) {
+			return label, true
+		}
+	}
+	return "", false
+}
+
+// labelStudioProjectFor registers a project on first use, updating its
+// config when rawConfig is set, and returns a copy safe to read unlocked.
+func labelStudioProjectFor(id, rawConfig string) labelStudioProject {
+	labelStudioMu.Lock()
+	defer labelStudioMu.Unlock()
+	project, ok := labelStudioProjects[id]
+	if !ok {
+		project = &labelStudioProject{
+			ID:          id,
+			Hostname:    os.Getenv("LABEL_STUDIO_URL"),
+			AccessToken: os.Getenv("LABEL_STUDIO_API_KEY"),
+		}
+		labelStudioProjects[id] = project
+	}
+	if rawConfig != "" {
+		project.Config = parseLabelConfig(rawConfig)
+	} else if project.Config.FromName == "" {
+		project.Config = parseLabelConfig("")
+	}
+	return *project
+}
+
+var (
+	modelVersionsMu sync.Mutex
+	modelVersions   = map[modelVersionKey]string{}
+)
+
+// modelVersionKey ties a cached version to the weights file as it was when
+// hashed, so retrained weights written to the same path get a new version.
+type modelVersionKey struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// modelVersion identifies model weights by name and content hash so Label
+// Studio can tell predictions of different fine-tuning runs apart.
+func modelVersion(model string) string {
+	name := strings.TrimSuffix(filepath.Base(model), filepath.Ext(model))
+	info, err := os.Stat(model)
+	if err != nil {
+		logger.Errorf("error opening model %v: %v", model, err)
+		return name
+	}
+	key := modelVersionKey{path: model, size: info.Size(), modTime: info.ModTime()}
+	modelVersionsMu.Lock()
+	version, ok := modelVersions[key]
+	modelVersionsMu.Unlock()
+	if ok {
+		return version
+	}
+
+	// hash without holding the lock, concurrent callers at worst hash twice
+	f, err := os.Open(model)
+	if err != nil {
+		logger.Errorf("error opening model %v: %v", model, err)
+		return name
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		logger.Errorf("error hashing model %v: %v", model, err)
+		return name
+	}
+	version = fmt.Sprintf("%v-%v", name, hex.EncodeToString(hash.Sum(nil))[:12])
+	modelVersionsMu.Lock()
+	for k := range modelVersions {
+		if k.path == model {
+			delete(modelVersions, k)
+		}
+	}
+	modelVersions[key] = version
+	modelVersionsMu.Unlock()
+	return version
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Errorf("error writing response: %v", err)
+	}
+}
+
+func labelStudioHealthHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":      "UP",
+		"model_class": "YOLOv8",
+	})
+}
+
+type labelStudioSetupRequest struct {
+	Project      string `json:"project"`
+	Schema       string `json:"schema"`
+	Hostname     string `json:"hostname"`
+	AccessToken  string `json:"access_token"`
+	ModelVersion string `json:"model_version"`
+}
+
+func labelStudioSetupHandler(w http.ResponseWriter, r *http.Request) {
+	req := labelStudioSetupRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid setup request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	labelStudioProjectFor(req.Project, req.Schema)
+	labelStudioMu.Lock()
+	stored := labelStudioProjects[req.Project]
+	if req.Hostname != "" {
+		stored.Hostname = req.Hostname
+	}
+	if req.AccessToken != "" {
+		stored.AccessToken = req.AccessToken
+	}
+	stored.ModelVersion = req.ModelVersion
+	project := *stored
+	labelStudioMu.Unlock()
+	logger.Infof("label studio project %v set up with labels %v", project.ID, project.Config.Labels)
+
+	writeJSON(w, http.StatusOK, map[string]string{"model_version": modelVersion(defaultModel)})
+}
+
+type labelStudioTask struct {
+	ID   int                    `json:"id"`
+	Data map[string]interface{} `json:"data"`
+}
+
+type labelStudioPredictRequest struct {
+	Tasks       []labelStudioTask `json:"tasks"`
+	Project     string            `json:"project"`
+	LabelConfig string            `json:"label_config"`
+}
+
+type labelStudioPrediction struct {
+	Result       []labelStudioResult `json:"result"`
+	Score        float64             `json:"score"`
+	ModelVersion string              `json:"model_version"`
+}
+
+type labelStudioResult struct {
+	ID             string               `json:"id"`
+	FromName       string               `json:"from_name"`
+	ToName         string               `json:"to_name"`
+	Type           string               `json:"type"`
+	OriginalWidth  int                  `json:"original_width"`
+	OriginalHeight int                  `json:"original_height"`
+	ImageRotation  int                  `json:"image_rotation"`
+	Value          labelStudioRectangle `json:"value"`
+	Score          float64              `json:"score"`
+}
+
+type labelStudioRectangle struct {
+	X               float64  `json:"x"`
+	Y               float64  `json:"y"`
+	Width           float64  `json:"width"`
+	Height          float64  `json:"height"`
+	Rotation        float64  `json:"rotation"`
+	RectangleLabels []string `json:"rectanglelabels"`
+}
+
+func labelStudioPredictHandler(w http.ResponseWriter, r *http.Request) {
+	req := labelStudioPredictRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid predict request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	project := labelStudioProjectFor(req.Project, req.LabelConfig)
+	predictions := []labelStudioPrediction{}
+	for _, task := range req.Tasks {
+		prediction, err := predictLabelStudioTask(r.Context(), project, task)
+		if err != nil {
+			logger.Errorf("error predicting task %v: %v", task.ID, err)
+			prediction = labelStudioPrediction{Result: []labelStudioResult{}, ModelVersion: modelVersion(defaultModel)}
+		}
+		predictions = append(predictions, prediction)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"results": predictions})
+}
+
+// predictLabelStudioTask runs yolo on the file of a task in a temporary dir.
+// Predictions aren't jobs, nothing is saved or published.
+func predictLabelStudioTask(ctx context.Context, project labelStudioProject, task labelStudioTask) (labelStudioPrediction, error) {
+	source, _ := task.Data[project.Config.DataKey].(string)
+	if source == "" {
+		return labelStudioPrediction{}, fmt.Errorf("task has no %q data", project.Config.DataKey)
+	}
+
+	localPath, err := downloadLabelStudioFile(ctx, project, source)
+	if err != nil {
+		return labelStudioPrediction{}, err
+	}
+	defer os.Remove(localPath)
+
+	dir, err := os.MkdirTemp("", "labelstudio-predict-*")
+	if err != nil {
+		return labelStudioPrediction{}, fmt.Errorf("error creating predict dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	j := newJob(localPath)
+	result, err := predict(ctx, predictOptions{
+		Model:      j.Model,
+		Source:     localPath,
+		Confidence: j.Confidence,
+		Mode:       j.Mode,
+	}, dir, func(string) {})
+	if err != nil {
+		return labelStudioPrediction{}, err
+	}
+	j.Media, j.Detections = result.Media, result.Detections
+	return newLabelStudioPrediction(project.Config, j, j.Detections), nil
+}
+
+// newLabelStudioPrediction converts detections of a single image or frame of
+// a job into RectangleLabels results, with coordinates in percent of the image.
+func newLabelStudioPrediction(config labelConfig, j *job, detections []detection) labelStudioPrediction {
+	prediction := labelStudioPrediction{
+		Result:       []labelStudioResult{},
+		ModelVersion: modelVersion(j.Model),
+	}
+	total := 0.0
+	for i, d := range detections {
+		label, ok := config.label(d.Class)
+		if !ok {
+			continue
+		}
+		prediction.Result = append(prediction.Result, labelStudioResult{
+			ID:             fmt.Sprintf("%v_%v_%v", j.ID, d.Frame, i),
+			FromName:       config.FromName,
+			ToName:         config.ToName,
+			Type:           "rectanglelabels",
+			OriginalWidth:  j.Media.Width,
+			OriginalHeight: j.Media.Height,
+			Value: labelStudioRectangle{
+				X:               (d.X - d.W/2) * 100,
+				Y:               (d.Y - d.H/2) * 100,
+				Width:           d.W * 100,
+				Height:          d.H * 100,
+				RectangleLabels: []string{label},
+			},
+			Score: d.Confidence,
+		})
+		total += d.Confidence
+	}
+	if len(prediction.Result) > 0 {
+		prediction.Score = total / float64(len(prediction.Result))
+	}
+	return prediction
+}
+
+// downloadLabelStudioFile fetches a task file, resolving urls relative to the
+// Label Studio host and authenticating with the project access token.
+func downloadLabelStudioFile(ctx context.Context, project labelStudioProject, source string) (string, error) {
+	fileURL, err := url.Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid task url %q: %v", source, err)
+	}
+	var host *url.URL
+	if project.Hostname != "" {
+		if host, err = url.Parse(project.Hostname); err != nil {
+			return "", fmt.Errorf("invalid label studio host %q: %v", project.Hostname, err)
+		}
+	}
+	if !fileURL.IsAbs() {
+		if host == nil {
+			return "", fmt.Errorf("relative task url %q and no label studio host configured", source)
+		}
+		fileURL = host.ResolveReference(fileURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	// the token only goes to the label studio host itself
+	if project.AccessToken != "" && host != nil && sameOrigin(fileURL, host) {
+		req.Header.Set("Authorization", "Token "+project.AccessToken)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error downloading %v: %v", fileURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error downloading %v: %v", fileURL, resp.Status)
+	}
+
+	f, err := os.CreateTemp("", "labelstudio-*"+path.Ext(fileURL.Path))
+	if err != nil {
+		return "", fmt.Errorf("error creating temp file: %v", err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("error saving %v: %v", fileURL, err)
+	}
+	return f.Name(), nil
+}
+
+// sameOrigin reports whether a and b have the same scheme and host.
+func sameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Host, b.Host)
+}
+
+type labelStudioWebhookEvent struct {
+	Action  string `json:"action"`
+	Project struct {
+		ID int `json:"id"`
+	} `json:"project"`
+}
+
+// labelStudioWebhookHandler acknowledges annotation and training events.
+// Training is still done by hand so events are only logged for now.
+func labelStudioWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	event := labelStudioWebhookEvent{}
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		http.Error(w, fmt.Sprintf("invalid webhook event: %v", err), http.StatusBadRequest)
+		return
+	}
+	logger.Infof("label studio event %v for project %v", event.Action, event.Project.ID)
+	writeJSON(w, http.StatusCreated, map[string]string{})
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// labelStudioClient talks to the Label Studio REST API. The url and token are
+// read from LABEL_STUDIO_URL and LABEL_STUDIO_API_KEY so it can be pointed at
+// a local stand-in.
+type labelStudioClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func newLabelStudioClient() *labelStudioClient {
+	return &labelStudioClient{
+		baseURL: strings.TrimSuffix(os.Getenv("LABEL_STUDIO_URL"), "/"),
+		token:   os.Getenv("LABEL_STUDIO_API_KEY"),
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// labelStudioImportTask is a task with its pre-annotations as accepted by
+// the project import endpoint.
+type labelStudioImportTask struct {
+	Data        map[string]interface{}  `json:"data"`
+	Predictions []labelStudioPrediction `json:"predictions,omitempty"`
+}
+
+func (c *labelStudioClient) do(method, path string, body interface{}, out interface{}) error {
+	if c.baseURL == "" {
+		return fmt.Errorf("LABEL_STUDIO_URL is not set")
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error encoding request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Token "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling label studio: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("label studio %v %v: %v %s", method, path, resp.Status, msg)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding label studio response: %v", err)
+	}
+	return nil
+}
+
+func (c *labelStudioClient) projectLabelConfig(project int) (labelConfig, error) {
+	var resp struct {
+		LabelConfig string `json:"label_config"`
+	}
+	if err := c.do(http.MethodGet, fmt.Sprintf("/api/projects/%v", project), nil, &resp); err != nil {
+		return labelConfig{}, err
+	}
+	return parseLabelConfig(resp.LabelConfig), nil
+}
+
+func (c *labelStudioClient) importTasks(project int, tasks []labelStudioImportTask) ([]int, error) {
+	if len(tasks) == 0 {
+		return []int{}, nil
+	}
+	var resp struct {
+		TaskCount int   `json:"task_count"`
+		TaskIDs   []int `json:"task_ids"`
+	}
+	path := fmt.Sprintf("/api/projects/%v/import?return_task_ids=true", project)
+	if err := c.do(http.MethodPost, path, tasks, &resp); err != nil {
+		return nil, err
+	}
+	return resp.TaskIDs, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+)
+
+func TestDownloadLabelStudioFileToken(t *testing.T) {
+	auth := make(chan string, 1)
+	serve := func(w http.ResponseWriter, r *http.Request) {
+		auth <- r.Header.Get("Authorization")
+		w.Write([]byte("image"))
+	}
+	studio := httptest.NewServer(http.HandlerFunc(serve))
+	defer studio.Close()
+	other := httptest.NewServer(http.HandlerFunc(serve))
+	defer other.Close()
+	project := labelStudioProject{Hostname: studio.URL, AccessToken: "secret"}
+
+	for _, tt := range []struct {
+		source string
+		want   string
+	}{
+		{"/data/upload/1/frame.jpg", "Token secret"},
+		{studio.URL + "/data/upload/1/frame.jpg", "Token secret"},
+		{other.URL + "/frame.jpg", ""},
+	} {
+		path, err := downloadLabelStudioFile(context.Background(), project, tt.source)
+		if err != nil {
+			t.Fatalf("%v: %v", tt.source, err)
+		}
+		os.Remove(path)
+		if got := <-auth; got != tt.want {
+			t.Errorf("%v sent Authorization %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestSameOrigin(t *testing.T) {
+	host, _ := url.Parse("https://studio.example.com")
+	for raw, want := range map[string]bool{
+		"https://studio.example.com/data/1.jpg":         true,
+		"HTTPS://Studio.Example.com/data/1.jpg":         true,
+		"https://studio.example.com.evil.io/1.jpg":      false,
+		"https://studio.example.com:8443/1.jpg":         false,
+		"http://studio.example.com/data/1.jpg":          false,
+		"https://studio.example.com@evil.io/data/1.jpg": false,
+	} {
+		u, err := url.Parse(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sameOrigin(u, host); got != want {
+			t.Errorf("sameOrigin(%v) = %v, want %v", raw, got, want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// logAttrsKey holds the attributes every record logged with the context
+// carries, like the request and job ids.
+type logAttrsKey struct{}
+
+// withLogAttrs returns a context whose log records include args, key value
+// pairs, on top of the ones ctx already has. A key already set is replaced.
+func withLogAttrs(ctx context.Context, args ...any) context.Context {
+	attrs, _ := ctx.Value(logAttrsKey{}).([]slog.Attr)
+	added := argsToAttrs(args)
+	merged := make([]slog.Attr, 0, len(attrs)+len(added))
+	for _, a := range attrs {
+		if !hasAttr(added, a.Key) {
+			merged = append(merged, a)
+		}
+	}
+	merged = append(merged, added...)
+	return context.WithValue(ctx, logAttrsKey{}, merged)
+}
+
+func argsToAttrs(args []any) []slog.Attr {
+	r := slog.Record{}
+	r.Add(args...)
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return attrs
+}
+
+func hasAttr(attrs []slog.Attr, key string) bool {
+	for _, a := range attrs {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// contextHandler adds the context attributes and the ids of the current span
+// to every record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(logAttrsKey{}).([]slog.Attr); ok {
+		// attributes passed to the call win over the context ones
+		own := make([]slog.Attr, 0, r.NumAttrs())
+		r.Attrs(func(a slog.Attr) bool {
+			own = append(own, a)
+			return true
+		})
+		for _, a := range attrs {
+			if !hasAttr(own, a.Key) {
+				r.AddAttrs(a)
+			}
+		}
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// structuredLogger keeps the printf style calls used across the server but
+// writes JSON records through slog. ctx is where the request and job ids
+// come from, see logger.ctx.
+type structuredLogger struct {
+	*slog.Logger
+	context context.Context
+}
+
+// logger writes JSON to stderr, LOG_FORMAT=text switches to plain text and
+// LOG_LEVEL to debug, warn or error changes the lowest level written.
+var logger = newStructuredLogger(os.Stderr, os.Getenv("LOG_FORMAT"), os.Getenv("LOG_LEVEL"))
+
+func newStructuredLogger(w io.Writer, format, level string) structuredLogger {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		lvl = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	var h slog.Handler = slog.NewJSONHandler(w, opts)
+	if strings.EqualFold(format, "text") {
+		h = slog.NewTextHandler(w, opts)
+	}
+	return structuredLogger{Logger: slog.New(contextHandler{h}), context: context.Background()}
+}
+
+// ctx returns a logger whose records carry the ids stored in ctx.
+func (l structuredLogger) ctx(ctx context.Context) structuredLogger {
+	return structuredLogger{Logger: l.Logger, context: ctx}
+}
+
+func (l structuredLogger) Info(msg string, args ...any) {
+	l.Logger.InfoContext(l.context, msg, args...)
+}
+
+func (l structuredLogger) Warn(msg string, args ...any) {
+	l.Logger.WarnContext(l.context, msg, args...)
+}
+
+func (l structuredLogger) Error(msg string, args ...any) {
+	l.Logger.ErrorContext(l.context, msg, args...)
+}
+
+func (l structuredLogger) Infof(format string, args ...any) {
+	l.Logger.InfoContext(l.context, fmt.Sprintf(format, args...))
+}
+
+func (l structuredLogger) Warnf(format string, args ...any) {
+	l.Logger.WarnContext(l.context, fmt.Sprintf(format, args...))
+}
+
+func (l structuredLogger) Errorf(format string, args ...any) {
+	l.Logger.ErrorContext(l.context, fmt.Sprintf(format, args...))
+}
+
+func (l structuredLogger) Fatalf(format string, args ...any) {
+	l.Logger.ErrorContext(l.context, fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
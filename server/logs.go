@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	logLevelInfo  = "info"
+	logLevelWarn  = "warn"
+	logLevelError = "error"
+
+	jobLogFileName = "job.log"
+	// log lines are sent to clients in batches of up to logBatchSize lines,
+	// at most logBatchInterval after the first one was written
+	logBatchSize     = 50
+	logBatchInterval = 250 * time.Millisecond
+)
+
+var logLevels = map[string]int{logLevelInfo: 0, logLevelWarn: 1, logLevelError: 2}
+
+// logLine is a line of output of a job.
+type logLine struct {
+	Time  time.Time `json:"time"`
+	Level string    `json:"level"`
+	Text  string    `json:"text"`
+}
+
+// logLevel classifies a line of yolo or ffmpeg output.
+func logLevel(text string) string {
+	switch {
+	case strings.Contains(text, "Traceback"), strings.Contains(text, "ERROR"),
+		strings.Contains(text, "Error:"), strings.HasPrefix(text, "error"):
+		return logLevelError
+	case strings.Contains(text, "WARNING"), strings.Contains(text, "Warning"):
+		return logLevelWarn
+	}
+	return logLevelInfo
+}
+
+// parseLogLevel validates a minimum level, empty means every line.
+func parseLogLevel(level string) (string, error) {
+	if level == "" {
+		return logLevelInfo, nil
+	}
+	if _, ok := logLevels[level]; !ok {
+		return "", fmt.Errorf("unknown log level %q", level)
+	}
+	return level, nil
+}
+
+// jobLog writes every line of a job to its log file and hands them to flush
+// in batches, so a chatty yolo run doesn't turn into one websocket frame per
+// line.
+type jobLog struct {
+	mu      sync.Mutex
+	file    *os.File
+	out     *bufio.Writer
+	pending []logLine
+	timer   *time.Timer
+	flushFn func([]logLine)
+}
+
+// openJobLog creates the log file of a job, lines are still batched to flush
+// when the file can't be created.
+func openJobLog(j *job, flush func([]logLine)) *jobLog {
+	l := &jobLog{flushFn: flush}
+	if err := os.MkdirAll(j.dir(), 0755); err != nil {
+		logger.Errorf("error creating job dir: %v", err)
+		return l
+	}
+	f, err := os.OpenFile(filepath.Join(j.dir(), jobLogFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		logger.Errorf("error creating log of job %v: %v", j.ID, err)
+		return l
+	}
+	l.file, l.out = f, bufio.NewWriter(f)
+	return l
+}
+
+// Write records a line of command output, it is safe to use as onLine.
+func (l *jobLog) Write(text string) {
+	l.add(logLevel(text), text)
+}
+
+// add records a line with a known level.
+func (l *jobLog) add(level, text string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	line := l.record(level, text)
+	l.pending = append(l.pending, line)
+	if len(l.pending) >= logBatchSize {
+		l.flushLocked()
+		return
+	}
+	if l.timer == nil {
+		l.timer = time.AfterFunc(logBatchInterval, l.flush)
+	}
+}
+
+// note records a line in the log file only, for messages clients get as
+// their own events.
+func (l *jobLog) note(level, text string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.record(level, text)
+}
+
+func (l *jobLog) record(level, text string) logLine {
+	line := logLine{Time: time.Now(), Level: level, Text: text}
+	if l.out != nil {
+		fmt.Fprintf(l.out, "%v %-5v %v\n", line.Time.Format(time.RFC3339Nano), strings.ToUpper(level), text)
+	}
+	return line
+}
+
+// flush sends the pending lines, callers flush before publishing events that
+// must come after them.
+func (l *jobLog) flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.flushLocked()
+}
+
+func (l *jobLog) flushLocked() {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	if l.out != nil {
+		if err := l.out.Flush(); err != nil {
+			logger.Errorf("error writing job log: %v", err)
+		}
+	}
+	if len(l.pending) == 0 {
+		return
+	}
+	l.flushFn(l.pending)
+	l.pending = nil
+}
+
+func (l *jobLog) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.flushLocked()
+	if l.file != nil {
+		l.file.Close()
+	}
+	// a timer that already fired finds nothing left to write
+	l.file, l.out = nil, nil
+}
+
+// jobLogHandler serves the full log of a job, level drops the lines below it.
+// GET /jobs/{id}/logs?level=warn
+func jobLogHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !jobIDPattern.MatchString(id) {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+	level, err := parseLogLevel(r.URL.Query().Get("level"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f, err := os.Open(filepath.Join(jobDir(id), jobLogFileName))
+	if err != nil {
+		http.Error(w, "log not found", http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if level == logLevelInfo {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", "job-"+id+".log"))
+		http.ServeContent(w, r, "", time.Time{}, f)
+		return
+	}
+	in := bufio.NewScanner(f)
+	in.Buffer(make([]byte, 64*1024), 1024*1024)
+	for in.Scan() {
+		// lines are "<time> <LEVEL> <text>"
+		fields := strings.Fields(in.Text())
+		if len(fields) > 1 && logLevels[strings.ToLower(fields[1])] >= logLevels[level] {
+			fmt.Fprintln(w, in.Text())
+		}
+	}
+}
@@ -3,128 +3,571 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"html/template"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/websocket"
 
 	"github.com/gorilla/mux"
-	"github.com/vorticist/logger"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+const shutdownTimeout = 10 * time.Second
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
+	Subprotocols:    []string{wsProtocolJSON},
 }
 
 type message struct {
-	Message  string `json:"message"`
-	LogLine  string `json:"log_line"`
-	VideoURL string `json:"video_url"`
+	// JobID picks an existing job to watch instead of starting one.
+	JobID   string `json:"job_id"`
+	Message string `json:"message"`
+	Mode    string `json:"mode"`
+	Tracker string `json:"tracker"`
+	// Model is a model name from /models, empty uses the default.
+	Model      string  `json:"model"`
+	Confidence float64 `json:"confidence"`
+	// Highlights is the value of the highlights checkbox, "on" when checked.
+	Highlights    string `json:"highlights"`
+	HighlightsURL string `json:"highlights_url"`
+	Profile       string `json:"profile"`
+	// Renditions is the value of the renditions checkbox, "on" when checked.
+	Renditions  string         `json:"renditions"`
+	VideoType   string         `json:"video_type"`
+	PlaylistURL string         `json:"playlist_url"`
+	Progress    float64        `json:"progress"`
+	LogLine     string         `json:"log_line"`
+	LogLines    []logLine      `json:"log_lines,omitempty"`
+	LogURL      string         `json:"log_url,omitempty"`
+	VideoURL    string         `json:"video_url"`
+	ImageURL    string         `json:"image_url"`
+	ExportURL   string         `json:"export_url"`
+	Tracked     bool           `json:"tracked"`
+	Counts      map[string]int `json:"counts"`
+	Events      []ruleEvent    `json:"events"`
+	Summary     *videoSummary  `json:"summary"`
+	Error       *jobError      `json:"error,omitempty"`
 }
 
 func main() {
+	shutdownTracing, err := setupTracing(context.Background())
+	if err != nil {
+		logger.Fatalf("error setting up tracing: %v", err)
+	}
+	if err := setupEvents(); err != nil {
+		logger.Fatalf("error setting up events: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// the same binary runs leased jobs for an API server, a single job or
+	// the DetectionJob controller instead of serving
+	if len(os.Args) > 1 {
+		code := 0
+		switch os.Args[1] {
+		case "worker":
+			if err := runWorker(ctx); err != nil {
+				logger.Fatalf("error running worker: %v", err)
+			}
+		case "run":
+			code = runOneShot(ctx, os.Args[2:])
+		case "controller":
+			if err := runController(ctx); err != nil {
+				logger.Fatalf("error running controller: %v", err)
+			}
+		default:
+			logger.Fatalf("unknown command %q, want worker, run or controller", os.Args[1])
+		}
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Errorf("error flushing traces: %v", err)
+		}
+		os.Exit(code)
+	}
+
+	if remoteDispatch() && os.Getenv("WORKER_TOKEN") == "" {
+		logger.Fatalf("JOB_DISPATCH=remote needs WORKER_TOKEN to authenticate workers")
+	}
+	go func() {
+		if err := serveGRPC(); err != nil {
+			logger.Fatalf("error serving gRPC: %v", err)
+		}
+	}()
+
 	router := mux.NewRouter()
+	router.Use(requestMiddleware)
 
 	router.PathPrefix("/static/").Handler(http.StripPrefix("/static", http.FileServer(http.Dir("./static/"))))
 	router.HandleFunc("/detect", detectHandler).Methods("GET")
+	router.HandleFunc("/jobs", listJobsHandler).Methods("GET")
+	router.HandleFunc("/jobs", createJobHandler).Methods("POST")
+	router.HandleFunc("/jobs/frames", framesJobHandler).Methods("POST")
+	router.HandleFunc("/jobs/evaluate", evaluateJobHandler).Methods("POST")
+	router.HandleFunc("/jobs/{id}", getJobHandler).Methods("GET")
+	router.HandleFunc("/jobs/{id}/events", jobEventsHandler).Methods("GET")
+	router.HandleFunc("/jobs/{id}/cancel", cancelJobHandler).Methods("POST")
+	router.HandleFunc("/jobs/{id}/export", exportJobHandler).Methods("GET")
+	router.HandleFunc("/jobs/{id}/logs", jobLogHandler).Methods("GET")
+	router.HandleFunc("/jobs/{id}/dataset", datasetHandler).Methods("GET")
+	router.HandleFunc("/export", exportBatchHandler).Methods("GET")
+	router.HandleFunc("/jobs/{id}/rules", evaluateJobRulesHandler).Methods("POST")
+	router.HandleFunc("/jobs/{id}/highlights", highlightsHandler).Methods("POST")
+	router.HandleFunc("/profiles", listTranscodeProfilesHandler).Methods("GET")
+	router.HandleFunc("/models", listModelsHandler).Methods("GET")
+	router.HandleFunc("/evaluations", evaluationsHandler).Methods("GET")
+	router.HandleFunc("/cache", cacheStatsHandler).Methods("GET")
+	router.HandleFunc("/cache", clearCacheHandler).Methods("DELETE")
+	router.HandleFunc("/rules", listRuleSetsHandler).Methods("GET")
+	router.HandleFunc("/rules", createRuleSetHandler).Methods("POST")
+	router.HandleFunc("/rules/{id}", updateRuleSetHandler).Methods("PUT")
+	router.HandleFunc("/rules/{id}", deleteRuleSetHandler).Methods("DELETE")
+	router.HandleFunc("/streams", startStreamHandler).Methods("POST")
+	router.HandleFunc("/streams", listStreamsHandler).Methods("GET")
+	router.HandleFunc("/streams/{id}", streamStatusHandler).Methods("GET")
+	router.HandleFunc("/streams/{id}", stopStreamHandler).Methods("DELETE")
+	router.HandleFunc("/active-learning", activeLearningHandler).Methods("POST")
+	router.HandleFunc("/trainings", startTrainingHandler).Methods("POST")
+	router.HandleFunc("/trainings", listTrainingsHandler).Methods("GET")
+	router.HandleFunc("/trainings/{id}", getTrainingHandler).Methods("GET")
+	router.HandleFunc("/trainings/{id}/events", trainingEventsHandler).Methods("GET")
+	router.HandleFunc("/trainings/{id}/cancel", cancelTrainingHandler).Methods("POST")
+	router.HandleFunc("/trainings/{id}/resume", resumeTrainingHandler).Methods("POST")
+	router.HandleFunc("/trainings/{id}/register", registerTrainingHandler).Methods("POST")
+	registerLabelStudioRoutes(router)
+	registerWorkerRoutes(ctx, router)
+	router.HandleFunc("/openapi.json", openAPIHandler).Methods("GET")
+	router.HandleFunc("/docs", docsHandler).Methods("GET")
 	router.HandleFunc("/", makeIndexHandler()).Methods("GET")
 
-	fmt.Println("Server is running on :8080")
-	http.ListenAndServe(":8080", router)
+	server := &http.Server{Addr: ":8080", Handler: router}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("Server is running on :8080")
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Fatalf("error serving http: %v", err)
+	}
+	// flush the spans of the last requests
+	if err := shutdownTracing(context.Background()); err != nil {
+		logger.Errorf("error flushing traces: %v", err)
+	}
 }
 
 func makeIndexHandler() func(w http.ResponseWriter, r *http.Request) {
-	tmpl := template.Must(template.ParseFiles("templates/index.html"))
+	tmpls, err := parsedTemplates()
+	if err != nil {
+		logger.Fatalf("error parsing templates: %v", err)
+	}
+	tmpl := tmpls.Lookup("index.html")
 	return func(w http.ResponseWriter, r *http.Request) {
 		logger.Info("index...")
-		tmpl.Execute(w, "index")
+		data := struct {
+			RunningJobs []*jobFeed
+		}{runningFeeds(clientID(w, r))}
+		tmpl.Execute(w, data)
 	}
 }
 
+// detectHandler starts a job for every url sent over the websocket. Jobs run
+// in the background so the client can reconnect, it is sent the jobs it left
+// running on connect and can watch any job by sending its job_id.
+//
 // yolo predict model=yolov8n-seg.pt source='https://youtu.be/c8XQp5brszI' imgsz=320
 func detectHandler(w http.ResponseWriter, r *http.Request) {
+	owner := clientID(nil, r)
+	log := logger.ctx(r.Context())
+	_, span := startSpan(r.Context(), "websocket.upgrade")
 	conn, err := upgrader.Upgrade(w, r, nil)
+	if err == nil {
+		span.SetAttributes(attribute.String("websocket.subprotocol", conn.Subprotocol()))
+	}
+	endSpan(span, err)
 	if err != nil {
-		logger.Errorf("failed to upgrade connection: %v", err)
+		log.Errorf("failed to upgrade connection: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 	defer conn.Close()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// clients that negotiated the JSON protocol get envelopes, the index
+	// page gets html fragments
+	jsonProtocol := conn.Subprotocol() == wsProtocolJSON
+
+	var writeMu sync.Mutex
+	send := func(e feedEvent) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if jsonProtocol {
+			return conn.WriteJSON(e.envelope())
+		}
+		for _, frame := range e.frames() {
+			if err := conn.WriteMessage(websocket.TextMessage, frame); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// sendError tells JSON clients why a request was rejected, the index page
+	// only gets it in the server log
+	sendError := func(jobID string, err error) {
+		log.Errorf("invalid request: %v", err)
+		if jsonProtocol {
+			send(feedEvent{Type: feedEventError, JobID: jobID, Time: time.Now(), Msg: errorMessage(jobID, err)})
+		}
+	}
+	watching := map[string]bool{}
+	watch := func(f *jobFeed, after int) {
+		if watching[f.JobID] {
+			return
+		}
+		watching[f.JobID] = true
+		go func() {
+			if err := f.watch(ctx, after, send); err != nil && ctx.Err() == nil {
+				log.Errorf("error sending job %v: %v", f.JobID, err)
+			}
+		}()
+	}
+
+	for _, f := range runningFeeds(owner) {
+		watch(f, 0)
+	}
+
 	for {
-		msg := message{}
-		err := conn.ReadJSON(&msg)
+		_, data, err := conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				logger.Errorf("error %v", err)
+				log.Errorf("error %v", err)
 			}
 			break
 		}
-
-		url := msg.Message
-		if len(url) == 0 {
+		req, ok, err := readWSRequest(data, jsonProtocol)
+		if err != nil {
+			sendError("", newJobError(errCodeInvalidRequest, err))
+			continue
+		}
+		if !ok {
 			continue
 		}
 
-		logger.Infof("got url: %v", url)
+		switch req.Type {
+		case wsRequestWatch:
+			if f := findFeed(req.JobID); f != nil {
+				watch(f, req.AfterSeq)
+				continue
+			}
+			// the feed is gone, the result is still on disk
+			j, err := loadJob(req.JobID)
+			if err != nil {
+				sendError(req.JobID, newJobError(errCodeInvalidRequest, fmt.Errorf("job %v not found", req.JobID)))
+				continue
+			}
+			send(feedEvent{Type: feedEventResult, JobID: j.ID, Time: time.Now(), Msg: j.resultMessage()})
+			if jsonProtocol {
+				send(feedEvent{Type: feedEventDone, JobID: j.ID, Time: time.Now(), Msg: message{JobID: j.ID}})
+			}
+		case wsRequestCancel:
+			if !cancelJob(req.JobID) {
+				sendError(req.JobID, newJobError(errCodeInvalidRequest, fmt.Errorf("job %v is not running", req.JobID)))
+			}
+		case wsRequestSubmit:
+			f, err := startDetectJob(r.Context(), *req.Job, owner)
+			if err != nil {
+				sendError("", err)
+				continue
+			}
+			watch(f, 0)
+		}
+	}
+}
+
+// detectRequest is a detect job as submitted over any of the APIs.
+type detectRequest struct {
+	Source     string  `json:"source"`
+	Model      string  `json:"model,omitempty"`
+	Confidence float64 `json:"confidence,omitempty"`
+	Mode       string  `json:"mode,omitempty"`
+	Tracker    string  `json:"tracker,omitempty"`
+	Profile    string  `json:"profile,omitempty"`
+	Highlights bool    `json:"highlights,omitempty"`
+	Renditions bool    `json:"renditions,omitempty"`
+}
+
+// detectRequest converts the fields of the index page form, checkboxes are
+// sent as "on".
+func (m message) detectRequest() detectRequest {
+	return detectRequest{
+		Source:     m.Message,
+		Model:      m.Model,
+		Confidence: m.Confidence,
+		Mode:       m.Mode,
+		Tracker:    m.Tracker,
+		Profile:    m.Profile,
+		Highlights: m.Highlights == "on",
+		Renditions: m.Renditions == "on",
+	}
+}
+
+// startDetectJob validates a detect request and starts its job in the
+// background, the returned feed carries its progress. With JOB_DISPATCH=remote
+// the job is queued for a worker instead of running here.
+func startDetectJob(ctx context.Context, req detectRequest, owner string) (*jobFeed, error) {
+	if len(req.Source) == 0 {
+		return nil, newJobError(errCodeInvalidRequest, fmt.Errorf("source is required"))
+	}
+
+	logger.ctx(ctx).Infof("got url: %v", req.Source)
+
+	if limit := jobsPerClient(); limit > 0 && owner != "" && len(runningFeeds(owner)) >= limit {
+		return nil, newJobError(errCodeQuotaExceeded, fmt.Errorf("the limit is %v running jobs per client", limit))
+	}
+	j, profile, err := newDetectJob(req)
+	if err != nil {
+		return nil, err
+	}
+	f := newJobFeed(j, owner)
+	if remoteDispatch() {
+		enqueueJob(ctx, f, j, req)
+		return f, nil
+	}
+	go j.process(linkContext(f.ctx, ctx), f, profile, req.Highlights)
+	return f, nil
+}
+
+// newDetectJob validates a detect request and returns its job and
+// transcoding profile.
+func newDetectJob(req detectRequest) (*job, transcodeProfile, error) {
+	url := req.Source
+	if err := checkSource(url); err != nil {
+		return nil, transcodeProfile{}, err
+	}
+	mode, tracker, err := parsePredictMode(req.Mode, req.Tracker)
+	if err != nil {
+		return nil, transcodeProfile{}, newJobError(errCodeInvalidRequest, err)
+	}
 
-		args := []string{
-			"detect",
-			"predict",
-			"model='/server/best.pt'",
-			fmt.Sprintf("source='%v'", url),
-			"conf=0.70",
-			"imgsz=640",
+	// rules are evaluated over tracks
+	if mode == predictModeDetect && len(rulesForSource(url)) > 0 {
+		mode, tracker = predictModeTrack, defaultTracker
+	}
+
+	profile, err := findTranscodeProfile(req.Profile)
+	if err != nil {
+		return nil, transcodeProfile{}, newJobError(errCodeInvalidRequest, err)
+	}
+	model, err := findModel(req.Model)
+	if err != nil {
+		return nil, transcodeProfile{}, newJobError(errCodeInvalidRequest, err)
+	}
+	if req.Confidence < 0 || req.Confidence > 1 {
+		return nil, transcodeProfile{}, newJobError(errCodeInvalidRequest, fmt.Errorf("confidence must be between 0 and 1"))
+	}
+
+	j := newJob(url)
+	j.Model = model.Path
+	if req.Confidence > 0 {
+		j.Confidence = req.Confidence
+	}
+	j.Mode, j.Tracker = mode, tracker
+	j.Profile, j.Renditions = profile.Name, req.Renditions
+	return j, profile, nil
+}
+
+// process runs a detect job to the end publishing its logs, progress and
+// result to f. It stops early when ctx, the feed context, is cancelled.
+func (j *job) process(ctx context.Context, f *jobFeed, profile transcodeProfile, highlights bool) {
+	defer f.publish(feedEventDone, message{JobID: j.ID})
+	ctx, span := startSpan(ctx, "detect job",
+		attribute.String("job.id", j.ID),
+		attribute.String("job.source", j.Source),
+		attribute.String("job.model", j.Model),
+		attribute.String("job.mode", j.Mode))
+	var spanErr error
+	defer func() { endSpan(span, spanErr) }()
+	ctx = withLogAttrs(ctx, "job_id", j.ID)
+	log := logger.ctx(ctx)
+
+	f.publish(feedEventQueued, message{JobID: j.ID, Message: j.Source})
+	// save right away so the job shows up in /jobs while it runs
+	if err := j.save(); err != nil {
+		log.Errorf("error saving job %v: %v", j.ID, err)
+	}
+
+	jl := openJobLog(j, func(lines []logLine) {
+		f.publish(feedEventLog, message{JobID: j.ID, LogLines: lines})
+	})
+	defer jl.close()
+	fail := func(err error) {
+		spanErr = err
+		msg := errorMessage(j.ID, err)
+		jl.note(logLevelError, msg.LogLine)
+		jl.flush()
+		f.publish(feedEventError, msg)
+	}
+
+	j.publishStarted()
+	err := j.runDetection(ctx, jl.Write)
+	jl.flush()
+	if ctx.Err() != nil {
+		err = asJobError(ctx.Err())
+	}
+	err = j.finish(err)
+	if err != nil {
+		log.Errorf("error running detection: %v", err)
+		fail(err)
+		return
+	}
+
+	go autoActiveLearning(j)
+
+	if len(j.Events) > 0 {
+		f.publish(feedEventRules, message{JobID: j.ID, Events: j.Events})
+	}
+
+	if j.Media.IsVideo {
+		lastPercent := -1
+		onProgress := func(percent float64) {
+			if int(percent) == lastPercent {
+				return
+			}
+			lastPercent = int(percent)
+			f.publish(feedEventProgress, message{JobID: j.ID, Progress: percent})
 		}
-		err = executeCommandWithOutputLogs(conn, "yolo", "/usr/src/ultralytics", args)
+		j.VideoPath, err = j.transcode(ctx, profile, onProgress)
 		if err != nil {
-			logger.Error("error executing command: %v", err)
+			log.Errorf("error transcoding job %v: %v", j.ID, err)
+			err = newJobError(errCodeTranscodeFailed, err)
+			if ctx.Err() != nil {
+				err = asJobError(ctx.Err())
+			}
+			fail(j.finish(err))
 			return
 		}
+		if j.Renditions {
+			lastPercent = -1
+			if _, err := j.transcodeRenditions(ctx, profile, onProgress); err != nil {
+				log.Errorf("error transcoding renditions of job %v: %v", j.ID, err)
+				jl.add(logLevelWarn, fmt.Sprintf("skipped renditions: %v", err))
+				j.Renditions = false
+			}
+		}
 
-		err = conn.WriteMessage(websocket.TextMessage, []byte("DONE."))
+		summaryCtx, summarySpan := startSpan(ctx, "summarize")
+		err = j.summarize(summaryCtx)
+		endSpan(summarySpan, err)
 		if err != nil {
-			logger.Error("error writing message: %v", err)
-			return
+			log.Errorf("error summarizing job %v: %v", j.ID, err)
+			jl.add(logLevelWarn, fmt.Sprintf("skipped summary: %v", err))
 		}
-		mp4Path := "./static/output.mp4"
-		aviPath, err := findVideoFile()
-		if err != nil {
-			logger.Error("error finding video file: %v", err)
-			return
+		if highlights {
+			if err := j.cutHighlights(ctx, newHighlightOptions()); err != nil {
+				log.Errorf("error cutting highlights of job %v: %v", j.ID, err)
+				jl.add(logLevelWarn, fmt.Sprintf("skipped highlights: %v", err))
+			}
 		}
-		logger.Infof("found video file: %v", aviPath)
-
-		args = []string{"-i", aviPath, "-vcodec", "libx264", "-vprofile", "high", "-crf", "28", mp4Path}
-		err = executeCommand("ffmpeg", "/server", args)
-		if err != nil {
-			logger.Error("error executing command: %v", err)
-			return
+		if err := j.save(); err != nil {
+			log.Errorf("error saving job %v: %v", j.ID, err)
 		}
+	}
 
-		resultFileName := filepath.Base(mp4Path)
-		msg = message{VideoURL: fmt.Sprintf("/static/%v", resultFileName)}
-		err = conn.WriteMessage(websocket.TextMessage, getTemplate("templates/video.html", msg))
-		if err != nil {
-			logger.Error("error writing message: %v", err)
-			return
-		}
+	jl.flush()
+	f.publish(feedEventResult, j.resultMessage())
+}
+
+// errorMessage is the error event of a failed job.
+func errorMessage(jobID string, err error) message {
+	return message{JobID: jobID, LogLine: fmt.Sprintf("error: %v", err), Error: asJobError(err)}
+}
+
+// resultMessage is what the result template needs to show a finished job.
+func (j *job) resultMessage() message {
+	msg := message{
+		JobID:     j.ID,
+		ExportURL: fmt.Sprintf("/jobs/%v/export", j.ID),
+		LogURL:    fmt.Sprintf("/jobs/%v/logs", j.ID),
+		Tracked:   j.Mode == predictModeTrack,
+		Counts:    j.Counts,
+	}
+	if !j.Media.IsVideo {
+		msg.ImageURL = j.fileURL(filepath.Base(j.OutputPath))
+		return msg
+	}
+	if j.VideoPath != "" {
+		msg.VideoURL = j.fileURL(filepath.Base(j.VideoPath))
+		msg.VideoType = "video/" + strings.TrimPrefix(filepath.Ext(j.VideoPath), ".")
 	}
+	if j.Renditions {
+		msg.PlaylistURL = j.fileURL(renditionsDirName + "/" + masterPlaylistName)
+	}
+	msg.HighlightsURL = j.highlightsURL()
+	msg.Summary = j.Summary
+	return msg
+}
+
+var templateFuncs = template.FuncMap{
+	// reverse lists log lines newest first for the afterbegin swap
+	"reverse": func(lines []logLine) []logLine {
+		reversed := make([]logLine, len(lines))
+		for i, line := range lines {
+			reversed[len(lines)-1-i] = line
+		}
+		return reversed
+	},
+	// classMetric formats the metric of a class, "-" when it was not evaluated
+	"classMetric": func(metrics map[string]float64, class string) string {
+		v, ok := metrics[class]
+		if !ok {
+			return "-"
+		}
+		return fmt.Sprintf("%.3f", v)
+	},
+}
+
+var templates = struct {
+	once sync.Once
+	tmpl *template.Template
+	err  error
+}{}
+
+// parsedTemplates parses the templates dir once, they are looked up by file
+// name.
+func parsedTemplates() (*template.Template, error) {
+	templates.once.Do(func() {
+		templates.tmpl, templates.err = template.New("").Funcs(templateFuncs).ParseGlob("templates/*.html")
+	})
+	return templates.tmpl, templates.err
 }
 
 func getTemplate(templatePath string, msg message) []byte {
-	tmpl, err := template.ParseFiles(templatePath)
+	tmpls, err := parsedTemplates()
 	if err != nil {
 		logger.Errorf("template failed to parse: %v", err)
 		return nil
 	}
+	tmpl := tmpls.Lookup(filepath.Base(templatePath))
+	if tmpl == nil {
+		logger.Errorf("template %v not found", templatePath)
+		return nil
+	}
 	var renderedMessage bytes.Buffer
 	err = tmpl.Execute(&renderedMessage, msg)
 	if err != nil {
@@ -135,23 +578,20 @@ func getTemplate(templatePath string, msg message) []byte {
 	return renderedMessage.Bytes()
 }
 
-func executeCommand(command, cmdDir string, c []string) error {
-	cmd := exec.Command(command, c...)
+func executeCommand(ctx context.Context, command, cmdDir string, c []string) error {
+	cmd := exec.CommandContext(ctx, command, c...)
 	cmd.Dir = cmdDir
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return fmt.Errorf("error reading stdout: %v", err)
 	}
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		return fmt.Errorf("error reading stdout: %v", err)
-	}
+	// share the stdout pipe so a chatty stderr can't block the process
+	cmd.Stderr = cmd.Stdout
 	if err = cmd.Start(); err != nil {
 		return fmt.Errorf("error starting command: %v", err)
 	}
-	multi := io.MultiReader(stdout, stderr)
-	in := bufio.NewScanner(multi)
+	in := bufio.NewScanner(stdout)
 	for in.Scan() {
 		line := in.Text()
 		logger.Info(line)
@@ -160,60 +600,64 @@ func executeCommand(command, cmdDir string, c []string) error {
 	if err := in.Err(); err != nil {
 		return fmt.Errorf("error reading stdout: %v", err)
 	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("error running %v: %v", command, err)
+	}
 
 	return nil
 }
 
-func executeCommandWithOutputLogs(conn *websocket.Conn, command, cmdDir string, c []string) error {
-	cmd := exec.Command(command, c...)
+func executeCommandWithOutputLogs(ctx context.Context, onLine func(string), command, cmdDir string, c []string) error {
+	cmd := exec.CommandContext(ctx, command, c...)
 	cmd.Dir = cmdDir
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return fmt.Errorf("error reading stdout: %v", err)
 	}
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		return fmt.Errorf("error reading stdout: %v", err)
-	}
+	// share the stdout pipe so a chatty stderr can't block the process
+	cmd.Stderr = cmd.Stdout
 	if err = cmd.Start(); err != nil {
 		return fmt.Errorf("error starting command: %v", err)
 	}
-	multi := io.MultiReader(stdout, stderr)
-	in := bufio.NewScanner(multi)
+	log := logger.ctx(ctx)
+	in := bufio.NewScanner(stdout)
 	for in.Scan() {
 		line := in.Text()
-		logger.Info(line)
-		if strings.Contains(line, "WARNING") {
-			continue
-		}
-		msg := message{LogLine: line}
-		err := conn.WriteMessage(websocket.TextMessage, getTemplate("templates/log.html", msg))
-		if err != nil {
-			break
-		}
+		log.Info(line, "command", command)
+		onLine(line)
 	}
 
 	if err := in.Err(); err != nil {
 		return fmt.Errorf("error reading stdout: %v", err)
 	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("error running %v: %v", command, err)
+	}
 
 	return nil
 }
 
-func findVideoFile() (string, error) {
-	folderPath := "/usr/src/ultralytics/runs/detect/predict"
+// findVideoFile returns the annotated video, or image, yolo saved in folderPath.
+func findVideoFile(folderPath string) (string, error) {
 	outputPath := ""
 	err := filepath.Walk(folderPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			logger.Errorf("error walking: %v", err)
 			return err
 		}
+		if info.IsDir() && info.Name() == "labels" {
+			return filepath.SkipDir
+		}
 
-		if !info.IsDir() && strings.HasSuffix(info.Name(), ".avi") {
+		switch strings.ToLower(filepath.Ext(info.Name())) {
+		case ".avi", ".mp4", ".jpg", ".jpeg", ".png", ".webp", ".bmp":
+			if info.IsDir() {
+				return nil
+			}
 			logger.Infof("found output file: %v", path)
 			outputPath = path
-			return filepath.SkipDir
+			return filepath.SkipAll
 		}
 
 		return nil
@@ -222,6 +666,9 @@ func findVideoFile() (string, error) {
 		logger.Errorf("error finding video file: %v", err)
 		return "", err
 	}
+	if outputPath == "" {
+		return "", fmt.Errorf("no output file in %v", folderPath)
+	}
 	return outputPath, nil
 }
 
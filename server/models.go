@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultModelsDir = "./models"
+
+// modelInfo is a set of weights jobs can be run with.
+type modelInfo struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Default bool   `json:"default"`
+	// Card is how the model was made, set for models trained by the server.
+	Card *modelCard `json:"card,omitempty"`
+}
+
+// modelCard is saved as <name>.json next to the weights of a registered
+// model.
+type modelCard struct {
+	Training  string    `json:"training,omitempty"`
+	BaseModel string    `json:"base_model"`
+	Dataset   string    `json:"dataset"`
+	Epochs    int       `json:"epochs"`
+	CreatedAt time.Time `json:"created_at"`
+	// Metrics are the validation metrics of the best epoch.
+	Metrics *detectionMetrics `json:"metrics,omitempty"`
+}
+
+func modelsDir() string {
+	if dir := os.Getenv("MODELS_DIR"); dir != "" {
+		return dir
+	}
+	return defaultModelsDir
+}
+
+func modelName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// listModels returns the default model plus every .pt file in MODELS_DIR.
+func listModels() []modelInfo {
+	models := []modelInfo{{Name: modelName(defaultModel), Path: defaultModel, Version: modelVersion(defaultModel), Default: true}}
+	paths, _ := filepath.Glob(filepath.Join(modelsDir(), "*.pt"))
+	sort.Strings(paths)
+	for _, path := range paths {
+		models = append(models, modelInfo{Name: modelName(path), Path: path, Version: modelVersion(path), Card: loadModelCard(path)})
+	}
+	return models
+}
+
+func modelCardPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".json"
+}
+
+func loadModelCard(path string) *modelCard {
+	data, err := os.ReadFile(modelCardPath(path))
+	if err != nil {
+		return nil
+	}
+	card := &modelCard{}
+	if err := json.Unmarshal(data, card); err != nil {
+		logger.Errorf("error decoding model card of %v: %v", path, err)
+		return nil
+	}
+	return card
+}
+
+// registerModel copies weights to MODELS_DIR as name with its card, the
+// model is listed once the weights are in place.
+func registerModel(name, weights string, card modelCard) (modelInfo, error) {
+	if err := os.MkdirAll(modelsDir(), 0755); err != nil {
+		return modelInfo{}, fmt.Errorf("error creating models dir: %v", err)
+	}
+	path := filepath.Join(modelsDir(), name+".pt")
+	if _, err := os.Stat(path); err == nil {
+		return modelInfo{}, fmt.Errorf("model %q already exists", name)
+	}
+	data, err := json.MarshalIndent(card, "", "  ")
+	if err != nil {
+		return modelInfo{}, err
+	}
+	if err := os.WriteFile(modelCardPath(path), data, 0644); err != nil {
+		return modelInfo{}, err
+	}
+	if err := copyFile(weights, path+".tmp"); err != nil {
+		return modelInfo{}, err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return modelInfo{}, err
+	}
+	return modelInfo{Name: name, Path: path, Version: modelVersion(path), Card: &card}, nil
+}
+
+// findModel resolves a model name or path from listModels, empty picks the
+// default model.
+func findModel(name string) (modelInfo, error) {
+	models := listModels()
+	if name == "" {
+		return models[0], nil
+	}
+	for _, m := range models {
+		if m.Name == name || m.Path == name {
+			return m, nil
+		}
+	}
+	return modelInfo{}, fmt.Errorf("unknown model %q", name)
+}
+
+func listModelsHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, listModels())
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	detectionv1alpha1 "github.com/arkusnexus/ai-demo/server/k8s/v1alpha1"
+)
+
+const (
+	// statusReportInterval throttles progress patches of the DetectionJob.
+	statusReportInterval = 2 * time.Second
+	terminationLogPath   = "/dev/termination-log"
+)
+
+// framePattern matches the frame counter yolo prints for videos,
+// "video 1/1 (frame 12/300) ...".
+var framePattern = regexp.MustCompile(`\(frame (\d+)/(\d+)\)`)
+
+// runOneShot is the one-shot mode of the server binary, "server run -source
+// <url>": it runs a single detect job with the flags as the request, prints
+// its events as JSON lines and exits. When DETECTION_JOB_NAME is set, as in
+// the pods the controller starts, it reports progress and artifacts to that
+// DetectionJob. The exit code is 0 when the job is done,
+// exitCodeNotRetryable when it failed in a way retrying won't fix and 1
+// otherwise.
+func runOneShot(ctx context.Context, args []string) int {
+	req := detectRequest{}
+	var confidence string
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.StringVar(&req.Source, "source", "", "url or path of the video or image")
+	fs.StringVar(&req.Model, "model", "", "model name or path")
+	fs.StringVar(&confidence, "confidence", "", "minimum confidence, between 0 and 1")
+	fs.StringVar(&req.Mode, "mode", "", "detect or track")
+	fs.StringVar(&req.Tracker, "tracker", "", "tracker config in track mode")
+	fs.StringVar(&req.Profile, "profile", "", "transcoding profile")
+	fs.BoolVar(&req.Highlights, "highlights", false, "cut a highlights reel")
+	fs.BoolVar(&req.Renditions, "renditions", false, "transcode HLS renditions")
+	if err := fs.Parse(args); err != nil {
+		return exitCodeNotRetryable
+	}
+	var reporter *statusReporter
+	if name := os.Getenv(detectionJobNameEnv); name != "" {
+		var err error
+		reporter, err = newStatusReporter(types.NamespacedName{Namespace: os.Getenv(detectionJobNamespaceEnv), Name: name})
+		if err != nil {
+			// the job still runs, its Job tells whether it worked
+			logger.Errorf("error creating status reporter: %v", err)
+		}
+	}
+
+	if confidence != "" {
+		v, err := strconv.ParseFloat(confidence, 64)
+		if err != nil {
+			return exitOneShot(reporter, newJobError(errCodeInvalidRequest, fmt.Errorf("invalid confidence: %v", err)))
+		}
+		req.Confidence = v
+	}
+	if req.Source == "" {
+		return exitOneShot(reporter, newJobError(errCodeInvalidRequest, fmt.Errorf("source is required")))
+	}
+	j, profile, err := newDetectJob(req)
+	if err != nil {
+		return exitOneShot(reporter, err)
+	}
+	f := newJobFeed(j, "")
+	reporter.update(func(s *detectionv1alpha1.DetectionJobStatus) {
+		s.JobID, s.Stage, s.Progress = j.ID, detectionv1alpha1.StageInference, 0
+	}, true)
+	go j.process(linkContext(f.ctx, ctx), f, profile, req.Highlights)
+
+	// stopping the pod cancels the job
+	go func() {
+		<-ctx.Done()
+		f.cancel()
+	}()
+
+	out := json.NewEncoder(os.Stdout)
+	var failure error
+	f.watch(context.Background(), 0, func(e feedEvent) error {
+		out.Encode(e)
+		switch e.Type {
+		case feedEventLog:
+			for _, line := range e.Msg.LogLines {
+				if m := framePattern.FindStringSubmatch(line.Text); m != nil {
+					frame, _ := strconv.Atoi(m[1])
+					frames, _ := strconv.Atoi(m[2])
+					if frames > 0 {
+						reporter.update(func(s *detectionv1alpha1.DetectionJobStatus) {
+							s.Stage, s.Progress = detectionv1alpha1.StageInference, int32(frame*100/frames)
+						}, false)
+					}
+				}
+			}
+		case feedEventProgress:
+			reporter.update(func(s *detectionv1alpha1.DetectionJobStatus) {
+				s.Stage, s.Progress = detectionv1alpha1.StageTranscode, int32(e.Msg.Progress)
+			}, false)
+		case feedEventError:
+			if e.Msg.Error != nil {
+				failure = e.Msg.Error
+			}
+		}
+		return nil
+	})
+	if failure != nil {
+		return exitOneShot(reporter, failure)
+	}
+
+	saved, err := loadJob(j.ID)
+	if err != nil {
+		return exitOneShot(reporter, newJobError(errCodeInternal, err))
+	}
+	reporter.update(func(s *detectionv1alpha1.DetectionJobStatus) {
+		s.Stage, s.Progress = detectionv1alpha1.StageDone, 100
+		s.Artifacts = jobArtifacts(saved)
+		s.Counts = map[string]int32{}
+		for class, n := range saved.Counts {
+			s.Counts[class] = int32(n)
+		}
+	}, true)
+	return 0
+}
+
+// exitOneShot reports why a one-shot run failed and returns its exit code.
+// The message also goes to the termination log, kubectl describe shows it.
+func exitOneShot(reporter *statusReporter, err error) int {
+	je := asJobError(err)
+	logger.Error("job failed", "error_code", je.Code, "error", je.Error())
+	os.WriteFile(terminationLogPath, []byte(je.Error()), 0644)
+	reporter.update(func(s *detectionv1alpha1.DetectionJobStatus) {
+		s.Message, s.ErrorCode = je.Error(), je.Code
+	}, true)
+	if je.Retryable {
+		return 1
+	}
+	return exitCodeNotRetryable
+}
+
+// jobArtifacts lists the files of a job relative to jobsDir, which is the
+// root of the output volume.
+func jobArtifacts(j *job) []detectionv1alpha1.Artifact {
+	artifacts := []detectionv1alpha1.Artifact{
+		{Name: jobFileName, Path: j.ID + "/" + jobFileName},
+		{Name: jobLogFileName, Path: j.ID + "/" + jobLogFileName},
+	}
+	for _, path := range []string{j.OutputPath, j.VideoPath} {
+		if path != "" {
+			name := filepath.Base(path)
+			artifacts = append(artifacts, detectionv1alpha1.Artifact{Name: name, Path: j.ID + "/" + name})
+		}
+	}
+	prefix := fmt.Sprintf("/static/jobs/%v/", j.ID)
+	for _, a := range j.Artifacts {
+		if rel, ok := strings.CutPrefix(a.URL, prefix); ok && !hasArtifact(artifacts, a.Name) {
+			artifacts = append(artifacts, detectionv1alpha1.Artifact{Name: a.Name, Path: j.ID + "/" + rel})
+		}
+	}
+	return artifacts
+}
+
+func hasArtifact(artifacts []detectionv1alpha1.Artifact, name string) bool {
+	for _, a := range artifacts {
+		if a.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// statusReporter patches the status of the DetectionJob a one-shot run
+// belongs to. A nil reporter drops updates, so runs outside of the cluster
+// don't need one.
+type statusReporter struct {
+	client client.Client
+	key    types.NamespacedName
+	status detectionv1alpha1.DetectionJobStatus
+	last   time.Time
+}
+
+func newStatusReporter(key types.NamespacedName) (*statusReporter, error) {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	scheme, err := newControllerScheme()
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+	return &statusReporter{client: c, key: key}, nil
+}
+
+// update applies change to the reported status and patches it, at most every
+// statusReportInterval unless force is set.
+func (r *statusReporter) update(change func(*detectionv1alpha1.DetectionJobStatus), force bool) {
+	if r == nil {
+		return
+	}
+	change(&r.status)
+	if !force && time.Since(r.last) < statusReportInterval {
+		return
+	}
+	r.last = time.Now()
+	if err := r.patch(); err != nil {
+		logger.Errorf("error reporting status of %v: %v", r.key, err)
+	}
+}
+
+// patch sends the fields the pod owns as a merge patch, the phase and times
+// are left to the controller. Without an error message and errorCode are
+// sent as null, clearing the failure of an earlier pod of the Job.
+func (r *statusReporter) patch() error {
+	status := map[string]any{
+		"jobID":     r.status.JobID,
+		"stage":     r.status.Stage,
+		"progress":  r.status.Progress,
+		"message":   nil,
+		"errorCode": nil,
+	}
+	if r.status.Message != "" {
+		status["message"] = r.status.Message
+		status["errorCode"] = r.status.ErrorCode
+	}
+	if r.status.Artifacts != nil {
+		status["artifacts"] = r.status.Artifacts
+		status["counts"] = r.status.Counts
+	}
+	data, err := json.Marshal(map[string]any{"status": status})
+	if err != nil {
+		return err
+	}
+	dj := &detectionv1alpha1.DetectionJob{ObjectMeta: metav1.ObjectMeta{Namespace: r.key.Namespace, Name: r.key.Name}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return r.client.Status().Patch(ctx, dj, client.RawPatch(types.MergePatchType, data))
+}
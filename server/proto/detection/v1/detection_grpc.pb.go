@@ -0,0 +1,293 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.3
+// source: proto/detection/v1/detection.proto
+
+package detectionv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DetectionService_SubmitJob_FullMethodName  = "/detection.v1.DetectionService/SubmitJob"
+	DetectionService_WatchJob_FullMethodName   = "/detection.v1.DetectionService/WatchJob"
+	DetectionService_GetResult_FullMethodName  = "/detection.v1.DetectionService/GetResult"
+	DetectionService_ListModels_FullMethodName = "/detection.v1.DetectionService/ListModels"
+	DetectionService_CancelJob_FullMethodName  = "/detection.v1.DetectionService/CancelJob"
+)
+
+// DetectionServiceClient is the client API for DetectionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DetectionService runs yolo jobs on the same engine as the HTTP API.
+type DetectionServiceClient interface {
+	// SubmitJob starts a detect job in the background.
+	SubmitJob(ctx context.Context, in *SubmitJobRequest, opts ...grpc.CallOption) (*SubmitJobResponse, error)
+	// WatchJob streams the events of a job until it is done, starting after
+	// after_seq so a client can resume where it left off.
+	WatchJob(ctx context.Context, in *WatchJobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobEvent], error)
+	// GetResult returns the stored state of a job.
+	GetResult(ctx context.Context, in *GetResultRequest, opts ...grpc.CallOption) (*JobResult, error)
+	// ListModels lists the weights jobs can be run with.
+	ListModels(ctx context.Context, in *ListModelsRequest, opts ...grpc.CallOption) (*ListModelsResponse, error)
+	// CancelJob stops a running job.
+	CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*CancelJobResponse, error)
+}
+
+type detectionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDetectionServiceClient(cc grpc.ClientConnInterface) DetectionServiceClient {
+	return &detectionServiceClient{cc}
+}
+
+func (c *detectionServiceClient) SubmitJob(ctx context.Context, in *SubmitJobRequest, opts ...grpc.CallOption) (*SubmitJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitJobResponse)
+	err := c.cc.Invoke(ctx, DetectionService_SubmitJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectionServiceClient) WatchJob(ctx context.Context, in *WatchJobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DetectionService_ServiceDesc.Streams[0], DetectionService_WatchJob_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchJobRequest, JobEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DetectionService_WatchJobClient = grpc.ServerStreamingClient[JobEvent]
+
+func (c *detectionServiceClient) GetResult(ctx context.Context, in *GetResultRequest, opts ...grpc.CallOption) (*JobResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobResult)
+	err := c.cc.Invoke(ctx, DetectionService_GetResult_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectionServiceClient) ListModels(ctx context.Context, in *ListModelsRequest, opts ...grpc.CallOption) (*ListModelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListModelsResponse)
+	err := c.cc.Invoke(ctx, DetectionService_ListModels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *detectionServiceClient) CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*CancelJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelJobResponse)
+	err := c.cc.Invoke(ctx, DetectionService_CancelJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DetectionServiceServer is the server API for DetectionService service.
+// All implementations must embed UnimplementedDetectionServiceServer
+// for forward compatibility.
+//
+// DetectionService runs yolo jobs on the same engine as the HTTP API.
+type DetectionServiceServer interface {
+	// SubmitJob starts a detect job in the background.
+	SubmitJob(context.Context, *SubmitJobRequest) (*SubmitJobResponse, error)
+	// WatchJob streams the events of a job until it is done, starting after
+	// after_seq so a client can resume where it left off.
+	WatchJob(*WatchJobRequest, grpc.ServerStreamingServer[JobEvent]) error
+	// GetResult returns the stored state of a job.
+	GetResult(context.Context, *GetResultRequest) (*JobResult, error)
+	// ListModels lists the weights jobs can be run with.
+	ListModels(context.Context, *ListModelsRequest) (*ListModelsResponse, error)
+	// CancelJob stops a running job.
+	CancelJob(context.Context, *CancelJobRequest) (*CancelJobResponse, error)
+	mustEmbedUnimplementedDetectionServiceServer()
+}
+
+// UnimplementedDetectionServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDetectionServiceServer struct{}
+
+func (UnimplementedDetectionServiceServer) SubmitJob(context.Context, *SubmitJobRequest) (*SubmitJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitJob not implemented")
+}
+func (UnimplementedDetectionServiceServer) WatchJob(*WatchJobRequest, grpc.ServerStreamingServer[JobEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchJob not implemented")
+}
+func (UnimplementedDetectionServiceServer) GetResult(context.Context, *GetResultRequest) (*JobResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetResult not implemented")
+}
+func (UnimplementedDetectionServiceServer) ListModels(context.Context, *ListModelsRequest) (*ListModelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListModels not implemented")
+}
+func (UnimplementedDetectionServiceServer) CancelJob(context.Context, *CancelJobRequest) (*CancelJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+}
+func (UnimplementedDetectionServiceServer) mustEmbedUnimplementedDetectionServiceServer() {}
+func (UnimplementedDetectionServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeDetectionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DetectionServiceServer will
+// result in compilation errors.
+type UnsafeDetectionServiceServer interface {
+	mustEmbedUnimplementedDetectionServiceServer()
+}
+
+func RegisterDetectionServiceServer(s grpc.ServiceRegistrar, srv DetectionServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDetectionServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DetectionService_ServiceDesc, srv)
+}
+
+func _DetectionService_SubmitJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectionServiceServer).SubmitJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectionService_SubmitJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectionServiceServer).SubmitJob(ctx, req.(*SubmitJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectionService_WatchJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchJobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DetectionServiceServer).WatchJob(m, &grpc.GenericServerStream[WatchJobRequest, JobEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DetectionService_WatchJobServer = grpc.ServerStreamingServer[JobEvent]
+
+func _DetectionService_GetResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetResultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectionServiceServer).GetResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectionService_GetResult_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectionServiceServer).GetResult(ctx, req.(*GetResultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectionService_ListModels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListModelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectionServiceServer).ListModels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectionService_ListModels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectionServiceServer).ListModels(ctx, req.(*ListModelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DetectionService_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DetectionServiceServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DetectionService_CancelJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DetectionServiceServer).CancelJob(ctx, req.(*CancelJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DetectionService_ServiceDesc is the grpc.ServiceDesc for DetectionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DetectionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "detection.v1.DetectionService",
+	HandlerType: (*DetectionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitJob",
+			Handler:    _DetectionService_SubmitJob_Handler,
+		},
+		{
+			MethodName: "GetResult",
+			Handler:    _DetectionService_GetResult_Handler,
+		},
+		{
+			MethodName: "ListModels",
+			Handler:    _DetectionService_ListModels_Handler,
+		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _DetectionService_CancelJob_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchJob",
+			Handler:       _DetectionService_WatchJob_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/detection/v1/detection.proto",
+}
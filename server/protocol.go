@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// The detect websocket speaks one of two protocols. Without a subprotocol it
+// sends the html fragments the htmx index page swaps in and takes the fields
+// of its form. Clients that ask for wsProtocolJSON get wsEnvelope messages
+// and send wsRequest messages instead.
+const (
+	wsProtocolJSON = "detect.v1.json"
+	wsVersion      = 1
+
+	wsRequestSubmit = "submit"
+	wsRequestWatch  = "watch"
+	wsRequestCancel = "cancel"
+)
+
+// wsEnvelope is a server message of the JSON protocol. Type is one of the
+// feed event types, Payload depends on it:
+//
+//	queued    wsQueuedPayload
+//	log       wsLogPayload
+//	progress  wsProgressPayload
+//	rules     wsRulesPayload
+//	result    wsResultPayload
+//	error     wsErrorPayload
+//	done      wsDonePayload
+type wsEnvelope struct {
+	Version int       `json:"version"`
+	Type    string    `json:"type"`
+	JobID   string    `json:"job_id,omitempty"`
+	Seq     int       `json:"seq,omitempty"`
+	Time    time.Time `json:"time"`
+	Payload any       `json:"payload,omitempty"`
+}
+
+type wsQueuedPayload struct {
+	Source string `json:"source"`
+}
+
+type wsLogPayload struct {
+	Lines []logLine `json:"lines"`
+}
+
+type wsProgressPayload struct {
+	// Percent of the transcode done.
+	Percent float64 `json:"percent"`
+}
+
+type wsRulesPayload struct {
+	Events []ruleEvent `json:"events"`
+}
+
+type wsResultPayload struct {
+	VideoURL      string         `json:"video_url,omitempty"`
+	VideoType     string         `json:"video_type,omitempty"`
+	PlaylistURL   string         `json:"playlist_url,omitempty"`
+	ImageURL      string         `json:"image_url,omitempty"`
+	ExportURL     string         `json:"export_url"`
+	LogURL        string         `json:"log_url"`
+	HighlightsURL string         `json:"highlights_url,omitempty"`
+	Tracked       bool           `json:"tracked"`
+	Counts        map[string]int `json:"counts"`
+	Summary       *videoSummary  `json:"summary,omitempty"`
+}
+
+type wsErrorPayload struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+type wsDonePayload struct {
+	Status string `json:"status,omitempty"`
+}
+
+// wsRequest is a client message of the JSON protocol. Submit carries the job
+// in Job, watch and cancel name it in JobID. Watch resumes after AfterSeq.
+type wsRequest struct {
+	Type     string         `json:"type"`
+	JobID    string         `json:"job_id,omitempty"`
+	AfterSeq int            `json:"after_seq,omitempty"`
+	Job      *detectRequest `json:"job,omitempty"`
+}
+
+// envelope converts a feed event into its JSON protocol message.
+func (e feedEvent) envelope() wsEnvelope {
+	env := wsEnvelope{Version: wsVersion, Type: e.Type, JobID: e.JobID, Seq: e.Seq, Time: e.Time}
+	switch e.Type {
+	case feedEventQueued:
+		env.Payload = wsQueuedPayload{Source: e.Msg.Message}
+	case feedEventLog:
+		env.Payload = wsLogPayload{Lines: e.Msg.LogLines}
+	case feedEventProgress:
+		env.Payload = wsProgressPayload{Percent: e.Msg.Progress}
+	case feedEventRules:
+		env.Payload = wsRulesPayload{Events: e.Msg.Events}
+	case feedEventResult:
+		env.Payload = wsResultPayload{
+			VideoURL:      e.Msg.VideoURL,
+			VideoType:     e.Msg.VideoType,
+			PlaylistURL:   e.Msg.PlaylistURL,
+			ImageURL:      e.Msg.ImageURL,
+			ExportURL:     e.Msg.ExportURL,
+			LogURL:        e.Msg.LogURL,
+			HighlightsURL: e.Msg.HighlightsURL,
+			Tracked:       e.Msg.Tracked,
+			Counts:        e.Msg.Counts,
+			Summary:       e.Msg.Summary,
+		}
+	case feedEventError:
+		je := e.Msg.Error
+		if je == nil {
+			je = newJobError(errCodeInternal, errors.New(e.Msg.LogLine))
+		}
+		env.Payload = wsErrorPayload{Code: je.Code, Message: je.Message, Retryable: je.Retryable, Detail: je.Detail}
+	case feedEventDone:
+		payload := wsDonePayload{}
+		if j, err := loadJob(e.JobID); err == nil {
+			payload.Status = j.Status
+		}
+		env.Payload = payload
+	}
+	return env
+}
+
+// wsRequest converts a message of the htmx form into a request, a job id
+// watches that job and anything else with a source submits one.
+func (m message) wsRequest() (wsRequest, bool) {
+	if m.JobID != "" {
+		return wsRequest{Type: wsRequestWatch, JobID: m.JobID}, true
+	}
+	if len(m.Message) == 0 {
+		return wsRequest{}, false
+	}
+	req := m.detectRequest()
+	return wsRequest{Type: wsRequestSubmit, Job: &req}, true
+}
+
+// readWSRequest decodes a client message in the protocol of the connection,
+// ok is false for messages that should be ignored.
+func readWSRequest(data []byte, jsonProtocol bool) (req wsRequest, ok bool, err error) {
+	if !jsonProtocol {
+		msg := message{}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return wsRequest{}, false, err
+		}
+		req, ok = msg.wsRequest()
+		return req, ok, nil
+	}
+
+	if err := json.Unmarshal(data, &req); err != nil {
+		return wsRequest{}, false, err
+	}
+	switch req.Type {
+	case wsRequestSubmit:
+		if req.Job == nil {
+			return wsRequest{}, false, fmt.Errorf("submit requires a job")
+		}
+	case wsRequestWatch, wsRequestCancel:
+		if req.JobID == "" {
+			return wsRequest{}, false, fmt.Errorf("%v requires a job_id", req.Type)
+		}
+	default:
+		return wsRequest{}, false, fmt.Errorf("unknown request type %q", req.Type)
+	}
+	return req, true, nil
+}
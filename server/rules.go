@@ -0,0 +1,439 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	rulesDir = "./rules"
+
+	ruleTypeZone = "zone"
+	ruleTypeLine = "line"
+
+	// forward is crossing a line from the left of A->B to its right.
+	directionAny      = "any"
+	directionForward  = "forward"
+	directionBackward = "backward"
+
+	eventZoneEnter = "zone_enter"
+	eventZoneExit  = "zone_exit"
+	eventLineCross = "line_cross"
+)
+
+// point is a position in normalized image coordinates, 0,0 is the top left.
+type point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// rule is a zone or a line plus the conditions a tracked object has to meet
+// to trigger it. Objects are placed at the center of their box.
+type rule struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Type    string  `json:"type"`
+	Polygon []point `json:"polygon,omitempty"`
+	Line    []point `json:"line,omitempty"`
+	// Classes limits the rule to some classes, empty matches every class.
+	Classes       []string `json:"classes,omitempty"`
+	MinConfidence float64  `json:"min_confidence"`
+	// DwellSeconds is how long an object stays in a zone before it counts as entered.
+	DwellSeconds float64 `json:"dwell_seconds"`
+	// Direction is "any", "forward" or "backward" for line rules.
+	Direction string `json:"direction,omitempty"`
+}
+
+// ruleSet holds the rules for a source, "*" applies to every source.
+type ruleSet struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	Rules  []rule `json:"rules"`
+}
+
+// ruleEvent is emitted when a tracked object triggers a rule.
+type ruleEvent struct {
+	RuleID     string  `json:"rule_id"`
+	RuleName   string  `json:"rule_name"`
+	Type       string  `json:"type"`
+	TrackID    int     `json:"track_id"`
+	Class      string  `json:"class"`
+	Frame      int     `json:"frame"`
+	Time       float64 `json:"time"`
+	Confidence float64 `json:"confidence"`
+	Direction  string  `json:"direction,omitempty"`
+}
+
+func (r *rule) validate() error {
+	inRange := func(points []point) bool {
+		for _, p := range points {
+			if p.X < 0 || p.X > 1 || p.Y < 0 || p.Y > 1 {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch r.Type {
+	case ruleTypeZone:
+		if len(r.Polygon) < 3 {
+			return fmt.Errorf("rule %q: a zone needs at least 3 points", r.Name)
+		}
+		if !inRange(r.Polygon) {
+			return fmt.Errorf("rule %q: points must be normalized", r.Name)
+		}
+	case ruleTypeLine:
+		if len(r.Line) != 2 {
+			return fmt.Errorf("rule %q: a line needs 2 points", r.Name)
+		}
+		if !inRange(r.Line) {
+			return fmt.Errorf("rule %q: points must be normalized", r.Name)
+		}
+		switch r.Direction {
+		case "":
+			r.Direction = directionAny
+		case directionAny, directionForward, directionBackward:
+		default:
+			return fmt.Errorf("rule %q: unknown direction %q", r.Name, r.Direction)
+		}
+	default:
+		return fmt.Errorf("rule %q: unknown type %q", r.Name, r.Type)
+	}
+	if r.MinConfidence < 0 || r.MinConfidence > 1 {
+		return fmt.Errorf("rule %q: min_confidence must be between 0 and 1", r.Name)
+	}
+	if r.DwellSeconds < 0 {
+		return fmt.Errorf("rule %q: dwell_seconds can't be negative", r.Name)
+	}
+	if r.ID == "" {
+		r.ID = newJobID()
+	}
+	return nil
+}
+
+func (r rule) matches(d detection) bool {
+	if d.Confidence < r.MinConfidence {
+		return false
+	}
+	if len(r.Classes) == 0 {
+		return true
+	}
+	for _, class := range r.Classes {
+		if strings.EqualFold(class, d.Class) {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateRules replays every track through the rules and returns the events
+// ordered by time. Detections without a track id are ignored.
+func evaluateRules(rules []rule, detections []detection) []ruleEvent {
+	byTrack := map[int][]detection{}
+	for _, d := range detections {
+		if d.TrackID != 0 {
+			byTrack[d.TrackID] = append(byTrack[d.TrackID], d)
+		}
+	}
+
+	events := []ruleEvent{}
+	for _, trackDetections := range byTrack {
+		sort.Slice(trackDetections, func(a, b int) bool {
+			return trackDetections[a].Frame < trackDetections[b].Frame
+		})
+		for _, r := range rules {
+			switch r.Type {
+			case ruleTypeZone:
+				events = append(events, evaluateZone(r, trackDetections)...)
+			case ruleTypeLine:
+				events = append(events, evaluateLine(r, trackDetections)...)
+			}
+		}
+	}
+	sort.SliceStable(events, func(a, b int) bool {
+		if events[a].Frame == events[b].Frame {
+			return events[a].TrackID < events[b].TrackID
+		}
+		return events[a].Frame < events[b].Frame
+	})
+	return events
+}
+
+func newRuleEvent(r rule, eventType string, d detection) ruleEvent {
+	return ruleEvent{
+		RuleID:     r.ID,
+		RuleName:   r.Name,
+		Type:       eventType,
+		TrackID:    d.TrackID,
+		Class:      d.Class,
+		Frame:      d.Frame,
+		Time:       d.Time,
+		Confidence: d.Confidence,
+	}
+}
+
+// evaluateZone emits an enter event once a track has stayed in the zone for
+// the dwell time and an exit event when it leaves after entering.
+func evaluateZone(r rule, detections []detection) []ruleEvent {
+	events := []ruleEvent{}
+	inZone, entered := false, false
+	since := 0.0
+	for _, d := range detections {
+		if !r.matches(d) {
+			continue
+		}
+		inside := pointInPolygon(point{X: d.X, Y: d.Y}, r.Polygon)
+		switch {
+		case inside && !inZone:
+			inZone, since = true, d.Time
+		case !inside && inZone:
+			if entered {
+				events = append(events, newRuleEvent(r, eventZoneExit, d))
+			}
+			inZone, entered = false, false
+			continue
+		}
+		if inside && !entered && d.Time-since >= r.DwellSeconds {
+			events = append(events, newRuleEvent(r, eventZoneEnter, d))
+			entered = true
+		}
+	}
+	return events
+}
+
+// evaluateLine emits an event every time the path of a track crosses the line.
+func evaluateLine(r rule, detections []detection) []ruleEvent {
+	events := []ruleEvent{}
+	a, b := r.Line[0], r.Line[1]
+	var prev *detection
+	for i := range detections {
+		d := detections[i]
+		if !r.matches(d) {
+			continue
+		}
+		if prev != nil {
+			p0 := point{X: prev.X, Y: prev.Y}
+			p1 := point{X: d.X, Y: d.Y}
+			if segmentsIntersect(p0, p1, a, b) {
+				// in image coordinates y grows down, so a positive cross
+				// product means the point is right of A->B.
+				direction := directionForward
+				if cross(a, b, p1) < 0 {
+					direction = directionBackward
+				}
+				if r.Direction == directionAny || r.Direction == direction {
+					event := newRuleEvent(r, eventLineCross, d)
+					event.Direction = direction
+					events = append(events, event)
+				}
+			}
+		}
+		prev = &detections[i]
+	}
+	return events
+}
+
+// cross is the z component of (b-a) x (p-a).
+func cross(a, b, p point) float64 {
+	return (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+}
+
+// segmentsIntersect reports whether p0->p1 strictly crosses a->b.
+func segmentsIntersect(p0, p1, a, b point) bool {
+	d1 := cross(a, b, p0)
+	d2 := cross(a, b, p1)
+	d3 := cross(p0, p1, a)
+	d4 := cross(p0, p1, b)
+	return ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0))
+}
+
+// pointInPolygon uses ray casting.
+func pointInPolygon(p point, polygon []point) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+var rulesMu sync.Mutex
+
+func ruleSetPath(id string) string {
+	return filepath.Join(rulesDir, id+".json")
+}
+
+func loadRuleSets() ([]ruleSet, error) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	paths, err := filepath.Glob(filepath.Join(rulesDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sets := []ruleSet{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %v: %v", path, err)
+		}
+		set := ruleSet{}
+		if err := json.Unmarshal(data, &set); err != nil {
+			return nil, fmt.Errorf("error decoding %v: %v", path, err)
+		}
+		sets = append(sets, set)
+	}
+	return sets, nil
+}
+
+func saveRuleSet(set ruleSet) error {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	if err := os.MkdirAll(rulesDir, 0755); err != nil {
+		return fmt.Errorf("error creating rules dir: %v", err)
+	}
+	data, err := json.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(ruleSetPath(set.ID), data, 0644)
+}
+
+// rulesForSource returns the rules of every set matching the source.
+func rulesForSource(source string) []rule {
+	sets, err := loadRuleSets()
+	if err != nil {
+		logger.Errorf("error loading rules: %v", err)
+		return nil
+	}
+	rules := []rule{}
+	for _, set := range sets {
+		if set.Source == "*" || set.Source == source {
+			rules = append(rules, set.Rules...)
+		}
+	}
+	return rules
+}
+
+// applyRules evaluates the rules for the job source and stores the events.
+func (j *job) applyRules() {
+	rules := rulesForSource(j.Source)
+	if len(rules) == 0 {
+		return
+	}
+	j.Events = evaluateRules(rules, j.Detections)
+	logger.Infof("job %v: %v rule events", j.ID, len(j.Events))
+}
+
+func decodeRuleSet(r *http.Request) (ruleSet, error) {
+	set := ruleSet{}
+	if err := json.NewDecoder(r.Body).Decode(&set); err != nil {
+		return set, fmt.Errorf("invalid rule set: %v", err)
+	}
+	if set.Source == "" {
+		return set, fmt.Errorf("source is required, use \"*\" for every source")
+	}
+	for i := range set.Rules {
+		if err := set.Rules[i].validate(); err != nil {
+			return set, err
+		}
+	}
+	return set, nil
+}
+
+// listRuleSetsHandler GET /rules
+func listRuleSetsHandler(w http.ResponseWriter, r *http.Request) {
+	sets, err := loadRuleSets()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, sets)
+}
+
+// createRuleSetHandler POST /rules
+func createRuleSetHandler(w http.ResponseWriter, r *http.Request) {
+	set, err := decodeRuleSet(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	set.ID = newJobID()
+	if err := saveRuleSet(set); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusCreated, set)
+}
+
+// updateRuleSetHandler PUT /rules/{id}
+func updateRuleSetHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !jobIDPattern.MatchString(id) {
+		http.Error(w, "rule set not found", http.StatusNotFound)
+		return
+	}
+	if _, err := os.Stat(ruleSetPath(id)); err != nil {
+		http.Error(w, "rule set not found", http.StatusNotFound)
+		return
+	}
+	set, err := decodeRuleSet(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	set.ID = id
+	if err := saveRuleSet(set); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, set)
+}
+
+// deleteRuleSetHandler DELETE /rules/{id}
+func deleteRuleSetHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !jobIDPattern.MatchString(id) {
+		http.Error(w, "rule set not found", http.StatusNotFound)
+		return
+	}
+	rulesMu.Lock()
+	err := os.Remove(ruleSetPath(id))
+	rulesMu.Unlock()
+	if err != nil {
+		http.Error(w, "rule set not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// evaluateJobRulesHandler re-runs the current rules over a finished job.
+// POST /jobs/{id}/rules
+func evaluateJobRulesHandler(w http.ResponseWriter, r *http.Request) {
+	j, err := loadJob(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+	// rules follow objects across frames, plain detections have no tracks
+	if j.Mode != predictModeTrack {
+		http.Error(w, "rules need a tracked job, run the job in track mode", http.StatusBadRequest)
+		return
+	}
+	j.Events = nil
+	j.applyRules()
+	if err := j.save(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, j.Events)
+}
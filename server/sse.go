@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	sseKeepAlive  = 15 * time.Second
+	sseRetryDelay = 3 * time.Second
+)
+
+// createJobHandler starts a detect job from a JSON or form body with the
+// same fields the websocket takes, for clients that can't use websockets.
+// Multipart forms can upload the media as "file" instead of a url.
+func createJobHandler(w http.ResponseWriter, r *http.Request) {
+	msg := message{}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			writeError(w, newJobError(errCodeInvalidRequest, err))
+			return
+		}
+	} else {
+		if err := r.ParseMultipartForm(maxUploadMemory); err != nil && err != http.ErrNotMultipart {
+			writeError(w, newJobError(errCodeInvalidRequest, err))
+			return
+		}
+		msg.Message = r.FormValue("message")
+		msg.Model = r.FormValue("model")
+		if v := r.FormValue("confidence"); v != "" {
+			confidence, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				writeError(w, newJobError(errCodeInvalidRequest, fmt.Errorf("invalid confidence: %v", err)))
+				return
+			}
+			msg.Confidence = confidence
+		}
+		msg.Mode = r.FormValue("mode")
+		msg.Tracker = r.FormValue("tracker")
+		msg.Profile = r.FormValue("profile")
+		msg.Highlights = r.FormValue("highlights")
+		msg.Renditions = r.FormValue("renditions")
+
+		if file, header, err := r.FormFile("file"); err == nil {
+			defer file.Close()
+			path, err := saveUpload(file, header.Filename)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			msg.Message = path
+		}
+	}
+
+	f, err := startDetectJob(r.Context(), msg.detectRequest(), clientID(w, r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{
+		"id":     f.JobID,
+		"events": fmt.Sprintf("/jobs/%v/events", f.JobID),
+	})
+}
+
+// jobEventsHandler streams the events of a job as server-sent events. The
+// event id is the feed sequence number so a reconnecting EventSource resumes
+// after Last-Event-ID. Data is the html fragment the websocket would send, or
+// the event as JSON with format=json.
+func jobEventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	id := mux.Vars(r)["id"]
+	after, _ := strconv.Atoi(r.Header.Get("Last-Event-ID"))
+	if v := r.URL.Query().Get("after"); v != "" {
+		after, _ = strconv.Atoi(v)
+	}
+	asJSON := r.URL.Query().Get("format") == "json"
+
+	f := findFeed(id)
+	var j *job
+	if f == nil {
+		var err error
+		if j, err = loadJob(id); err != nil {
+			http.Error(w, "job not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// stop nginx style proxies from buffering the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// the keep-alive goroutine may still tick after the handler returned,
+	// when w must no longer be used
+	var mu sync.Mutex
+	closed := false
+	defer func() {
+		mu.Lock()
+		closed = true
+		mu.Unlock()
+	}()
+	write := func(s string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return io.ErrClosedPipe
+		}
+		if _, err := fmt.Fprint(w, s); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+	send := func(e feedEvent) error {
+		return write(formatSSE(e, asJSON))
+	}
+	write(fmt.Sprintf("retry: %v\n\n", sseRetryDelay.Milliseconds()))
+
+	// the feed is gone, the result is still on disk
+	if f == nil {
+		send(feedEvent{Type: feedEventResult, JobID: j.ID, Time: time.Now(), Msg: j.resultMessage()})
+		send(feedEvent{Type: feedEventDone, JobID: j.ID, Time: time.Now(), Msg: message{JobID: j.ID}})
+		return
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(sseKeepAlive)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				write(": keep-alive\n\n")
+			}
+		}
+	}()
+
+	if err := f.watch(r.Context(), after, send); err != nil && r.Context().Err() == nil {
+		logger.Errorf("error streaming job %v: %v", id, err)
+	}
+}
+
+// formatSSE writes an event in the text/event-stream format, one data line
+// per line of payload.
+func formatSSE(e feedEvent, asJSON bool) string {
+	var data []byte
+	if asJSON {
+		data, _ = json.Marshal(e)
+	} else {
+		data = bytes.Join(e.frames(), []byte("\n"))
+	}
+
+	var b strings.Builder
+	if e.Seq > 0 {
+		fmt.Fprintf(&b, "id: %v\n", e.Seq)
+	}
+	fmt.Fprintf(&b, "event: %v\n", e.Type)
+	for _, line := range strings.Split(string(data), "\n") {
+		fmt.Fprintf(&b, "data: %v\n", strings.TrimSuffix(line, "\r"))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
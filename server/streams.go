@@ -0,0 +1,460 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	streamsDir = "./static/streams"
+
+	streamStatusConnecting   = "connecting"
+	streamStatusRunning      = "running"
+	streamStatusReconnecting = "reconnecting"
+	streamStatusStopped      = "stopped"
+
+	maxReconnectDelay = 30 * time.Second
+	// maxSegmentBacklog is how many finished segments wait for detection,
+	// older ones are dropped when detection is slower than the camera.
+	maxSegmentBacklog = 2
+)
+
+// streamConfig describes a camera to attach to. Any source ffmpeg can read
+// works, for example rtsp://camera/stream or an HTTP MJPEG url. For local
+// testing a file can be served as MJPEG with:
+//
+//	ffmpeg -re -stream_loop -1 -i sample.mp4 -f mpjpeg -listen 1 http://127.0.0.1:8090/
+type streamConfig struct {
+	Source string `json:"source"`
+	// Format forces the ffmpeg input format, e.g. "mpjpeg" for MJPEG streams.
+	Format     string  `json:"format,omitempty"`
+	Model      string  `json:"model"`
+	Confidence float64 `json:"confidence"`
+	// SegmentSeconds is how much footage is buffered before running detection.
+	SegmentSeconds int `json:"segment_seconds"`
+	// Window is how many annotated segments are kept.
+	Window int `json:"window"`
+}
+
+func (c *streamConfig) setDefaults() {
+	if c.Model == "" {
+		c.Model = defaultModel
+	}
+	if c.Confidence <= 0 {
+		c.Confidence = defaultConfidence
+	}
+	if c.SegmentSeconds <= 0 {
+		c.SegmentSeconds = 10
+	}
+	if c.Window <= 0 {
+		c.Window = 6
+	}
+}
+
+// streamSegment is an annotated piece of the rolling window.
+type streamSegment struct {
+	Index      int            `json:"index"`
+	StartedAt  time.Time      `json:"started_at"`
+	Duration   float64        `json:"duration"`
+	URL        string         `json:"url"`
+	Detections int            `json:"detections"`
+	Counts     map[string]int `json:"counts"`
+}
+
+// streamStatus is the json view of a session.
+type streamStatus struct {
+	ID          string          `json:"id"`
+	Config      streamConfig    `json:"config"`
+	Status      string          `json:"status"`
+	StartedAt   time.Time       `json:"started_at"`
+	Reconnects  int             `json:"reconnects"`
+	LastError   string          `json:"last_error,omitempty"`
+	Segments    int             `json:"segments"`
+	Dropped     int             `json:"dropped"`
+	PlaylistURL string          `json:"playlist_url"`
+	Window      []streamSegment `json:"window"`
+}
+
+// streamSession captures a camera into short segments with ffmpeg and runs
+// detection on every finished segment until it is stopped.
+type streamSession struct {
+	mu        sync.Mutex
+	status    streamStatus
+	capturing bool
+	// attempt is when ffmpeg was last started, segments written after it
+	// mean the source is connected.
+	attempt time.Time
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+var (
+	streamsMu sync.Mutex
+	streams   = map[string]*streamSession{}
+)
+
+func newStreamSession(config streamConfig) *streamSession {
+	id := newJobID()
+	return &streamSession{
+		status: streamStatus{
+			ID:          id,
+			Config:      config,
+			Status:      streamStatusConnecting,
+			StartedAt:   time.Now(),
+			PlaylistURL: fmt.Sprintf("/static/streams/%v/live.m3u8", id),
+			Window:      []streamSegment{},
+		},
+		done: make(chan struct{}),
+	}
+}
+
+func (s *streamSession) dir() string {
+	return filepath.Join(streamsDir, s.status.ID)
+}
+
+func (s *streamSession) snapshot() streamStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status
+	status.Window = append([]streamSegment{}, s.status.Window...)
+	return status
+}
+
+func (s *streamSession) setCapturing(capturing bool, status string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.capturing = capturing
+	s.status.Status = status
+	if err != nil {
+		s.status.LastError = err.Error()
+	}
+}
+
+// attach marks ffmpeg as started without changing the status, it stays
+// connecting or reconnecting until markConnected sees its first segment.
+func (s *streamSession) attach() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.capturing = true
+	s.attempt = time.Now()
+	return s.attempt
+}
+
+// markConnected switches the session to running once ffmpeg has written a
+// segment since it was last started, ffmpeg only opens its output after it
+// got through to the source.
+func (s *streamSession) markConnected(segments []string) {
+	if len(segments) == 0 {
+		return
+	}
+	info, err := os.Stat(segments[len(segments)-1])
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.capturing && s.status.Status != streamStatusRunning && !info.ModTime().Before(s.attempt) {
+		s.status.Status = streamStatusRunning
+	}
+}
+
+func (s *streamSession) start() error {
+	for _, dir := range []string{"raw", "work", "window"} {
+		if err := os.MkdirAll(filepath.Join(s.dir(), dir), 0755); err != nil {
+			return fmt.Errorf("error creating stream dir: %v", err)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	processed := make(chan struct{})
+	go func() {
+		s.processSegments(ctx)
+		close(processed)
+	}()
+	go func() {
+		s.capture(ctx)
+		<-processed
+		s.setCapturing(false, streamStatusStopped, nil)
+		close(s.done)
+	}()
+	return nil
+}
+
+func (s *streamSession) stop() {
+	s.cancel()
+	<-s.done
+}
+
+// capture keeps ffmpeg attached to the source, reconnecting with an
+// exponential backoff whenever the stream drops.
+func (s *streamSession) capture(ctx context.Context) {
+	config := s.status.Config
+	delay := time.Second
+	for ctx.Err() == nil {
+		started := s.attach()
+
+		args := []string{"-hide_banner", "-loglevel", "error"}
+		if strings.HasPrefix(config.Source, "rtsp://") {
+			args = append(args, "-rtsp_transport", "tcp")
+		}
+		if config.Format != "" {
+			args = append(args, "-f", config.Format)
+		}
+		args = append(args,
+			"-i", config.Source,
+			"-an", "-c:v", "libx264", "-preset", "veryfast",
+			"-f", "segment",
+			"-segment_time", fmt.Sprint(config.SegmentSeconds),
+			"-reset_timestamps", "1",
+			"-strftime", "1",
+			filepath.Join(s.dir(), "raw", "%Y%m%d%H%M%S.mp4"),
+		)
+		err := executeCommand(ctx, "ffmpeg", ".", args)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = fmt.Errorf("stream ended")
+		}
+		logger.Errorf("stream %v disconnected: %v", s.status.ID, err)
+
+		if time.Since(started) > maxReconnectDelay {
+			delay = time.Second
+		}
+		s.setCapturing(false, streamStatusReconnecting, err)
+		s.mu.Lock()
+		s.status.Reconnects++
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// processSegments runs detection on every segment ffmpeg has finished
+// writing. While capturing, the newest segment is still being written.
+func (s *streamSession) processSegments(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		segments, err := filepath.Glob(filepath.Join(s.dir(), "raw", "*.mp4"))
+		if err != nil {
+			logger.Errorf("error listing segments: %v", err)
+			continue
+		}
+		sort.Strings(segments)
+		s.markConnected(segments)
+		s.mu.Lock()
+		if s.capturing && len(segments) > 0 {
+			segments = segments[:len(segments)-1]
+		}
+		s.mu.Unlock()
+		segments = s.dropBacklog(segments)
+
+		for _, segment := range segments {
+			if ctx.Err() != nil {
+				return
+			}
+			if err := s.processSegment(ctx, segment); err != nil {
+				logger.Errorf("stream %v: error processing %v: %v", s.status.ID, segment, err)
+			}
+			os.Remove(segment)
+		}
+	}
+}
+
+// dropBacklog removes the oldest segments beyond maxSegmentBacklog so the
+// window stays live, and returns the ones left.
+func (s *streamSession) dropBacklog(segments []string) []string {
+	if len(segments) <= maxSegmentBacklog {
+		return segments
+	}
+	stale := segments[:len(segments)-maxSegmentBacklog]
+	for _, segment := range stale {
+		os.Remove(segment)
+	}
+	s.mu.Lock()
+	s.status.Dropped += len(stale)
+	s.mu.Unlock()
+	logger.Infof("stream %v: detection is behind, dropped %v segments", s.status.ID, len(stale))
+	return segments[len(stale):]
+}
+
+func (s *streamSession) processSegment(ctx context.Context, segment string) error {
+	config := s.status.Config
+	s.mu.Lock()
+	index := s.status.Segments
+	s.status.Segments++
+	s.mu.Unlock()
+
+	workDir := filepath.Join(s.dir(), "work", fmt.Sprint(index))
+	defer os.RemoveAll(workDir)
+	opts := predictOptions{Model: config.Model, Source: segment, Confidence: config.Confidence, Mode: predictModeDetect}
+	result, err := predict(ctx, opts, workDir, func(line string) {})
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%06d.ts", index)
+	args := []string{"-y", "-i", result.OutputPath, "-c:v", "libx264", "-preset", "veryfast", "-f", "mpegts", filepath.Join(s.dir(), "window", name)}
+	if err := executeCommand(ctx, "ffmpeg", ".", args); err != nil {
+		return err
+	}
+
+	started, _ := time.ParseInLocation("20060102150405", strings.TrimSuffix(filepath.Base(segment), ".mp4"), time.Local)
+	entry := streamSegment{
+		Index:      index,
+		StartedAt:  started,
+		Duration:   float64(config.SegmentSeconds),
+		URL:        fmt.Sprintf("/static/streams/%v/window/%v", s.status.ID, name),
+		Detections: len(result.Detections),
+		Counts:     map[string]int{},
+	}
+	if result.Media.FPS > 0 && result.Media.Frames > 0 {
+		entry.Duration = float64(result.Media.Frames) / result.Media.FPS
+	}
+	for _, d := range result.Detections {
+		entry.Counts[d.Class]++
+	}
+	publishDetections(s.status.ID, config.Source, config.Model, result.Detections)
+
+	s.mu.Lock()
+	s.status.Window = append(s.status.Window, entry)
+	for len(s.status.Window) > config.Window {
+		os.Remove(filepath.Join(s.dir(), "window", fmt.Sprintf("%06d.ts", s.status.Window[0].Index)))
+		s.status.Window = s.status.Window[1:]
+	}
+	window := append([]streamSegment{}, s.status.Window...)
+	s.mu.Unlock()
+
+	return s.writePlaylist(window)
+}
+
+// writePlaylist publishes the rolling window as a live HLS playlist.
+func (s *streamSession) writePlaylist(window []streamSegment) error {
+	if len(window) == 0 {
+		return nil
+	}
+	target := 0.0
+	for _, segment := range window {
+		target = max(target, segment.Duration)
+	}
+
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	fmt.Fprintf(&b, "#EXT-X-TARGETDURATION:%d\n", int(target)+1)
+	fmt.Fprintf(&b, "#EXT-X-MEDIA-SEQUENCE:%d\n", window[0].Index)
+	fmt.Fprintf(&b, "#EXT-X-DISCONTINUITY-SEQUENCE:%d\n", window[0].Index)
+	for _, segment := range window {
+		fmt.Fprintf(&b, "#EXT-X-DISCONTINUITY\n#EXTINF:%.3f,\nwindow/%06d.ts\n", segment.Duration, segment.Index)
+	}
+
+	path := filepath.Join(s.dir(), "live.m3u8")
+	if err := os.WriteFile(path+".tmp", []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// startStreamHandler attaches to a camera and starts continuous detection.
+// POST /streams
+func startStreamHandler(w http.ResponseWriter, r *http.Request) {
+	config := streamConfig{}
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if config.Source == "" {
+		http.Error(w, "source is required", http.StatusBadRequest)
+		return
+	}
+	model, err := findModel(config.Model)
+	if err != nil {
+		writeError(w, newJobError(errCodeInvalidRequest, err))
+		return
+	}
+	if err := checkModel(model.Path); err != nil {
+		writeError(w, err)
+		return
+	}
+	config.Model = model.Path
+	config.setDefaults()
+
+	s := newStreamSession(config)
+	if err := s.start(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	streamsMu.Lock()
+	streams[s.status.ID] = s
+	streamsMu.Unlock()
+	logger.Infof("stream %v started for %v", s.status.ID, config.Source)
+
+	writeJSON(w, http.StatusCreated, s.snapshot())
+}
+
+// listStreamsHandler returns the status of every session.
+// GET /streams
+func listStreamsHandler(w http.ResponseWriter, r *http.Request) {
+	streamsMu.Lock()
+	statuses := []streamStatus{}
+	for _, s := range streams {
+		statuses = append(statuses, s.snapshot())
+	}
+	streamsMu.Unlock()
+	sort.Slice(statuses, func(a, b int) bool {
+		return statuses[a].StartedAt.Before(statuses[b].StartedAt)
+	})
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+// streamStatusHandler returns the status of a session.
+// GET /streams/{id}
+func streamStatusHandler(w http.ResponseWriter, r *http.Request) {
+	streamsMu.Lock()
+	s, ok := streams[mux.Vars(r)["id"]]
+	streamsMu.Unlock()
+	if !ok {
+		http.Error(w, "stream not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.snapshot())
+}
+
+// stopStreamHandler stops a session and forgets it, its files are kept.
+// DELETE /streams/{id}
+func stopStreamHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	streamsMu.Lock()
+	s, ok := streams[id]
+	delete(streams, id)
+	streamsMu.Unlock()
+	if !ok {
+		http.Error(w, "stream not found", http.StatusNotFound)
+		return
+	}
+	s.stop()
+	logger.Infof("stream %v stopped", id)
+	writeJSON(w, http.StatusOK, s.snapshot())
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// streamTestModel registers weights in a temporary MODELS_DIR so the
+// stream passes model validation.
+func streamTestModel(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("MODELS_DIR", dir)
+	if err := os.WriteFile(filepath.Join(dir, "m.pt"), []byte("weights"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func startTestStream(t *testing.T, config streamConfig) (*httptest.ResponseRecorder, streamStatus) {
+	t.Helper()
+	body, _ := json.Marshal(config)
+	w := httptest.NewRecorder()
+	startStreamHandler(w, httptest.NewRequest(http.MethodPost, "/streams", bytes.NewReader(body)))
+	status := streamStatus{}
+	if w.Code == http.StatusCreated {
+		if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { stopTestStream(t, status.ID) })
+	}
+	return w, status
+}
+
+func stopTestStream(t *testing.T, id string) streamStatus {
+	t.Helper()
+	r := mux.SetURLVars(httptest.NewRequest(http.MethodDelete, "/streams/"+id, nil), map[string]string{"id": id})
+	w := httptest.NewRecorder()
+	stopStreamHandler(w, r)
+	status := streamStatus{}
+	if w.Code == http.StatusOK {
+		json.NewDecoder(w.Body).Decode(&status)
+	}
+	return status
+}
+
+// waitForStream polls the session until ok accepts its status.
+func waitForStream(t *testing.T, id string, ok func(streamStatus) bool) streamStatus {
+	t.Helper()
+	streamsMu.Lock()
+	s := streams[id]
+	streamsMu.Unlock()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		status := s.snapshot()
+		if ok(status) {
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("stream %v: timed out, last status %+v", id, status)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestStartStreamValidatesModel(t *testing.T) {
+	chdirTemp(t)
+	streamTestModel(t)
+
+	w, _ := startTestStream(t, streamConfig{Source: "rtsp://camera/stream", Model: "missing"})
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), errCodeInvalidRequest) {
+		t.Fatalf("unknown model: got %v %s", w.Code, w.Body)
+	}
+}
+
+func TestStreamRunningOnlyAfterConnect(t *testing.T) {
+	chdirTemp(t)
+	streamTestModel(t)
+	// takes a moment to connect, then writes the first segment and keeps
+	// capturing until the session is stopped
+	fakeCommand(t, "ffmpeg", `for last; do :; done
+sleep 1
+echo segment > "$(dirname "$last")/$(date +%Y%m%d%H%M%S).mp4"
+exec sleep 30`)
+
+	w, status := startTestStream(t, streamConfig{Source: "rtsp://camera/stream", Model: "m"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got %v %s", w.Code, w.Body)
+	}
+	if status.Status != streamStatusConnecting {
+		t.Fatalf("status = %q before ffmpeg connected", status.Status)
+	}
+	if !strings.HasSuffix(status.Config.Model, "m.pt") {
+		t.Errorf("model = %q, want the resolved weights path", status.Config.Model)
+	}
+
+	waitForStream(t, status.ID, func(s streamStatus) bool { return s.Status == streamStatusRunning })
+	if stopped := stopTestStream(t, status.ID); stopped.Status != streamStatusStopped {
+		t.Fatalf("status = %q after stop", stopped.Status)
+	}
+}
+
+func TestStreamReconnects(t *testing.T) {
+	chdirTemp(t)
+	streamTestModel(t)
+	fakeCommand(t, "ffmpeg", `echo "Connection refused"; exit 1`)
+
+	_, status := startTestStream(t, streamConfig{Source: "rtsp://camera/stream", Model: "m"})
+	status = waitForStream(t, status.ID, func(s streamStatus) bool { return s.Reconnects > 0 })
+	if status.Status == streamStatusRunning {
+		t.Errorf("status = %q for a stream that never connected", status.Status)
+	}
+	if !strings.Contains(status.LastError, "ffmpeg") {
+		t.Errorf("last error = %q", status.LastError)
+	}
+}
+
+// TestStreamFromLocalFFmpeg attaches to an MJPEG stream served by ffmpeg
+// itself, as described on streamConfig.
+func TestStreamFromLocalFFmpeg(t *testing.T) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		t.Skip("ffmpeg is not installed")
+	}
+	chdirTemp(t)
+	streamTestModel(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	server := exec.CommandContext(ctx, "ffmpeg", "-hide_banner", "-loglevel", "error", "-re",
+		"-f", "lavfi", "-i", "testsrc=size=320x240:rate=10",
+		"-f", "mpjpeg", "-listen", "1", fmt.Sprintf("http://%v/", addr))
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		cancel()
+		server.Wait()
+	}()
+
+	_, status := startTestStream(t, streamConfig{Source: fmt.Sprintf("http://%v/", addr), Format: "mpjpeg", Model: "m", SegmentSeconds: 1})
+	waitForStream(t, status.ID, func(s streamStatus) bool { return s.Status == streamStatusRunning })
+	if stopped := stopTestStream(t, status.ID); stopped.Status != streamStatusStopped {
+		t.Fatalf("status = %q after stop", stopped.Status)
+	}
+}
+
+func TestStreamDropsSegmentBacklog(t *testing.T) {
+	chdirTemp(t)
+	s := newStreamSession(streamConfig{})
+	raw := filepath.Join(s.dir(), "raw")
+	if err := os.MkdirAll(raw, 0755); err != nil {
+		t.Fatal(err)
+	}
+	segments := []string{}
+	for i := 0; i < 5; i++ {
+		segment := filepath.Join(raw, fmt.Sprintf("%06d.mp4", i))
+		if err := os.WriteFile(segment, []byte("video"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		segments = append(segments, segment)
+	}
+
+	left := s.dropBacklog(segments)
+	if len(left) != maxSegmentBacklog || left[0] != segments[5-maxSegmentBacklog] {
+		t.Fatalf("kept %v, want the newest %v", left, maxSegmentBacklog)
+	}
+	if status := s.snapshot(); status.Dropped != 5-maxSegmentBacklog {
+		t.Errorf("dropped = %v, want %v", status.Dropped, 5-maxSegmentBacklog)
+	}
+	if _, err := os.Stat(segments[0]); !os.IsNotExist(err) {
+		t.Errorf("oldest segment still on disk: %v", err)
+	}
+	if left := s.dropBacklog(left); len(left) != maxSegmentBacklog || s.snapshot().Dropped != 5-maxSegmentBacklog {
+		t.Errorf("a short backlog was trimmed to %v", left)
+	}
+}
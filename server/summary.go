@@ -0,0 +1,362 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	summaryFramesPerClass = 4
+	summaryMinFrameGap    = 1.0
+	summaryThumbWidth     = 320
+	timelineGap           = 0.5
+)
+
+// interval is a span of a video, in seconds.
+type interval struct {
+	Start         float64 `json:"start"`
+	End           float64 `json:"end"`
+	Count         int     `json:"count"`
+	MaxConfidence float64 `json:"max_confidence"`
+}
+
+// classTimeline lists when a class was on screen.
+type classTimeline struct {
+	Class     string     `json:"class"`
+	Intervals []interval `json:"intervals"`
+}
+
+// summaryFrame is one of the best frames of a class.
+type summaryFrame struct {
+	Class      string  `json:"class"`
+	Frame      int     `json:"frame"`
+	Time       float64 `json:"time"`
+	Confidence float64 `json:"confidence"`
+}
+
+// videoSummary is what a reviewer needs to find where the model fired.
+type videoSummary struct {
+	Duration  float64         `json:"duration"`
+	Timeline  []classTimeline `json:"timeline"`
+	TopFrames []summaryFrame  `json:"top_frames"`
+
+	TimelineURL     string `json:"timeline_url,omitempty"`
+	ContactSheetURL string `json:"contact_sheet_url,omitempty"`
+}
+
+// mergeIntervals turns sorted detection times into intervals, joining times
+// less than gap seconds apart.
+func mergeIntervals(detections []detection, gap float64) []interval {
+	sorted := append([]detection{}, detections...)
+	sort.Slice(sorted, func(a, b int) bool {
+		return sorted[a].Time < sorted[b].Time
+	})
+
+	intervals := []interval{}
+	for _, d := range sorted {
+		last := len(intervals) - 1
+		if last >= 0 && d.Time-intervals[last].End <= gap {
+			intervals[last].End = d.Time
+			intervals[last].Count++
+			intervals[last].MaxConfidence = max(intervals[last].MaxConfidence, d.Confidence)
+			continue
+		}
+		intervals = append(intervals, interval{Start: d.Time, End: d.Time, Count: 1, MaxConfidence: d.Confidence})
+	}
+	return intervals
+}
+
+func detectionsByClass(detections []detection) ([]string, map[string][]detection) {
+	classes := []string{}
+	byClass := map[string][]detection{}
+	for _, d := range detections {
+		if _, ok := byClass[d.Class]; !ok {
+			classes = append(classes, d.Class)
+		}
+		byClass[d.Class] = append(byClass[d.Class], d)
+	}
+	sort.Strings(classes)
+	return classes, byClass
+}
+
+// topFrames picks the highest confidence frames of a class at least
+// summaryMinFrameGap seconds apart.
+func topFrames(class string, detections []detection) []summaryFrame {
+	sorted := append([]detection{}, detections...)
+	sort.Slice(sorted, func(a, b int) bool {
+		return sorted[a].Confidence > sorted[b].Confidence
+	})
+
+	frames := []summaryFrame{}
+	for _, d := range sorted {
+		tooClose := false
+		for _, f := range frames {
+			if f.Time-d.Time < summaryMinFrameGap && d.Time-f.Time < summaryMinFrameGap {
+				tooClose = true
+				break
+			}
+		}
+		if tooClose {
+			continue
+		}
+		frames = append(frames, summaryFrame{Class: class, Frame: d.Frame, Time: d.Time, Confidence: d.Confidence})
+		if len(frames) == summaryFramesPerClass {
+			break
+		}
+	}
+	sort.Slice(frames, func(a, b int) bool {
+		return frames[a].Time < frames[b].Time
+	})
+	return frames
+}
+
+// summarize writes the contact sheet and timeline of a video job into its
+// summary dir and lists them as artifacts.
+func (j *job) summarize(ctx context.Context) error {
+	if !j.Media.IsVideo || len(j.Detections) == 0 {
+		return nil
+	}
+	dir := filepath.Join(j.dir(), "summary")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating summary dir: %v", err)
+	}
+
+	summary := &videoSummary{Duration: j.duration()}
+	classes, byClass := detectionsByClass(j.Detections)
+	for _, class := range classes {
+		summary.Timeline = append(summary.Timeline, classTimeline{Class: class, Intervals: mergeIntervals(byClass[class], timelineGap)})
+		summary.TopFrames = append(summary.TopFrames, topFrames(class, byClass[class])...)
+	}
+	for _, t := range summary.Timeline {
+		summary.Duration = max(summary.Duration, t.Intervals[len(t.Intervals)-1].End)
+	}
+	j.Summary = summary
+
+	if err := os.WriteFile(filepath.Join(dir, "timeline.svg"), []byte(timelineSVG(summary)), 0644); err != nil {
+		return fmt.Errorf("error writing timeline: %v", err)
+	}
+	j.addArtifact("timeline.svg", j.fileURL("summary/timeline.svg"))
+	summary.TimelineURL = j.fileURL("summary/timeline.svg")
+	if err := writePNG(filepath.Join(dir, "timeline.png"), timelinePNG(summary)); err != nil {
+		return fmt.Errorf("error writing timeline: %v", err)
+	}
+	j.addArtifact("timeline.png", j.fileURL("summary/timeline.png"))
+
+	if err := j.writeContactSheet(ctx, filepath.Join(dir, "contact_sheet.jpg")); err != nil {
+		return err
+	}
+	j.addArtifact("contact_sheet.jpg", j.fileURL("summary/contact_sheet.jpg"))
+	summary.ContactSheetURL = j.fileURL("summary/contact_sheet.jpg")
+
+	// written last so it carries the urls of everything above
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "timeline.json"), data, 0644); err != nil {
+		return fmt.Errorf("error writing timeline: %v", err)
+	}
+	j.addArtifact("timeline.json", j.fileURL("summary/timeline.json"))
+	return nil
+}
+
+var timelineColors = []string{"#0d6efd", "#dc3545", "#198754", "#ffc107", "#6f42c1", "#fd7e14", "#20c997", "#d63384"}
+
+// timeline layout shared by the SVG and PNG renderings.
+const (
+	timelineLabelWidth = 120.0
+	timelinePlotWidth  = 800.0
+	timelineRowHeight  = 28.0
+	timelineAxisHeight = 24.0
+)
+
+// timelineSVG draws one row per class with a bar for every interval.
+func timelineSVG(summary *videoSummary) string {
+	duration := max(summary.Duration, 1)
+	height := timelineRowHeight*float64(len(summary.Timeline)) + timelineAxisHeight
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" font-family="monospace" font-size="12">`+"\n", timelineLabelWidth+timelinePlotWidth+10, height)
+	for i, t := range summary.Timeline {
+		y := float64(i) * timelineRowHeight
+		fmt.Fprintf(&b, `<text x="4" y="%.1f" fill="#888">%v</text>`+"\n", y+timelineRowHeight/2+4, template.HTMLEscapeString(t.Class))
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#222"/>`+"\n", timelineLabelWidth, y+4, timelinePlotWidth, timelineRowHeight-8)
+		for _, iv := range t.Intervals {
+			x := timelineLabelWidth + iv.Start/duration*timelinePlotWidth
+			w := max((iv.End-iv.Start)/duration*timelinePlotWidth, 2)
+			fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%v"><title>%.1fs - %.1fs (%v)</title></rect>`+"\n",
+				x, y+4, w, timelineRowHeight-8, timelineColors[i%len(timelineColors)], iv.Start, iv.End, iv.Count)
+		}
+	}
+	axisY := timelineRowHeight * float64(len(summary.Timeline))
+	step := timelineStep(duration)
+	for s := 0.0; s <= duration; s += step {
+		x := timelineLabelWidth + s/duration*timelinePlotWidth
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#888"/>`+"\n", x, axisY, x, axisY+4)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" fill="#888" text-anchor="middle">%v</text>`+"\n", x, axisY+16, formatTimestamp(s))
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// timelinePNG draws the same chart as timelineSVG on a white background, for
+// viewers that don't render SVG.
+func timelinePNG(summary *videoSummary) image.Image {
+	duration := max(summary.Duration, 1)
+	width := int(timelineLabelWidth + timelinePlotWidth + 10)
+	height := int(timelineRowHeight*float64(len(summary.Timeline)) + timelineAxisHeight)
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	gray := color.RGBA{0x88, 0x88, 0x88, 0xff}
+	fill := func(x, y, w, h float64, c color.Color) {
+		draw.Draw(img, image.Rect(int(x), int(y), int(x+w+0.5), int(y+h)), image.NewUniform(c), image.Point{}, draw.Src)
+	}
+	text := func(x, y float64, s string) {
+		d := font.Drawer{Dst: img, Src: image.NewUniform(gray), Face: basicfont.Face7x13, Dot: fixed.P(int(x), int(y))}
+		d.DrawString(s)
+	}
+
+	for i, t := range summary.Timeline {
+		y := float64(i) * timelineRowHeight
+		text(4, y+timelineRowHeight/2+4, t.Class)
+		fill(timelineLabelWidth, y+4, timelinePlotWidth, timelineRowHeight-8, color.RGBA{0x22, 0x22, 0x22, 0xff})
+		c := hexColor(timelineColors[i%len(timelineColors)])
+		for _, iv := range t.Intervals {
+			x := timelineLabelWidth + iv.Start/duration*timelinePlotWidth
+			w := max((iv.End-iv.Start)/duration*timelinePlotWidth, 2)
+			fill(x, y+4, w, timelineRowHeight-8, c)
+		}
+	}
+	axisY := timelineRowHeight * float64(len(summary.Timeline))
+	step := timelineStep(duration)
+	for s := 0.0; s <= duration; s += step {
+		x := timelineLabelWidth + s/duration*timelinePlotWidth
+		fill(x, axisY, 1, 4, gray)
+		label := formatTimestamp(s)
+		labelWidth := float64(len(label)) * 7
+		text(min(x-labelWidth/2, float64(width)-labelWidth), axisY+16, label)
+	}
+	return img
+}
+
+// hexColor parses a #rrggbb color.
+func hexColor(hex string) color.RGBA {
+	c := color.RGBA{A: 0xff}
+	fmt.Sscanf(hex, "#%02x%02x%02x", &c.R, &c.G, &c.B)
+	return c
+}
+
+func writePNG(path string, img image.Image) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(out, img); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// timelineStep picks an axis step giving roughly ten ticks.
+func timelineStep(duration float64) float64 {
+	for _, step := range []float64{1, 5, 10, 30, 60, 120, 300, 600} {
+		if duration/step <= 10 {
+			return step
+		}
+	}
+	return 1200
+}
+
+func formatTimestamp(seconds float64) string {
+	s := int(seconds)
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// writeContactSheet tiles the top frames of every class, one row per class.
+func (j *job) writeContactSheet(ctx context.Context, path string) error {
+	width := summaryThumbWidth
+	height := summaryThumbWidth * 9 / 16
+	if j.Media.Width > 0 && j.Media.Height > 0 {
+		height = summaryThumbWidth * j.Media.Height / j.Media.Width
+	}
+
+	rows := map[string][]summaryFrame{}
+	classes := []string{}
+	for _, f := range j.Summary.TopFrames {
+		if _, ok := rows[f.Class]; !ok {
+			classes = append(classes, f.Class)
+		}
+		rows[f.Class] = append(rows[f.Class], f)
+	}
+
+	sheet := image.NewRGBA(image.Rect(0, 0, width*summaryFramesPerClass, height*len(classes)))
+	draw.Draw(sheet, sheet.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	tmp := filepath.Join(filepath.Dir(path), "frame.jpg")
+	defer os.Remove(tmp)
+	for row, class := range classes {
+		for col, f := range rows[class] {
+			if err := extractVideoFrame(ctx, j.OutputPath, f.Time, tmp); err != nil {
+				return err
+			}
+			frame, err := decodeImage(tmp)
+			if err != nil {
+				return err
+			}
+			tile := image.Rect(col*width, row*height, (col+1)*width, (row+1)*height)
+			draw.CatmullRom.Scale(sheet, tile, frame, frame.Bounds(), draw.Src, nil)
+			drawLabel(sheet, tile, fmt.Sprintf("%v %v %.2f", class, formatTimestamp(f.Time), f.Confidence))
+		}
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating contact sheet: %v", err)
+	}
+	defer out.Close()
+	return jpeg.Encode(out, sheet, &jpeg.Options{Quality: 85})
+}
+
+// extractVideoFrame saves the frame of a video at the given time.
+func extractVideoFrame(ctx context.Context, videoPath string, at float64, destPath string) error {
+	args := []string{"-y", "-ss", fmt.Sprintf("%.3f", at), "-i", videoPath, "-frames:v", "1", "-q:v", "2", destPath}
+	return executeCommand(ctx, "ffmpeg", ".", args)
+}
+
+func decodeImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	return img, err
+}
+
+// drawLabel writes text on a dark bar at the bottom of a tile.
+func drawLabel(dst draw.Image, tile image.Rectangle, text string) {
+	bar := image.Rect(tile.Min.X, tile.Max.Y-18, tile.Max.X, tile.Max.Y)
+	draw.Draw(dst, bar, image.NewUniform(color.RGBA{A: 180}), image.Point{}, draw.Over)
+	d := font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(color.White),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(bar.Min.X+4, bar.Max.Y-5),
+	}
+	d.DrawString(text)
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultServiceName = "detection-server"
+	requestIDHeader    = "X-Request-ID"
+)
+
+var tracer = otel.Tracer("github.com/arkusnexus/ai-demo/server")
+
+// setupTracing installs the trace exporter picked by OTEL_TRACES_EXPORTER:
+// "otlp" sends spans over OTLP/HTTP to OTEL_EXPORTER_OTLP_ENDPOINT, "console"
+// prints them to stdout and "none" drops them. It defaults to otlp when an
+// endpoint is set and none otherwise. The returned func flushes the spans
+// left when the server stops.
+func setupTracing(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporterName := os.Getenv("OTEL_TRACES_EXPORTER")
+	if exporterName == "" {
+		exporterName = "none"
+		if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" {
+			exporterName = "otlp"
+		}
+	}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch exporterName {
+	case "none":
+		return func(context.Context) error { return nil }, nil
+	case "otlp":
+		exporter, err = otlptracehttp.New(ctx)
+	case "console":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown OTEL_TRACES_EXPORTER %q", exporterName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating %v exporter: %v", exporterName, err)
+	}
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("error creating trace resource: %v", err)
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	logger.Infof("exporting traces with %v", exporterName)
+	return provider.Shutdown, nil
+}
+
+// statusRecorder keeps the status written by a handler. It passes Flush and
+// Hijack through so SSE and websockets keep working.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("hijacking not supported")
+	}
+	r.status = http.StatusSwitchingProtocols
+	return h.Hijack()
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// requestMiddleware gives every request an id, taken from X-Request-ID when
+// the client sent one, a span continuing the caller's trace and a log line
+// when it's done.
+func requestMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		requestID := r.Header.Get(requestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			requestID = newJobID()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+
+		route := r.URL.Path
+		if m := mux.CurrentRoute(r); m != nil {
+			if tmpl, err := m.GetPathTemplate(); err == nil {
+				route = tmpl
+			}
+		}
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(r.URL.Path),
+				attribute.String("request.id", requestID),
+			))
+		defer span.End()
+		ctx = withLogAttrs(ctx, "request_id", requestID)
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		span.SetAttributes(semconv.HTTPResponseStatusCode(rec.status))
+		if rec.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+		logger.ctx(ctx).Info("request",
+			"method", r.Method,
+			"route", route,
+			"status", rec.status,
+			"duration_ms", time.Since(start).Milliseconds())
+	})
+}
+
+// startSpan starts a span of the job pipeline.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on span, with its error code when it has one, and
+// ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		var je *jobError
+		if errors.As(err, &je) {
+			span.SetAttributes(attribute.String("error.code", je.Code))
+		}
+	}
+	span.End()
+}
+
+// linkContext returns ctx carrying the trace and log attributes of from, so
+// a job outlives the request that started it but stays in its trace.
+func linkContext(ctx, from context.Context) context.Context {
+	ctx = trace.ContextWithSpanContext(ctx, trace.SpanContextFromContext(from))
+	if attrs, ok := from.Value(logAttrsKey{}).([]slog.Attr); ok {
+		ctx = context.WithValue(ctx, logAttrsKey{}, attrs)
+	}
+	return ctx
+}
+
+// grpcContext does for a gRPC call what requestMiddleware does for a
+// request: an id from the x-request-id metadata or a new one, and a span
+// continuing the caller's trace.
+func grpcContext(ctx context.Context, method string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestID := ""
+	if ids := md.Get(requestIDHeader); len(ids) > 0 && len(ids[0]) <= 64 {
+		requestID = ids[0]
+	}
+	if requestID == "" {
+		requestID = newJobID()
+	}
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	ctx, span := tracer.Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.RPCSystemGRPC,
+			attribute.String("rpc.method", method),
+			attribute.String("request.id", requestID),
+		))
+	return withLogAttrs(ctx, "request_id", requestID), span
+}
+
+func grpcUnaryTelemetry(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	ctx, span := grpcContext(ctx, info.FullMethod)
+	resp, err := handler(ctx, req)
+	endGRPCSpan(ctx, span, info.FullMethod, start, err)
+	return resp, err
+}
+
+func grpcStreamTelemetry(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	ctx, span := grpcContext(ss.Context(), info.FullMethod)
+	err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	endGRPCSpan(ctx, span, info.FullMethod, start, err)
+	return err
+}
+
+func endGRPCSpan(ctx context.Context, span trace.Span, method string, start time.Time, err error) {
+	code := status.Code(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+	logger.ctx(ctx).Info("rpc",
+		"method", method,
+		"code", code.String(),
+		"duration_ms", time.Since(start).Milliseconds())
+}
+
+// contextStream hands the handler a stream with the telemetry context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// metadataCarrier reads trace headers from gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var (
+	testSpansOnce sync.Once
+	testSpans     *tracetest.InMemoryExporter
+)
+
+// recordSpans installs a tracer provider that keeps spans in memory. The
+// global tracer only binds to the first provider set, so it's shared by the
+// tests and reset for each.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	testSpansOnce.Do(func() {
+		testSpans = tracetest.NewInMemoryExporter()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(testSpans)))
+	})
+	testSpans.Reset()
+	return testSpans
+}
+
+// syncBuffer is a bytes.Buffer safe for the logger's goroutines.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// captureLogs sends the JSON log records to the returned buffer.
+func captureLogs(t *testing.T) *syncBuffer {
+	t.Helper()
+	buf := &syncBuffer{}
+	saved := logger
+	logger = newStructuredLogger(buf, "", "")
+	t.Cleanup(func() { logger = saved })
+	return buf
+}
+
+// fakePipeline stands in for yolo, ffprobe and ffmpeg: yolo saves a one
+// frame video, ffprobe describes it and ffmpeg writes its last argument.
+func fakePipeline(t *testing.T) {
+	t.Helper()
+	saved := ultralyticsDir
+	ultralyticsDir = t.TempDir()
+	t.Cleanup(func() { ultralyticsDir = saved })
+	fakeCommand(t, "yolo", `for arg; do
+  case "$arg" in
+    project=*) project=$(echo "${arg#project=}" | tr -d "'") ;;
+    name=*) name=$(echo "${arg#name=}" | tr -d "'") ;;
+  esac
+done
+mkdir -p "$project/$name/labels"
+echo "video 1/1 (frame 1/1) rtsp://camera/stream: 640x480 1 person, 5.0ms"
+echo video > "$project/$name/stream.mp4"`)
+	fakeCommand(t, "ffprobe", `echo '{"streams":[{"width":640,"height":480,"r_frame_rate":"10/1","nb_frames":"1"}]}'`)
+	fakeCommand(t, "ffmpeg", `for last; do :; done
+case "$last" in
+  -encoders) echo " V....D libx264 H.264"; exit 0 ;;
+esac
+echo video > "$last"
+echo progress=end`)
+	resetEncoders(t)
+}
+
+// resetEncoders makes the next transcode probe ffmpeg again.
+func resetEncoders(t *testing.T) {
+	t.Helper()
+	encodersMu.Lock()
+	encoders = nil
+	encodersMu.Unlock()
+	t.Cleanup(func() {
+		encodersMu.Lock()
+		encoders = nil
+		encodersMu.Unlock()
+	})
+}
+
+func TestDetectJobSpansAndLogs(t *testing.T) {
+	chdirTemp(t)
+	streamTestModel(t)
+	fakePipeline(t)
+	spans := recordSpans(t)
+	logs := captureLogs(t)
+
+	router := mux.NewRouter()
+	router.Use(requestMiddleware)
+	router.HandleFunc("/detect", detectHandler)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	dialer := websocket.Dialer{Subprotocols: []string{wsProtocolJSON}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/detect", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = conn.WriteJSON(wsRequest{Type: wsRequestSubmit, Job: &detectRequest{Source: "rtsp://camera/stream", Model: "m"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobID := ""
+	conn.SetReadDeadline(time.Now().Add(20 * time.Second))
+	for {
+		env := wsEnvelope{}
+		if err := conn.ReadJSON(&env); err != nil {
+			t.Fatal(err)
+		}
+		if env.Type == feedEventError {
+			t.Fatalf("job failed: %+v", env.Payload)
+		}
+		if env.Type == feedEventDone {
+			jobID = env.JobID
+			break
+		}
+	}
+	conn.Close()
+
+	// the request span ends when the handler sees the connection close
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	deadline := time.Now().Add(5 * time.Second)
+	for byName["GET /detect"] == nil && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+		for _, s := range spans.GetSpans().Snapshots() {
+			byName[s.Name()] = s
+		}
+	}
+	for child, parent := range map[string]string{
+		"websocket.upgrade": "GET /detect",
+		"detect job":        "GET /detect",
+		"inference":         "detect job",
+		"download":          "inference",
+		"video discovery":   "detect job",
+		"transcode":         "detect job",
+	} {
+		c, p := byName[child], byName[parent]
+		if c == nil || p == nil {
+			t.Errorf("missing span %q or %q", child, parent)
+			continue
+		}
+		if c.Parent().SpanID() != p.SpanContext().SpanID() || c.SpanContext().TraceID() != p.SpanContext().TraceID() {
+			t.Errorf("span %q is not a child of %q", child, parent)
+		}
+	}
+
+	// yolo's output is logged with the context of the job
+	wantRequestID := ""
+	for _, a := range byName["GET /detect"].Attributes() {
+		if a.Key == "request.id" {
+			wantRequestID = a.Value.AsString()
+		}
+	}
+	found := false
+	in := bufio.NewScanner(strings.NewReader(logs.String()))
+	for in.Scan() {
+		record := map[string]any{}
+		if json.Unmarshal(in.Bytes(), &record) != nil || !strings.HasPrefix(record["msg"].(string), "video 1/1") {
+			continue
+		}
+		found = true
+		if record["job_id"] != jobID || record["request_id"] != wantRequestID {
+			t.Errorf("yolo line has job_id %v and request_id %v, want %v and %v", record["job_id"], record["request_id"], jobID, wantRequestID)
+		}
+		if record["trace_id"] != byName["inference"].SpanContext().TraceID().String() {
+			t.Errorf("yolo line has trace_id %v", record["trace_id"])
+		}
+	}
+	if !found {
+		t.Errorf("yolo output not logged:\n%v", logs)
+	}
+}
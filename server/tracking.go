@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	predictModeDetect = "detect"
+	predictModeTrack  = "track"
+
+	defaultTracker = "bytetrack.yaml"
+)
+
+// trackers are the tracker configs shipped with ultralytics.
+var trackers = map[string]bool{
+	"bytetrack.yaml": true,
+	"botsort.yaml":   true,
+}
+
+// trackPoint is the box center of a track in a frame.
+type trackPoint struct {
+	Frame int     `json:"frame"`
+	Time  float64 `json:"time"`
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+}
+
+// track is a single object followed across frames by yolo track.
+type track struct {
+	ID             int          `json:"id"`
+	Class          string       `json:"class"`
+	ClassID        int          `json:"class_id"`
+	FirstFrame     int          `json:"first_frame"`
+	LastFrame      int          `json:"last_frame"`
+	FirstSeen      float64      `json:"first_seen"`
+	LastSeen       float64      `json:"last_seen"`
+	Detections     int          `json:"detections"`
+	MeanConfidence float64      `json:"mean_confidence"`
+	Trajectory     []trackPoint `json:"trajectory"`
+}
+
+// parsePredictMode validates the mode and tracker asked for by a client.
+func parsePredictMode(mode, tracker string) (string, string, error) {
+	switch mode {
+	case "", predictModeDetect:
+		return predictModeDetect, "", nil
+	case predictModeTrack:
+		if tracker == "" {
+			tracker = defaultTracker
+		}
+		if !trackers[tracker] {
+			return "", "", fmt.Errorf("unknown tracker: %q", tracker)
+		}
+		return predictModeTrack, tracker, nil
+	}
+	return "", "", fmt.Errorf("unknown mode: %q", mode)
+}
+
+// buildTracks groups tracked detections by track id. A track takes the class
+// it was detected as most often.
+func buildTracks(detections []detection) []track {
+	byID := map[int]*track{}
+	classVotes := map[int]map[int]int{}
+	classNames := map[int]string{}
+	for _, d := range detections {
+		if d.TrackID == 0 {
+			continue
+		}
+		t, ok := byID[d.TrackID]
+		if !ok {
+			t = &track{ID: d.TrackID, FirstFrame: d.Frame, FirstSeen: d.Time}
+			byID[d.TrackID] = t
+			classVotes[d.TrackID] = map[int]int{}
+		}
+		if d.Frame < t.FirstFrame {
+			t.FirstFrame, t.FirstSeen = d.Frame, d.Time
+		}
+		if d.Frame >= t.LastFrame {
+			t.LastFrame, t.LastSeen = d.Frame, d.Time
+		}
+		t.Detections++
+		t.MeanConfidence += d.Confidence
+		t.Trajectory = append(t.Trajectory, trackPoint{Frame: d.Frame, Time: d.Time, X: d.X, Y: d.Y})
+		classVotes[d.TrackID][d.ClassID]++
+		classNames[d.ClassID] = d.Class
+	}
+
+	tracks := []track{}
+	for id, t := range byID {
+		best := -1
+		for classID, votes := range classVotes[id] {
+			if best < 0 || votes > classVotes[id][best] || (votes == classVotes[id][best] && classID < best) {
+				best = classID
+			}
+		}
+		t.ClassID, t.Class = best, classNames[best]
+		t.MeanConfidence /= float64(t.Detections)
+		sort.Slice(t.Trajectory, func(a, b int) bool {
+			return t.Trajectory[a].Frame < t.Trajectory[b].Frame
+		})
+		tracks = append(tracks, *t)
+	}
+	sort.Slice(tracks, func(a, b int) bool {
+		return tracks[a].ID < tracks[b].ID
+	})
+	return tracks
+}
+
+// classCounts counts unique tracks per class when tracks are available and
+// per-frame detections otherwise.
+func classCounts(detections []detection, tracks []track) map[string]int {
+	counts := map[string]int{}
+	if len(tracks) > 0 {
+		for _, t := range tracks {
+			counts[t.Class]++
+		}
+		return counts
+	}
+	for _, d := range detections {
+		counts[d.Class]++
+	}
+	return counts
+}
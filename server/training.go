@@ -0,0 +1,937 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	trainingsDir        = "./static/trainings"
+	trainingFileName    = "training.json"
+	trainingLogFileName = "train.log"
+	// trainingRunName is the yolo run folder inside the training dir, it
+	// holds results.csv and weights/.
+	trainingRunName = "run"
+
+	defaultTrainEpochs    = 100
+	defaultTrainImageSize = 640
+	// maxTrainings is how many trainings run at once, they share the GPU.
+	maxTrainings = 1
+	// trainingPollInterval is how often results.csv is read for new epochs.
+	trainingPollInterval = 2 * time.Second
+)
+
+var (
+	modelNamePattern      = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+	hyperparameterPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// reservedHyperparameters are yolo train arguments the server sets itself.
+var reservedHyperparameters = map[string]bool{
+	"data": true, "model": true, "project": true, "name": true,
+	"exist_ok": true, "resume": true, "save_dir": true, "mode": true,
+}
+
+// trainRequest starts a training from a model on a dataset.
+type trainRequest struct {
+	// Dataset is a data.yaml, a folder with one, the id of a frames job or
+	// the name of a dataset yolo knows like coco8.yaml.
+	Dataset string `json:"dataset"`
+	// Model is the weights to start from, a model name from /models or a yolo
+	// model like yolov8n.pt. Empty uses the default model.
+	Model string `json:"model"`
+	// Name is what the trained model is registered as, train-<id> by default.
+	Name      string `json:"name"`
+	Epochs    int    `json:"epochs"`
+	ImageSize int    `json:"image_size"`
+	// Batch is the batch size, 0 uses the yolo default and -1 picks one that
+	// fits the GPU.
+	Batch int `json:"batch"`
+	// Patience stops training after that many epochs without improvement, 0
+	// uses the yolo default.
+	Patience     int     `json:"patience"`
+	LearningRate float64 `json:"learning_rate"`
+	// Device is "cpu", a GPU index or a list like "0,1", empty lets yolo pick.
+	Device string `json:"device"`
+	// Hyperparameters are passed to yolo train as they are, e.g.
+	// {"mosaic": 0.5, "optimizer": "AdamW"}.
+	Hyperparameters map[string]any `json:"hyperparameters,omitempty"`
+}
+
+func (r *trainRequest) setDefaults() {
+	if r.Epochs <= 0 {
+		r.Epochs = defaultTrainEpochs
+	}
+	if r.ImageSize <= 0 {
+		r.ImageSize = defaultTrainImageSize
+	}
+}
+
+func (r *trainRequest) validate() error {
+	if r.Dataset == "" {
+		return newJobError(errCodeInvalidRequest, fmt.Errorf("dataset is required"))
+	}
+	if r.Name != "" && !modelNamePattern.MatchString(r.Name) {
+		return newJobError(errCodeInvalidRequest, fmt.Errorf("invalid model name %q", r.Name))
+	}
+	if r.LearningRate < 0 || r.Patience < 0 || r.Batch < -1 {
+		return newJobError(errCodeInvalidRequest, fmt.Errorf("learning_rate, patience and batch can't be negative"))
+	}
+	for key, value := range r.Hyperparameters {
+		if !hyperparameterPattern.MatchString(key) || reservedHyperparameters[key] {
+			return newJobError(errCodeInvalidRequest, fmt.Errorf("invalid hyperparameter %q", key))
+		}
+		switch v := value.(type) {
+		case string:
+			if strings.ContainsRune(v, '\'') {
+				return newJobError(errCodeInvalidRequest, fmt.Errorf("hyperparameter %q can't contain quotes", key))
+			}
+		case float64, bool:
+		default:
+			return newJobError(errCodeInvalidRequest, fmt.Errorf("hyperparameter %q must be a string, number or boolean", key))
+		}
+	}
+	return nil
+}
+
+// detectionMetrics are the validation metrics of a model on a dataset.
+type detectionMetrics struct {
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	MAP50     float64 `json:"map50"`
+	MAP5095   float64 `json:"map50_95"`
+}
+
+// trainingEpoch is a row of results.csv.
+type trainingEpoch struct {
+	Epoch   int     `json:"epoch"`
+	BoxLoss float64 `json:"box_loss"`
+	ClsLoss float64 `json:"cls_loss"`
+	DFLLoss float64 `json:"dfl_loss"`
+	detectionMetrics
+	// Values has every column of the row, including the validation losses
+	// and learning rates.
+	Values map[string]float64 `json:"values"`
+}
+
+// trainingRun is a training and its outcome, saved as
+// trainingsDir/<id>/training.json next to the yolo run.
+type trainingRun struct {
+	ID      string       `json:"id"`
+	Status  string       `json:"status"`
+	Failure *jobError    `json:"failure,omitempty"`
+	Request trainRequest `json:"request"`
+	// Dataset and BaseModel are the resolved paths of the request.
+	Dataset    string     `json:"dataset"`
+	BaseModel  string     `json:"base_model"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// Resumes counts the times the training was resumed.
+	Resumes int             `json:"resumes"`
+	Epochs  []trainingEpoch `json:"epochs"`
+	// Best is the epoch with the highest mAP50-95, its weights are the ones
+	// registered.
+	Best *trainingEpoch `json:"best,omitempty"`
+	// Trained is set once yolo finished, the weights can be registered
+	// again with POST /trainings/{id}/register when that failed.
+	Trained bool `json:"trained,omitempty"`
+	// Model is the registered model once the training is done.
+	Model      *modelInfo `json:"model,omitempty"`
+	ResultsURL string     `json:"results_url"`
+	LogURL     string     `json:"log_url"`
+}
+
+func trainingDir(id string) string {
+	return filepath.Join(trainingsDir, id)
+}
+
+func (t *trainingRun) runDir() string {
+	return filepath.Join(trainingDir(t.ID), trainingRunName)
+}
+
+func (t *trainingRun) lastWeights() string {
+	return filepath.Join(t.runDir(), "weights", "last.pt")
+}
+
+func (t *trainingRun) bestWeights() string {
+	return filepath.Join(t.runDir(), "weights", "best.pt")
+}
+
+func (t *trainingRun) save() error {
+	if err := os.MkdirAll(trainingDir(t.ID), 0755); err != nil {
+		return fmt.Errorf("error creating training dir: %v", err)
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding training: %v", err)
+	}
+	path := filepath.Join(trainingDir(t.ID), trainingFileName)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+func loadTrainingRun(id string) (*trainingRun, error) {
+	if !jobIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("invalid training id: %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(trainingDir(id), trainingFileName))
+	if err != nil {
+		return nil, err
+	}
+	t := &trainingRun{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("error decoding training %v: %v", id, err)
+	}
+	return t, nil
+}
+
+// trainingSession is a training yolo is running.
+type trainingSession struct {
+	mu     sync.Mutex
+	run    trainingRun
+	cancel context.CancelFunc
+	done   chan struct{}
+	// changed is closed and replaced on every update, watchers wait on it.
+	changed chan struct{}
+}
+
+var (
+	trainingsMu sync.Mutex
+	trainings   = map[string]*trainingSession{}
+)
+
+func (s *trainingSession) snapshot() (trainingRun, chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run := s.run
+	run.Epochs = append([]trainingEpoch{}, s.run.Epochs...)
+	return run, s.changed
+}
+
+// update applies change to the run and wakes up the watchers.
+func (s *trainingSession) update(change func(*trainingRun)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	change(&s.run)
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// findTraining returns the training with its session when it's running. A
+// training saved as running without a session was stopped by a server
+// restart, it is reported as failed so it can be resumed.
+func findTraining(id string) (*trainingRun, *trainingSession, error) {
+	trainingsMu.Lock()
+	s, ok := trainings[id]
+	trainingsMu.Unlock()
+	if ok {
+		run, _ := s.snapshot()
+		return &run, s, nil
+	}
+	run, err := loadTrainingRun(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if run.Status == jobStatusRunning {
+		run.Status = jobStatusFailed
+		run.Failure = newJobError(errCodeInternal, fmt.Errorf("the server stopped during training"))
+	}
+	return run, nil, nil
+}
+
+// resolveDataset turns the dataset of a request into the absolute path of a
+// data.yaml.
+func resolveDataset(dataset string) (string, error) {
+	path := dataset
+	switch {
+	case jobIDPattern.MatchString(dataset):
+		// the dataset a frames job extracted
+		path = filepath.Join(jobDir(dataset), "dataset", "data.yaml")
+	case !strings.ContainsRune(dataset, os.PathSeparator):
+		// a dataset yolo downloads, like coco8.yaml
+		if filepath.Ext(dataset) == ".yaml" {
+			return dataset, nil
+		}
+	}
+	info, err := os.Stat(path)
+	if err == nil && info.IsDir() {
+		path = filepath.Join(path, "data.yaml")
+		_, err = os.Stat(path)
+	}
+	if err != nil {
+		return "", newJobError(errCodeInvalidDataset, err)
+	}
+	return filepath.Abs(path)
+}
+
+// resolveWeights finds the weights of a training or evaluation, registered
+// models first and then weights yolo can load or download.
+func resolveWeights(name string) (string, error) {
+	if m, err := findModel(name); err == nil {
+		return m.Path, nil
+	}
+	if filepath.Ext(name) != ".pt" {
+		return "", newJobError(errCodeInvalidRequest, fmt.Errorf("unknown model %q", name))
+	}
+	if err := checkModel(name); err != nil {
+		return "", err
+	}
+	if strings.ContainsRune(name, os.PathSeparator) {
+		return filepath.Abs(name)
+	}
+	return name, nil
+}
+
+// trainArgs are the yolo arguments of a new training. Resumed trainings only
+// need the last weights, yolo keeps the arguments in them.
+func (t *trainingRun) trainArgs(resume bool) ([]string, error) {
+	if resume {
+		last, err := filepath.Abs(t.lastWeights())
+		if err != nil {
+			return nil, err
+		}
+		return []string{"detect", "train", "resume", fmt.Sprintf("model='%v'", last)}, nil
+	}
+	project, err := filepath.Abs(trainingDir(t.ID))
+	if err != nil {
+		return nil, err
+	}
+	req := t.Request
+	args := []string{
+		"detect",
+		"train",
+		fmt.Sprintf("data='%v'", t.Dataset),
+		fmt.Sprintf("model='%v'", t.BaseModel),
+		fmt.Sprintf("epochs=%v", req.Epochs),
+		fmt.Sprintf("imgsz=%v", req.ImageSize),
+		fmt.Sprintf("project='%v'", project),
+		fmt.Sprintf("name='%v'", trainingRunName),
+		"exist_ok=True",
+	}
+	if req.Batch != 0 {
+		args = append(args, fmt.Sprintf("batch=%v", req.Batch))
+	}
+	if req.Patience > 0 {
+		args = append(args, fmt.Sprintf("patience=%v", req.Patience))
+	}
+	if req.LearningRate > 0 {
+		args = append(args, fmt.Sprintf("lr0=%v", req.LearningRate))
+	}
+	if req.Device != "" {
+		args = append(args, fmt.Sprintf("device='%v'", req.Device))
+	}
+	keys := make([]string, 0, len(req.Hyperparameters))
+	for key := range req.Hyperparameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		switch v := req.Hyperparameters[key].(type) {
+		case string:
+			args = append(args, fmt.Sprintf("%v='%v'", key, v))
+		default:
+			args = append(args, fmt.Sprintf("%v=%v", key, v))
+		}
+	}
+	return args, nil
+}
+
+// startTraining runs yolo train for the run in the background, resuming it
+// from its last weights when resume is set.
+func startTraining(run *trainingRun, resume bool) (*trainingSession, error) {
+	args, err := run.trainArgs(resume)
+	if err != nil {
+		return nil, err
+	}
+	trainingsMu.Lock()
+	if len(trainings) >= maxTrainings {
+		trainingsMu.Unlock()
+		return nil, newJobError(errCodeQuotaExceeded, fmt.Errorf("%v trainings are running", len(trainings)))
+	}
+	if _, ok := trainings[run.ID]; ok {
+		trainingsMu.Unlock()
+		return nil, fmt.Errorf("training %v is running", run.ID)
+	}
+	// the name is checked before the hours of training, not only when the
+	// model is registered
+	if err := checkModelName(run.Request.Name); err != nil {
+		trainingsMu.Unlock()
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &trainingSession{run: *run, cancel: cancel, done: make(chan struct{}), changed: make(chan struct{})}
+	trainings[run.ID] = s
+	trainingsMu.Unlock()
+
+	s.update(func(t *trainingRun) {
+		t.Status = jobStatusRunning
+		t.Failure = nil
+		t.FinishedAt = nil
+		if resume {
+			t.Resumes++
+		}
+	})
+	saved, _ := s.snapshot()
+	if err := saved.save(); err != nil {
+		s.finish(err)
+		return nil, err
+	}
+	go s.train(ctx, args)
+	return s, nil
+}
+
+// train runs yolo, reading the epochs it finished from results.csv while it
+// runs, and registers the best weights when it is done.
+func (s *trainingSession) train(ctx context.Context, args []string) {
+	id := s.run.ID
+	ctx = withLogAttrs(ctx, "training_id", id)
+	ctx, span := startSpan(ctx, "training",
+		attribute.String("training.id", id),
+		attribute.String("training.dataset", s.run.Dataset),
+		attribute.String("training.base_model", s.run.BaseModel),
+		attribute.Int("training.resumes", s.run.Resumes))
+	log := logger.ctx(ctx)
+	log.Info("training started", "dataset", s.run.Dataset, "base_model", s.run.BaseModel)
+
+	logFile, err := os.OpenFile(filepath.Join(trainingDir(id), trainingLogFileName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		endSpan(span, err)
+		s.finish(err)
+		return
+	}
+	defer logFile.Close()
+
+	stop := make(chan struct{})
+	polled := make(chan struct{})
+	go func() {
+		defer close(polled)
+		ticker := time.NewTicker(trainingPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				s.readResults()
+			}
+		}
+	}()
+
+	// the last lines tell why yolo failed
+	tail := []string{}
+	err = executeCommandWithOutputLogs(ctx, func(line string) {
+		io.WriteString(logFile, line+"\n")
+		tail = append(tail, line)
+		if len(tail) > predictErrorLines {
+			tail = tail[1:]
+		}
+	}, "yolo", ultralyticsDir, args)
+	close(stop)
+	<-polled
+	s.readResults()
+	if err != nil {
+		err = classifyDatasetError(ctx, err, tail, errCodeTrainingFailed)
+	} else {
+		s.update(func(t *trainingRun) {
+			t.Trained = true
+		})
+		err = s.register()
+	}
+	endSpan(span, err)
+	s.finish(err)
+}
+
+// readResults loads the epochs yolo wrote so far.
+func (s *trainingSession) readResults() {
+	epochs, err := readTrainingResults(filepath.Join(s.run.runDir(), "results.csv"))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Errorf("error reading results of training %v: %v", s.run.ID, err)
+		}
+		return
+	}
+	s.mu.Lock()
+	changed := len(epochs) != len(s.run.Epochs)
+	s.mu.Unlock()
+	if !changed {
+		return
+	}
+	s.update(func(t *trainingRun) {
+		t.Epochs = epochs
+		t.Best = bestEpoch(epochs)
+	})
+}
+
+// readTrainingResults parses the results.csv yolo writes after every epoch.
+// Its header is padded with spaces and the metrics columns end in (B) for
+// boxes, e.g. "metrics/mAP50-95(B)".
+func readTrainingResults(path string) ([]trainingEpoch, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	epochs := []trainingEpoch{}
+	if len(rows) < 2 {
+		return epochs, nil
+	}
+	header := make([]string, len(rows[0]))
+	for i, name := range rows[0] {
+		header[i] = strings.TrimSpace(name)
+	}
+	for _, row := range rows[1:] {
+		e := trainingEpoch{Values: map[string]float64{}}
+		for i, cell := range row {
+			if i >= len(header) {
+				break
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(cell), 64)
+			if err != nil {
+				continue
+			}
+			e.Values[header[i]] = v
+			switch strings.TrimSuffix(header[i], "(B)") {
+			case "epoch":
+				e.Epoch = int(v)
+			case "train/box_loss":
+				e.BoxLoss = v
+			case "train/cls_loss":
+				e.ClsLoss = v
+			case "train/dfl_loss":
+				e.DFLLoss = v
+			case "metrics/precision":
+				e.Precision = v
+			case "metrics/recall":
+				e.Recall = v
+			case "metrics/mAP50":
+				e.MAP50 = v
+			case "metrics/mAP50-95":
+				e.MAP5095 = v
+			}
+		}
+		epochs = append(epochs, e)
+	}
+	return epochs, nil
+}
+
+// bestEpoch picks the epoch yolo saves as best.pt, the one with the best
+// fitness, which weighs mAP50-95 over mAP50 0.9 to 0.1.
+func bestEpoch(epochs []trainingEpoch) *trainingEpoch {
+	var best *trainingEpoch
+	fitness := func(e *trainingEpoch) float64 { return 0.1*e.MAP50 + 0.9*e.MAP5095 }
+	for i := range epochs {
+		if best == nil || fitness(&epochs[i]) > fitness(best) {
+			best = &epochs[i]
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	e := *best
+	return &e
+}
+
+// datasetErrorPatterns map lines of yolo train and val output to the error
+// they mean.
+var datasetErrorPatterns = []struct {
+	pattern string
+	code    string
+}{
+	{"Dataset '", errCodeInvalidDataset},
+	{"images not found", errCodeInvalidDataset},
+	{"No labels found", errCodeInvalidDataset},
+	{"UnpicklingError", errCodeModelLoadFailed},
+	{"is not a supported model", errCodeModelLoadFailed},
+}
+
+// classifyDatasetError turns a failed yolo train or val into a jobError using
+// the last lines it printed, fallback is the code of errors it doesn't know.
+func classifyDatasetError(ctx context.Context, err error, tail []string, fallback string) *jobError {
+	if ctx.Err() != nil {
+		return asJobError(ctx.Err())
+	}
+	for i := len(tail) - 1; i >= 0; i-- {
+		for _, p := range datasetErrorPatterns {
+			if strings.Contains(tail[i], p.pattern) {
+				return newJobError(p.code, fmt.Errorf("%v: %v", err, strings.TrimSpace(tail[i])))
+			}
+		}
+	}
+	return newJobError(fallback, err)
+}
+
+// checkModelName makes sure a training can register its model under name,
+// that no model and no other running training uses it. trainingsMu is held.
+func checkModelName(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, err := findModel(name); err == nil {
+		return newJobError(errCodeInvalidRequest, fmt.Errorf("model %q already exists", name))
+	}
+	for _, other := range trainings {
+		if run, _ := other.snapshot(); run.Request.Name == name {
+			return newJobError(errCodeInvalidRequest, fmt.Errorf("model %q is being trained by %v", name, run.ID))
+		}
+	}
+	return nil
+}
+
+// modelName is what the trained model is registered as.
+func (t *trainingRun) registeredName() string {
+	if t.Request.Name != "" {
+		return t.Request.Name
+	}
+	return "train-" + t.ID
+}
+
+// registerModel copies the best weights to MODELS_DIR as a new model with the
+// training metrics next to it.
+func (t *trainingRun) registerModel() (modelInfo, error) {
+	card := modelCard{
+		Training:  t.ID,
+		BaseModel: t.BaseModel,
+		Dataset:   t.Dataset,
+		Epochs:    len(t.Epochs),
+		CreatedAt: time.Now(),
+	}
+	if t.Best != nil {
+		card.Metrics = &t.Best.detectionMetrics
+	}
+	m, err := registerModel(t.registeredName(), t.bestWeights(), card)
+	if err != nil {
+		return m, newJobError(errCodeInternal, fmt.Errorf("error registering model: %v", err))
+	}
+	return m, nil
+}
+
+// register registers the model of the session.
+func (s *trainingSession) register() error {
+	run, _ := s.snapshot()
+	m, err := run.registerModel()
+	if err != nil {
+		return err
+	}
+	s.update(func(t *trainingRun) {
+		t.Model = &m
+	})
+	return nil
+}
+
+// finish records the outcome of the training, saves it and forgets the
+// session.
+func (s *trainingSession) finish(err error) {
+	s.update(func(t *trainingRun) {
+		now := time.Now()
+		t.FinishedAt = &now
+		t.Status = jobStatusDone
+		if err != nil {
+			t.Status = jobStatusFailed
+			if errors.Is(err, context.Canceled) {
+				t.Status = jobStatusCancelled
+			}
+			t.Failure = asJobError(err)
+		}
+	})
+	run, _ := s.snapshot()
+	if saveErr := run.save(); saveErr != nil {
+		logger.Errorf("error saving training %v: %v", run.ID, saveErr)
+	}
+	trainingsMu.Lock()
+	delete(trainings, run.ID)
+	trainingsMu.Unlock()
+	s.cancel()
+	close(s.done)
+
+	errorCode := ""
+	if run.Failure != nil {
+		errorCode = run.Failure.Code
+	}
+	logger.Info("training finished", "training_id", run.ID, "status", run.Status, "epochs", len(run.Epochs), "error_code", errorCode)
+	// wake up watchers so they see the session is gone
+	s.update(func(*trainingRun) {})
+}
+
+// listTrainingRuns returns the saved trainings, newest first.
+func listTrainingRuns() ([]*trainingRun, error) {
+	paths, err := filepath.Glob(filepath.Join(trainingsDir, "*", trainingFileName))
+	if err != nil {
+		return nil, err
+	}
+	runs := []*trainingRun{}
+	for _, path := range paths {
+		run, _, err := findTraining(filepath.Base(filepath.Dir(path)))
+		if err != nil {
+			logger.Errorf("error loading training %v: %v", path, err)
+			continue
+		}
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(a, b int) bool {
+		return runs[a].CreatedAt.After(runs[b].CreatedAt)
+	})
+	return runs, nil
+}
+
+// startTrainingHandler starts a training.
+// POST /trainings
+func startTrainingHandler(w http.ResponseWriter, r *http.Request) {
+	req := trainRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, newJobError(errCodeInvalidRequest, err))
+		return
+	}
+	req.setDefaults()
+	if err := req.validate(); err != nil {
+		writeError(w, err)
+		return
+	}
+	dataset, err := resolveDataset(req.Dataset)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	baseModel, err := resolveWeights(req.Model)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	id := newJobID()
+	run := &trainingRun{
+		ID:         id,
+		Request:    req,
+		Dataset:    dataset,
+		BaseModel:  baseModel,
+		CreatedAt:  time.Now(),
+		Epochs:     []trainingEpoch{},
+		ResultsURL: fmt.Sprintf("/static/trainings/%v/%v/results.csv", id, trainingRunName),
+		LogURL:     fmt.Sprintf("/static/trainings/%v/%v", id, trainingLogFileName),
+	}
+	s, err := startTraining(run, false)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	started, _ := s.snapshot()
+	writeJSON(w, http.StatusAccepted, started)
+}
+
+// listTrainingsHandler returns every training, newest first.
+// GET /trainings
+func listTrainingsHandler(w http.ResponseWriter, r *http.Request) {
+	runs, err := listTrainingRuns()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, runs)
+}
+
+// getTrainingHandler returns a training with the epochs done so far.
+// GET /trainings/{id}
+func getTrainingHandler(w http.ResponseWriter, r *http.Request) {
+	run, _, err := findTraining(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "training not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+// cancelTrainingHandler stops a running training, it can be resumed later.
+// POST /trainings/{id}/cancel
+func cancelTrainingHandler(w http.ResponseWriter, r *http.Request) {
+	_, s, err := findTraining(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "training not found", http.StatusNotFound)
+		return
+	}
+	if s == nil {
+		http.Error(w, "training is not running", http.StatusConflict)
+		return
+	}
+	s.cancel()
+	<-s.done
+	run, _ := s.snapshot()
+	writeJSON(w, http.StatusOK, run)
+}
+
+// resumeTrainingHandler continues a cancelled or failed training from the
+// weights of its last epoch.
+// POST /trainings/{id}/resume
+func resumeTrainingHandler(w http.ResponseWriter, r *http.Request) {
+	run, s, err := findTraining(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "training not found", http.StatusNotFound)
+		return
+	}
+	if s != nil || run.Status == jobStatusDone {
+		http.Error(w, fmt.Sprintf("training is %v", run.Status), http.StatusConflict)
+		return
+	}
+	if run.Trained {
+		http.Error(w, "training finished, its weights can be registered", http.StatusConflict)
+		return
+	}
+	if _, err := os.Stat(run.lastWeights()); err != nil {
+		http.Error(w, "training has no finished epoch to resume from", http.StatusConflict)
+		return
+	}
+	if s, err = startTraining(run, true); err != nil {
+		writeError(w, err)
+		return
+	}
+	resumed, _ := s.snapshot()
+	writeJSON(w, http.StatusAccepted, resumed)
+}
+
+// registerTrainingHandler registers the best weights of a training yolo
+// finished but whose model couldn't be registered, under the name in the
+// body or the one of the request.
+// POST /trainings/{id}/register
+func registerTrainingHandler(w http.ResponseWriter, r *http.Request) {
+	run, s, err := findTraining(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "training not found", http.StatusNotFound)
+		return
+	}
+	if s != nil || !run.Trained || run.Model != nil {
+		http.Error(w, "training has no weights waiting to be registered", http.StatusConflict)
+		return
+	}
+	req := struct {
+		Name string `json:"name"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeError(w, newJobError(errCodeInvalidRequest, err))
+		return
+	}
+	if req.Name != "" {
+		run.Request.Name = req.Name
+	}
+	if err := run.Request.validate(); err != nil {
+		writeError(w, err)
+		return
+	}
+	trainingsMu.Lock()
+	err = checkModelName(run.Request.Name)
+	trainingsMu.Unlock()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	m, err := run.registerModel()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	run.Model, run.Status, run.Failure = &m, jobStatusDone, nil
+	if err := run.save(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+// trainingEventsHandler streams the epochs of a training as server-sent
+// events, "epoch" events with the epoch as the event id and a final "done"
+// event with the training. A reconnecting EventSource resumes after
+// Last-Event-ID.
+// GET /trainings/{id}/events
+func trainingEventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	id := mux.Vars(r)["id"]
+	if _, _, err := findTraining(id); err != nil {
+		http.Error(w, "training not found", http.StatusNotFound)
+		return
+	}
+	// older yolo versions count epochs from 0
+	after := -1
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		after, _ = strconv.Atoi(v)
+	}
+	if v := r.URL.Query().Get("after"); v != "" {
+		after, _ = strconv.Atoi(v)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	send := func(id, event string, v any) error {
+		data, _ := json.Marshal(v)
+		if id != "" {
+			id = "id: " + id + "\n"
+		}
+		if _, err := fmt.Fprintf(w, "%vevent: %v\ndata: %s\n\n", id, event, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+	fmt.Fprintf(w, "retry: %v\n\n", sseRetryDelay.Milliseconds())
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		run, s, err := findTraining(id)
+		if err != nil {
+			return
+		}
+		var changed chan struct{}
+		if s != nil {
+			var snapshot trainingRun
+			snapshot, changed = s.snapshot()
+			run = &snapshot
+		}
+		for _, e := range run.Epochs {
+			if e.Epoch > after {
+				if err := send(strconv.Itoa(e.Epoch), "epoch", e); err != nil {
+					return
+				}
+				after = e.Epoch
+			}
+		}
+		if s == nil {
+			send("", "done", run)
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changed:
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// testResultsCSV is the results.csv of a yolov8 run, padded header and all.
+const testResultsCSV = `                  epoch,         train/box_loss,         train/cls_loss,         train/dfl_loss,   metrics/precision(B),      metrics/recall(B),       metrics/mAP50(B),    metrics/mAP50-95(B),           val/box_loss,                 lr/pg0
+                      1,                 1.2345,                 2.3456,                 1.1111,                   0.51,                   0.42,                   0.45,                   0.25,                  1.333,               0.000333
+                      2,                 1.1234,                 1.9876,                 1.0987,                   0.61,                   0.52,                   0.70,                   0.30,                  1.222,               0.000654
+                      3,                 1.0123,                 1.7654,                 1.0765,                   0.63,                   0.55,                   0.60,                   0.40,                  1.111,                not-a-number
+`
+
+func TestReadTrainingResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.csv")
+	if err := os.WriteFile(path, []byte(testResultsCSV), 0644); err != nil {
+		t.Fatal(err)
+	}
+	epochs, err := readTrainingResults(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(epochs) != 3 {
+		t.Fatalf("read %v epochs, want 3", len(epochs))
+	}
+	e := epochs[0]
+	if e.Epoch != 1 || e.BoxLoss != 1.2345 || e.ClsLoss != 2.3456 || e.DFLLoss != 1.1111 {
+		t.Errorf("losses of epoch 1 = %+v", e)
+	}
+	if e.Precision != 0.51 || e.Recall != 0.42 || e.MAP50 != 0.45 || e.MAP5095 != 0.25 {
+		t.Errorf("metrics of epoch 1 = %+v", e.detectionMetrics)
+	}
+	// every column is kept by its trimmed name, cells that aren't numbers are skipped
+	if e.Values["val/box_loss"] != 1.333 || e.Values["metrics/mAP50-95(B)"] != 0.25 {
+		t.Errorf("values = %v", e.Values)
+	}
+	if _, ok := epochs[2].Values["lr/pg0"]; ok {
+		t.Errorf("unparsable cell kept as %v", epochs[2].Values["lr/pg0"])
+	}
+
+	// epoch 2 has the best mAP50 but fitness weighs mAP50-95 0.9 to 0.1
+	if best := bestEpoch(epochs); best == nil || best.Epoch != 3 {
+		t.Errorf("best epoch = %+v, want 3", best)
+	}
+	if best := bestEpoch(nil); best != nil {
+		t.Errorf("best epoch of no epochs = %+v", best)
+	}
+
+	if err := os.WriteFile(path, []byte(strings.SplitN(testResultsCSV, "\n", 2)[0]+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if epochs, err := readTrainingResults(path); err != nil || len(epochs) != 0 {
+		t.Errorf("header only: %v epochs, %v", len(epochs), err)
+	}
+}
+
+func registerTraining(id, body string) *httptest.ResponseRecorder {
+	r := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/trainings/"+id+"/register", bytes.NewBufferString(body)), map[string]string{"id": id})
+	w := httptest.NewRecorder()
+	registerTrainingHandler(w, r)
+	return w
+}
+
+func TestRegisterFinishedTraining(t *testing.T) {
+	chdirTemp(t)
+	t.Setenv("MODELS_DIR", t.TempDir())
+	if _, err := registerModel("taken", writeTestFile(t, "taken.pt"), modelCard{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// yolo finished but the name was taken by then
+	run := &trainingRun{ID: newJobID(), Status: jobStatusFailed, Request: trainRequest{Dataset: "coco8.yaml", Name: "taken"}, Trained: true}
+	if err := os.MkdirAll(filepath.Dir(run.bestWeights()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(run.bestWeights(), []byte("weights"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if w := registerTraining(run.ID, ""); w.Code != http.StatusBadRequest {
+		t.Fatalf("register under a taken name: %v %v", w.Code, w.Body)
+	}
+	if w := registerTraining(run.ID, `{"name": "street-v2"}`); w.Code != http.StatusOK {
+		t.Fatalf("register: %v %v", w.Code, w.Body)
+	}
+	saved, err := loadTrainingRun(run.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Status != jobStatusDone || saved.Failure != nil || saved.Model == nil || saved.Model.Name != "street-v2" {
+		t.Errorf("training after register = %+v", saved)
+	}
+	if data, err := os.ReadFile(filepath.Join(modelsDir(), "street-v2.pt")); err != nil || string(data) != "weights" {
+		t.Errorf("registered weights = %q, %v", data, err)
+	}
+	if w := registerTraining(run.ID, `{"name": "street-v3"}`); w.Code != http.StatusConflict {
+		t.Errorf("registering twice: %v", w.Code)
+	}
+}
+
+func writeTestFile(t *testing.T, name string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	defaultTranscodeProfile = "h264"
+	renditionsDirName       = "renditions"
+	masterPlaylistName      = "master.m3u8"
+	hlsSegmentSeconds       = 4
+	// renditionCRF is used for renditions of profiles with another codec,
+	// their CRF means something else to libx264.
+	renditionCRF = 26
+)
+
+// transcodeProfile is a named set of ffmpeg output settings.
+type transcodeProfile struct {
+	Name string `json:"name"`
+	// Codec is one of "h264", "hevc", "vp9" or "av1".
+	Codec string `json:"codec"`
+	CRF   int    `json:"crf"`
+	// Height scales the video keeping its aspect ratio, 0 keeps the source size.
+	Height int     `json:"height,omitempty"`
+	FPS    float64 `json:"fps,omitempty"`
+	// Container is "mp4" or "webm".
+	Container string `json:"container"`
+}
+
+// codecMaxCRF is the highest CRF the encoders of a codec accept.
+var codecMaxCRF = map[string]int{
+	"h264": 51,
+	"hevc": 51,
+	"vp9":  63,
+	"av1":  63,
+}
+
+// codecEncoders lists the ffmpeg encoders of a codec, preferred first.
+var codecEncoders = map[string][]string{
+	"h264": {"libx264"},
+	"hevc": {"libx265"},
+	"vp9":  {"libvpx-vp9"},
+	"av1":  {"libsvtav1", "libaom-av1"},
+}
+
+var builtinProfiles = []transcodeProfile{
+	{Name: "h264", Codec: "h264", CRF: 28, Container: "mp4"},
+	{Name: "h264-720p", Codec: "h264", CRF: 26, Height: 720, Container: "mp4"},
+	{Name: "h264-480p", Codec: "h264", CRF: 28, Height: 480, FPS: 15, Container: "mp4"},
+	{Name: "hevc", Codec: "hevc", CRF: 30, Container: "mp4"},
+	{Name: "webm-vp9", Codec: "vp9", CRF: 33, Container: "webm"},
+	{Name: "webm-av1", Codec: "av1", CRF: 35, Container: "webm"},
+	{Name: "av1", Codec: "av1", CRF: 35, Container: "mp4"},
+}
+
+// renditionHeights are the adaptive playback variants, the ones taller than
+// the source are skipped.
+var renditionHeights = []int{1080, 720, 480, 360}
+
+func (p transcodeProfile) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("profile name is required")
+	}
+	if _, ok := codecEncoders[p.Codec]; !ok {
+		return fmt.Errorf("profile %v: unknown codec %q", p.Name, p.Codec)
+	}
+	switch p.Container {
+	case "mp4":
+	case "webm":
+		if p.Codec != "vp9" && p.Codec != "av1" {
+			return fmt.Errorf("profile %v: webm only supports vp9 and av1", p.Name)
+		}
+	default:
+		return fmt.Errorf("profile %v: unknown container %q", p.Name, p.Container)
+	}
+	if p.CRF < 0 || p.CRF > codecMaxCRF[p.Codec] {
+		return fmt.Errorf("profile %v: crf must be between 0 and %v for %v", p.Name, codecMaxCRF[p.Codec], p.Codec)
+	}
+	return nil
+}
+
+// encoderArgs returns the ffmpeg video options of the profile.
+func (p transcodeProfile) encoderArgs() ([]string, error) {
+	encoder, err := availableEncoder(p.Codec)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"-c:v", encoder, "-crf", strconv.Itoa(p.CRF), "-pix_fmt", "yuv420p"}
+	switch encoder {
+	case "libx264":
+		args = append(args, "-profile:v", "high")
+	case "libvpx-vp9", "libaom-av1":
+		// constant quality mode needs the bitrate unset
+		args = append(args, "-b:v", "0")
+	}
+	filters := []string{}
+	if p.Height > 0 {
+		filters = append(filters, fmt.Sprintf("scale=-2:%v", p.Height))
+	}
+	if p.FPS > 0 {
+		filters = append(filters, fmt.Sprintf("fps=%v", p.FPS))
+	}
+	if len(filters) > 0 {
+		args = append(args, "-vf", strings.Join(filters, ","))
+	}
+	if p.Container == "mp4" {
+		args = append(args, "-movflags", "+faststart")
+	}
+	return args, nil
+}
+
+var (
+	encodersMu sync.Mutex
+	// encoders is nil until ffmpeg -encoders succeeds, a failed probe is
+	// retried by the next transcode.
+	encoders map[string]bool
+)
+
+// availableEncoder returns the first encoder of the codec this ffmpeg build
+// ships with.
+func availableEncoder(codec string) (string, error) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	if encoders == nil {
+		out, err := exec.Command("ffmpeg", "-hide_banner", "-encoders").Output()
+		if err != nil {
+			return "", fmt.Errorf("error listing ffmpeg encoders: %v", err)
+		}
+		found := map[string]bool{}
+		for _, line := range strings.Split(string(out), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) >= 2 && strings.HasPrefix(fields[0], "V") {
+				found[fields[1]] = true
+			}
+		}
+		encoders = found
+	}
+	for _, encoder := range codecEncoders[codec] {
+		if encoders[encoder] {
+			return encoder, nil
+		}
+	}
+	return "", fmt.Errorf("no %v encoder available in ffmpeg", codec)
+}
+
+var (
+	profilesOnce sync.Once
+	profiles     map[string]transcodeProfile
+)
+
+// transcodeProfiles returns the builtin profiles plus the ones in the file
+// named by TRANSCODE_PROFILES, which override builtins with the same name.
+func transcodeProfiles() map[string]transcodeProfile {
+	profilesOnce.Do(func() {
+		profiles = map[string]transcodeProfile{}
+		for _, p := range builtinProfiles {
+			profiles[p.Name] = p
+		}
+		path := os.Getenv("TRANSCODE_PROFILES")
+		if path == "" {
+			return
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logger.Errorf("error reading transcode profiles: %v", err)
+			return
+		}
+		custom := []transcodeProfile{}
+		if err := json.Unmarshal(data, &custom); err != nil {
+			logger.Errorf("error decoding transcode profiles: %v", err)
+			return
+		}
+		for _, p := range custom {
+			if err := p.validate(); err != nil {
+				logger.Errorf("skipping transcode profile: %v", err)
+				continue
+			}
+			profiles[p.Name] = p
+		}
+	})
+	return profiles
+}
+
+// findTranscodeProfile looks a profile up by name, empty picks the one set by
+// TRANSCODE_PROFILE or the default.
+func findTranscodeProfile(name string) (transcodeProfile, error) {
+	if name == "" {
+		name = os.Getenv("TRANSCODE_PROFILE")
+	}
+	if name == "" {
+		name = defaultTranscodeProfile
+	}
+	p, ok := transcodeProfiles()[name]
+	if !ok {
+		return transcodeProfile{}, fmt.Errorf("unknown transcode profile %q", name)
+	}
+	return p, nil
+}
+
+// transcode converts the yolo output of the job with the profile and returns
+// the path of the playable file. onProgress gets the percent done.
+func (j *job) transcode(ctx context.Context, p transcodeProfile, onProgress func(float64)) (path string, err error) {
+	ctx, span := startSpan(ctx, "transcode",
+		attribute.String("transcode.profile", p.Name),
+		attribute.String("transcode.codec", p.Codec))
+	defer func() { endSpan(span, err) }()
+	videoArgs, err := p.encoderArgs()
+	if err != nil {
+		return "", err
+	}
+	outPath := filepath.Join(j.dir(), "output."+p.Container)
+	args := append([]string{"-y", "-i", j.OutputPath, "-an"}, videoArgs...)
+	args = append(args, outPath)
+	if err := runFFmpeg(ctx, args, j.duration(), onProgress); err != nil {
+		return "", err
+	}
+	j.addArtifact(filepath.Base(outPath), j.fileURL(filepath.Base(outPath)))
+	return outPath, nil
+}
+
+// transcodeRenditions writes an HLS variant per rendition height plus a
+// master playlist for adaptive playback, and returns the master playlist path.
+func (j *job) transcodeRenditions(ctx context.Context, p transcodeProfile, onProgress func(float64)) (path string, err error) {
+	ctx, span := startSpan(ctx, "transcode renditions", attribute.String("transcode.profile", p.Name))
+	defer func() { endSpan(span, err) }()
+	heights := []int{}
+	for _, h := range renditionHeights {
+		if j.Media.Height == 0 || h <= j.Media.Height {
+			heights = append(heights, h)
+		}
+	}
+	if len(heights) == 0 {
+		heights = []int{j.Media.Height}
+	}
+	// browsers only play mpeg-ts segments reliably with h264
+	if p.Codec != "h264" {
+		p.CRF = renditionCRF
+	}
+	p.Codec, p.Container = "h264", "hls"
+
+	dir := filepath.Join(j.dir(), renditionsDirName)
+	var master strings.Builder
+	master.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for i, h := range heights {
+		rendition := p
+		rendition.Height = h
+		videoArgs, err := rendition.encoderArgs()
+		if err != nil {
+			return "", err
+		}
+		name := fmt.Sprintf("%vp", h)
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			return "", fmt.Errorf("error creating rendition dir: %v", err)
+		}
+		args := append([]string{"-y", "-i", j.OutputPath, "-an"}, videoArgs...)
+		args = append(args, "-f", "hls", "-hls_time", strconv.Itoa(hlsSegmentSeconds), "-hls_playlist_type", "vod",
+			"-hls_segment_filename", filepath.Join(dir, name, "%03d.ts"), filepath.Join(dir, name, "index.m3u8"))
+		err = runFFmpeg(ctx, args, j.duration(), func(percent float64) {
+			onProgress((float64(i)*100 + percent) / float64(len(heights)))
+		})
+		if err != nil {
+			return "", err
+		}
+
+		width := h * 16 / 9
+		if j.Media.Height > 0 {
+			width = (j.Media.Width*h/j.Media.Height + 1) / 2 * 2
+		}
+		fmt.Fprintf(&master, "#EXT-X-STREAM-INF:BANDWIDTH=%v,RESOLUTION=%vx%v\n%v/index.m3u8\n", renditionBandwidth(h), width, h, name)
+	}
+
+	masterPath := filepath.Join(dir, masterPlaylistName)
+	if err := os.WriteFile(masterPath, []byte(master.String()), 0644); err != nil {
+		return "", fmt.Errorf("error writing master playlist: %v", err)
+	}
+	j.addArtifact(masterPlaylistName, j.fileURL(renditionsDirName+"/"+masterPlaylistName))
+	return masterPath, nil
+}
+
+// renditionBandwidth is a rough peak bitrate hint for the master playlist.
+func renditionBandwidth(height int) int {
+	switch {
+	case height >= 1080:
+		return 5000000
+	case height >= 720:
+		return 2800000
+	case height >= 480:
+		return 1400000
+	}
+	return 800000
+}
+
+// runFFmpeg runs ffmpeg reporting progress from its -progress output against
+// the expected duration in seconds.
+func runFFmpeg(ctx context.Context, args []string, duration float64, onProgress func(float64)) error {
+	args = append([]string{"-hide_banner", "-loglevel", "error", "-nostats", "-progress", "pipe:1"}, args...)
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("error reading stdout: %v", err)
+	}
+	// share the stdout pipe so a chatty stderr can't block the process
+	cmd.Stderr = cmd.Stdout
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("error starting command: %v", err)
+	}
+	log := logger.ctx(ctx)
+	in := bufio.NewScanner(stdout)
+	for in.Scan() {
+		key, value, ok := strings.Cut(in.Text(), "=")
+		if !ok {
+			log.Info(in.Text(), "command", "ffmpeg")
+			continue
+		}
+		switch key {
+		case "out_time_ms":
+			// despite its name ffmpeg reports microseconds
+			us, err := strconv.ParseFloat(value, 64)
+			if err == nil && duration > 0 {
+				onProgress(min(us/1e6/duration*100, 100))
+			}
+		case "progress":
+			if value == "end" {
+				onProgress(100)
+			}
+		}
+	}
+
+	if err := in.Err(); err != nil {
+		return fmt.Errorf("error reading stdout: %v", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("error running ffmpeg: %v", err)
+	}
+	return nil
+}
+
+// listTranscodeProfilesHandler lists the profiles a job can pick from.
+func listTranscodeProfilesHandler(w http.ResponseWriter, r *http.Request) {
+	list := []transcodeProfile{}
+	for _, p := range transcodeProfiles() {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(a, b int) bool {
+		return list[a].Name < list[b].Name
+	})
+	writeJSON(w, http.StatusOK, list)
+}
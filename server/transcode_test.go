@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestAvailableEncoderRetriesFailedProbe(t *testing.T) {
+	resetEncoders(t)
+	fakeCommand(t, "ffmpeg", `exit 1`)
+	if _, err := availableEncoder("h264"); err == nil {
+		t.Fatal("expected an error while ffmpeg fails")
+	}
+
+	fakeCommand(t, "ffmpeg", `echo " V....D libx264 H.264"`)
+	if encoder, err := availableEncoder("h264"); err != nil || encoder != "libx264" {
+		t.Fatalf("encoder = %q, %v after ffmpeg recovered", encoder, err)
+	}
+	if _, err := availableEncoder("vp9"); err == nil {
+		t.Error("found a vp9 encoder ffmpeg doesn't list")
+	}
+}
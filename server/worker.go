@@ -0,0 +1,385 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	defaultWorkerAPIURL = "http://localhost:8080"
+	// workerRetryDelay is the wait after the API server could not be reached.
+	workerRetryDelay = 5 * time.Second
+	// maxEventAttempts is how many times events are sent before the job is
+	// given up, waiting eventRetryDelay and doubling it in between.
+	maxEventAttempts = 5
+	eventRetryDelay  = 500 * time.Millisecond
+)
+
+// workerClient talks to the /workers endpoints of the API server.
+type workerClient struct {
+	baseURL string
+	id      string
+	token   string
+	http    *http.Client
+	// transfer moves sources and artifacts, which can take longer than
+	// any API call, so it has no total timeout.
+	transfer *http.Client
+}
+
+// newWorkerClient reads WORKER_API_URL, WORKER_ID, the hostname by default,
+// and WORKER_TOKEN.
+func newWorkerClient() *workerClient {
+	c := &workerClient{
+		baseURL: strings.TrimRight(os.Getenv("WORKER_API_URL"), "/"),
+		id:      os.Getenv("WORKER_ID"),
+		token:   os.Getenv("WORKER_TOKEN"),
+		// leases long poll for up to maxLeaseWait
+		http:     &http.Client{Timeout: maxLeaseWait + 10*time.Second},
+		transfer: &http.Client{},
+	}
+	if c.baseURL == "" {
+		c.baseURL = defaultWorkerAPIURL
+	}
+	if c.id == "" {
+		c.id, _ = os.Hostname()
+		c.id += "-" + newJobID()[:6]
+	}
+	return c
+}
+
+// do sends a request and decodes a JSON response into out when it's not
+// nil. A 409 is errLeaseLost.
+func (c *workerClient) do(ctx context.Context, method, path string, header http.Header, body io.Reader, out any) (int, error) {
+	return c.doWith(ctx, c.http, method, path, header, body, out)
+}
+
+// doWith is do with another http client.
+func (c *workerClient) doWith(ctx context.Context, client *http.Client, method, path string, header http.Header, body io.Reader, out any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set(workerIDHeader, c.id)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusConflict:
+		return resp.StatusCode, errLeaseLost
+	case resp.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode, fmt.Errorf("%v %v: %v %v", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("error decoding %v response: %v", path, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+func (c *workerClient) postJSON(ctx context.Context, path string, in, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	header := http.Header{"Content-Type": {"application/json"}}
+	_, err = c.do(ctx, http.MethodPost, path, header, bytes.NewReader(data), out)
+	return err
+}
+
+// postEvents sends job events to the API server. Network errors and 5xx
+// answers are retried, so a restart of the API server doesn't cancel jobs.
+func (c *workerClient) postEvents(ctx context.Context, base string, events []feedEvent) error {
+	data, err := json.Marshal(workerEvents{Events: events})
+	if err != nil {
+		return err
+	}
+	header := http.Header{"Content-Type": {"application/json"}}
+	delay := eventRetryDelay
+	for attempt := 1; ; attempt++ {
+		status, err := c.do(ctx, http.MethodPost, base+"/events", header, bytes.NewReader(data), nil)
+		transient := status == 0 || status == http.StatusTooManyRequests || status >= 500
+		if err == nil || !transient || attempt == maxEventAttempts {
+			return err
+		}
+		logger.ctx(ctx).Warn("error sending job events, retrying", "attempt", attempt, "error", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// runWorker is the worker mode of the server binary: it leases detect jobs
+// from the API server at WORKER_API_URL, WORKER_CONCURRENCY at a time, runs
+// them and streams their events and files back until ctx is cancelled.
+func runWorker(ctx context.Context) error {
+	c := newWorkerClient()
+	concurrency, _ := strconv.Atoi(os.Getenv("WORKER_CONCURRENCY"))
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	logger.Info("worker is running", "worker_id", c.id, "api_url", c.baseURL, "concurrency", concurrency)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				task := workerTask{}
+				status, err := c.do(ctx, http.MethodPost, "/workers/lease", nil, nil, &task)
+				if err != nil {
+					if ctx.Err() == nil {
+						logger.Errorf("error leasing job: %v", err)
+						sleepContext(ctx, workerRetryDelay)
+					}
+					continue
+				}
+				if status == http.StatusNoContent {
+					continue
+				}
+				c.runTask(ctx, task)
+			}
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// runTask runs a leased job with the same pipeline as local jobs. Events of
+// the local feed are posted to the API server, the job dir is uploaded before
+// the result so its urls work, and the lease is renewed until the job is
+// done. Stopping the worker cancels the job, the API server requeues it when
+// the lease expires.
+func (c *workerClient) runTask(ctx context.Context, task workerTask) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(task.Trace))
+	ctx = withLogAttrs(ctx, "job_id", task.JobID, "worker_id", c.id)
+	ctx, span := startSpan(ctx, "worker job",
+		attribute.String("job.id", task.JobID),
+		attribute.Int("job.attempt", task.Attempt))
+	var spanErr error
+	defer func() { endSpan(span, spanErr) }()
+	log := logger.ctx(ctx)
+	log.Info("running job", "attempt", task.Attempt)
+
+	base := "/workers/jobs/" + task.JobID
+	req := task.Request
+	var download string
+	if task.SourceURL != "" {
+		var err error
+		if download, err = c.downloadSource(ctx, task); err != nil {
+			spanErr = newJobError(errCodeDownloadFailed, err)
+			c.reportFailure(ctx, task.JobID, spanErr)
+			return
+		}
+		defer os.RemoveAll(filepath.Dir(download))
+		req.Source = download
+	}
+
+	j, profile, err := newDetectJob(req)
+	if err != nil {
+		spanErr = err
+		c.reportFailure(ctx, task.JobID, err)
+		return
+	}
+	j.ID = task.JobID
+	f := newJobFeed(j, "")
+
+	stop, lost := make(chan struct{}), make(chan struct{})
+	defer close(stop)
+	go c.heartbeat(ctx, f, base, time.Duration(task.LeaseSeconds)*time.Second/3, stop, lost)
+	go j.process(linkContext(f.ctx, ctx), f, profile, req.Highlights)
+
+	uploaded := false
+	err = f.watch(ctx, 0, func(e feedEvent) error {
+		select {
+		case <-lost:
+			return errLeaseLost
+		default:
+		}
+		switch e.Type {
+		case feedEventQueued:
+			return nil
+		case feedEventResult, feedEventDone:
+			if !uploaded {
+				if err := c.uploadArtifacts(ctx, base, j, download, task.Request.Source); err != nil {
+					return err
+				}
+				uploaded = true
+			}
+		}
+		if e.Type == feedEventDone {
+			return c.postJSON(ctx, base+"/complete", workerCompletion{}, nil)
+		}
+		return c.postEvents(ctx, base, []feedEvent{e})
+	})
+	if err != nil {
+		spanErr = err
+		log.Errorf("error reporting job: %v", err)
+		f.cancel()
+		// wait for the job to stop before leasing another one
+		f.watch(context.Background(), 0, func(feedEvent) error { return nil })
+	}
+}
+
+// heartbeat renews the lease of a job every interval until stop is closed. It
+// cancels the job when the API server says it was cancelled or the lease was
+// lost, closing lost.
+func (c *workerClient) heartbeat(ctx context.Context, f *jobFeed, base string, interval time.Duration, stop, lost chan struct{}) {
+	if interval <= 0 {
+		interval = workerLeaseTTL / 3
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		resp := heartbeatResponse{}
+		err := c.postJSON(ctx, base+"/heartbeat", struct{}{}, &resp)
+		switch {
+		case err == errLeaseLost:
+			logger.ctx(ctx).Warn("lease lost, stopping job")
+			close(lost)
+			f.cancel()
+			return
+		case err != nil:
+			// the lease survives a few missed heartbeats
+			logger.ctx(ctx).Errorf("error sending heartbeat: %v", err)
+		case resp.Cancelled:
+			f.cancel()
+		}
+	}
+}
+
+// downloadSource fetches a source file from the API server into uploadsDir
+// and returns its path.
+func (c *workerClient) downloadSource(ctx context.Context, task workerTask) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+task.SourceURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(workerIDHeader, c.id)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.transfer.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error downloading source: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error downloading source: %v", resp.Status)
+	}
+	return saveUpload(resp.Body, filepath.Base(task.Request.Source))
+}
+
+// uploadArtifacts sends the job dir as a tar stream. job.json is sent with
+// the source the client submitted, not the worker copy.
+func (c *workerClient) uploadArtifacts(ctx context.Context, base string, j *job, download, source string) error {
+	if download != "" {
+		saved, err := loadJob(j.ID)
+		if err != nil {
+			return err
+		}
+		saved.Source = source
+		if err := saved.save(); err != nil {
+			return err
+		}
+	}
+	dir, err := filepath.Abs(j.dir())
+	if err != nil {
+		return err
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeArtifacts(pw, dir))
+	}()
+	header := http.Header{"Content-Type": {"application/x-tar"}, workerDirHeader: {dir}}
+	_, err = c.doWith(ctx, c.transfer, http.MethodPut, base+"/artifacts", header, pr, nil)
+	pr.Close()
+	return err
+}
+
+// writeArtifacts writes the regular files under dir as a tar stream.
+func writeArtifacts(w io.Writer, dir string) error {
+	out := tar.NewWriter(w)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err := out.WriteHeader(header); err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(out, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// reportFailure completes a job that could not start on the worker, the API
+// server fails it.
+func (c *workerClient) reportFailure(ctx context.Context, jobID string, err error) {
+	logger.ctx(ctx).Errorf("error starting job: %v", err)
+	completion := workerCompletion{Failure: asJobError(err)}
+	if err := c.postJSON(ctx, "/workers/jobs/"+jobID+"/complete", completion, nil); err != nil {
+		logger.ctx(ctx).Errorf("error completing job: %v", err)
+	}
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
@@ -0,0 +1,510 @@
+package main
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	dispatchRemote = "remote"
+
+	// workerLeaseTTL is how long a worker keeps a job without a heartbeat,
+	// after that the job goes back to the queue.
+	workerLeaseTTL = 30 * time.Second
+	// maxLeaseWait bounds the long poll of a worker asking for a job.
+	maxLeaseWait = 20 * time.Second
+	// maxJobAttempts is how many workers can die on a job before it fails.
+	maxJobAttempts = 3
+
+	workerIDHeader  = "X-Worker-ID"
+	workerDirHeader = "X-Worker-Job-Dir"
+)
+
+// remoteDispatch reports whether detect jobs are queued for workers, set with
+// JOB_DISPATCH=remote, instead of running in the API server.
+func remoteDispatch() bool {
+	return os.Getenv("JOB_DISPATCH") == dispatchRemote
+}
+
+// workerTask is a queued detect job as handed to a worker.
+type workerTask struct {
+	JobID   string        `json:"job_id"`
+	Request detectRequest `json:"request"`
+	// Attempt counts the workers that leased the job, starting at 1.
+	Attempt int `json:"attempt"`
+	// SourceURL is where the worker downloads a source that is a file on
+	// the API server, like an upload.
+	SourceURL    string            `json:"source_url,omitempty"`
+	LeaseSeconds int               `json:"lease_seconds"`
+	Trace        map[string]string `json:"trace,omitempty"`
+}
+
+// workerLease is a job held by a worker until expires, heartbeats push
+// expires forward.
+type workerLease struct {
+	task    workerTask
+	worker  string
+	expires time.Time
+}
+
+// workQueue holds the jobs waiting for a worker and the ones leased. wake is
+// closed and replaced when a job is queued to end the long polls.
+var workQueue = struct {
+	sync.Mutex
+	pending []workerTask
+	leases  map[string]*workerLease
+	wake    chan struct{}
+}{leases: map[string]*workerLease{}, wake: make(chan struct{})}
+
+var errLeaseLost = errors.New("lease lost")
+
+// enqueueJob queues a validated job for the workers. f stays the feed
+// clients watch, workers post their events to it.
+func enqueueJob(ctx context.Context, f *jobFeed, j *job, req detectRequest) {
+	req.Source = j.Source
+	task := workerTask{JobID: j.ID, Request: req, Trace: map[string]string{}}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(task.Trace))
+	if !strings.Contains(j.Source, "://") {
+		task.SourceURL = fmt.Sprintf("/workers/jobs/%v/source", j.ID)
+	}
+
+	// save right away so the job shows up in /jobs while it waits
+	if err := j.save(); err != nil {
+		logger.ctx(ctx).Errorf("error saving job %v: %v", j.ID, err)
+	}
+	f.publish(feedEventQueued, message{JobID: j.ID, Message: j.Source})
+	pushTask(task, false)
+	go dropCancelledTask(f, j.ID)
+}
+
+// pushTask queues a task, requeued tasks go first.
+func pushTask(task workerTask, front bool) {
+	workQueue.Lock()
+	defer workQueue.Unlock()
+	if front {
+		workQueue.pending = append([]workerTask{task}, workQueue.pending...)
+	} else {
+		workQueue.pending = append(workQueue.pending, task)
+	}
+	close(workQueue.wake)
+	workQueue.wake = make(chan struct{})
+}
+
+// dropCancelledTask finishes a job cancelled, or timed out, before a worker
+// picked it up. Leased jobs are stopped by their worker.
+func dropCancelledTask(f *jobFeed, jobID string) {
+	<-f.ctx.Done()
+	workQueue.Lock()
+	found := false
+	for i, task := range workQueue.pending {
+		if task.JobID == jobID {
+			workQueue.pending = append(workQueue.pending[:i], workQueue.pending[i+1:]...)
+			found = true
+			break
+		}
+	}
+	workQueue.Unlock()
+	if found {
+		failRemoteJob(f, jobID, asJobError(f.ctx.Err()))
+	}
+}
+
+// leaseTask hands the oldest queued job to worker, it waits for one until
+// ctx is done and returns false when there was none.
+func leaseTask(ctx context.Context, worker string) (workerTask, bool) {
+	for {
+		workQueue.Lock()
+		if len(workQueue.pending) > 0 {
+			task := workQueue.pending[0]
+			workQueue.pending = workQueue.pending[1:]
+			task.Attempt++
+			task.LeaseSeconds = int(workerLeaseTTL.Seconds())
+			workQueue.leases[task.JobID] = &workerLease{task: task, worker: worker, expires: time.Now().Add(workerLeaseTTL)}
+			workQueue.Unlock()
+			return task, true
+		}
+		wake := workQueue.wake
+		workQueue.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return workerTask{}, false
+		case <-wake:
+		}
+	}
+}
+
+// renewLease extends the lease worker holds on a job.
+func renewLease(jobID, worker string) error {
+	workQueue.Lock()
+	defer workQueue.Unlock()
+	l, ok := workQueue.leases[jobID]
+	if !ok || l.worker != worker {
+		return errLeaseLost
+	}
+	l.expires = time.Now().Add(workerLeaseTTL)
+	return nil
+}
+
+// checkLease returns an error unless worker holds the lease of a job.
+func checkLease(jobID, worker string) error {
+	workQueue.Lock()
+	defer workQueue.Unlock()
+	if l, ok := workQueue.leases[jobID]; !ok || l.worker != worker {
+		return errLeaseLost
+	}
+	return nil
+}
+
+// releaseLease drops the lease of a finished job.
+func releaseLease(jobID, worker string) error {
+	workQueue.Lock()
+	defer workQueue.Unlock()
+	if l, ok := workQueue.leases[jobID]; !ok || l.worker != worker {
+		return errLeaseLost
+	}
+	delete(workQueue.leases, jobID)
+	return nil
+}
+
+// reapLeases requeues the jobs of workers that stopped sending heartbeats.
+// Cancelled jobs and jobs that already lost maxJobAttempts workers fail.
+func reapLeases(ctx context.Context) {
+	ticker := time.NewTicker(workerLeaseTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		requeueExpired()
+	}
+}
+
+// requeueExpired requeues, or fails, the jobs whose lease expired.
+func requeueExpired() {
+	expired := []*workerLease{}
+	workQueue.Lock()
+	for id, l := range workQueue.leases {
+		if time.Now().After(l.expires) {
+			expired = append(expired, l)
+			delete(workQueue.leases, id)
+		}
+	}
+	workQueue.Unlock()
+
+	for _, l := range expired {
+		f := findFeed(l.task.JobID)
+		if f == nil {
+			continue
+		}
+		logger.Warn("worker lease expired", "job_id", l.task.JobID, "worker_id", l.worker, "attempt", l.task.Attempt)
+		switch {
+		case f.ctx.Err() != nil:
+			failRemoteJob(f, l.task.JobID, asJobError(f.ctx.Err()))
+		case l.task.Attempt >= maxJobAttempts:
+			failRemoteJob(f, l.task.JobID, newJobError(errCodeInternal, fmt.Errorf("%v workers stopped responding", l.task.Attempt)))
+		default:
+			f.publish(feedEventLog, message{JobID: l.task.JobID, LogLines: []logLine{{
+				Time:  time.Now(),
+				Level: logLevelWarn,
+				Text:  fmt.Sprintf("worker %v stopped responding, job requeued", l.worker),
+			}}})
+			pushTask(l.task, true)
+		}
+	}
+}
+
+// failRemoteJob finishes a job no worker is running anymore, f is nil when
+// the feed is gone.
+func failRemoteJob(f *jobFeed, jobID string, err error) {
+	j, loadErr := loadJob(jobID)
+	if loadErr != nil {
+		logger.Errorf("error loading job %v: %v", jobID, loadErr)
+		j = &job{ID: jobID, Type: jobTypeDetect}
+	}
+	err = j.finish(err)
+	if f != nil {
+		f.publish(feedEventError, errorMessage(jobID, err))
+		f.publish(feedEventDone, message{JobID: jobID})
+	}
+}
+
+// workerAuth checks the WORKER_TOKEN bearer token and that the request names
+// its worker. With JOB_DISPATCH=remote workers get sources and write job
+// dirs, so the token is required and nothing is let in without it.
+func workerAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorized := !remoteDispatch()
+		if token := os.Getenv("WORKER_TOKEN"); token != "" {
+			authorized = subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) == 1
+		}
+		if !authorized {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get(workerIDHeader) == "" {
+			http.Error(w, workerIDHeader+" is required", http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// registerWorkerRoutes adds the endpoints workers use to lease jobs and report
+// back, and starts requeueing the jobs of dead workers.
+func registerWorkerRoutes(ctx context.Context, router *mux.Router) {
+	s := router.PathPrefix("/workers").Subrouter()
+	s.Use(workerAuth)
+	s.HandleFunc("/lease", leaseHandler).Methods("POST")
+	s.HandleFunc("/jobs/{id}/heartbeat", heartbeatHandler).Methods("POST")
+	s.HandleFunc("/jobs/{id}/source", workerSourceHandler).Methods("GET")
+	s.HandleFunc("/jobs/{id}/events", workerEventsHandler).Methods("POST")
+	s.HandleFunc("/jobs/{id}/artifacts", workerArtifactsHandler).Methods("PUT")
+	s.HandleFunc("/jobs/{id}/complete", completeJobHandler).Methods("POST")
+	go reapLeases(ctx)
+}
+
+// leaseHandler gives the worker the next job, waiting up to maxLeaseWait for
+// one. It answers 204 when there was none.
+// POST /workers/lease
+func leaseHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), maxLeaseWait)
+	defer cancel()
+	task, ok := leaseTask(ctx, r.Header.Get(workerIDHeader))
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	logger.ctx(r.Context()).Info("job leased", "job_id", task.JobID, "worker_id", r.Header.Get(workerIDHeader), "attempt", task.Attempt)
+	writeJSON(w, http.StatusOK, task)
+}
+
+// heartbeatResponse tells the worker whether to stop the job.
+type heartbeatResponse struct {
+	Cancelled bool `json:"cancelled"`
+}
+
+// heartbeatHandler renews a lease, 409 means the worker lost the job.
+// POST /workers/jobs/{id}/heartbeat
+func heartbeatHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if err := renewLease(id, r.Header.Get(workerIDHeader)); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	resp := heartbeatResponse{}
+	if f := findFeed(id); f == nil || f.ctx.Err() != nil {
+		resp.Cancelled = true
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// workerSourceHandler serves the source file of a job to the worker running
+// it.
+// GET /workers/jobs/{id}/source
+func workerSourceHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if err := checkLease(id, r.Header.Get(workerIDHeader)); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	j, err := loadJob(id)
+	if err != nil || strings.Contains(j.Source, "://") {
+		http.Error(w, "source not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filepath.Base(j.Source)))
+	http.ServeFile(w, r, j.Source)
+}
+
+// workerEvents is a batch of feed events of a job run by a worker.
+type workerEvents struct {
+	Events []feedEvent `json:"events"`
+}
+
+// workerEventsHandler republishes the events of a job to the feed clients
+// watch. Done is published when the worker completes the job.
+// POST /workers/jobs/{id}/events
+func workerEventsHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if err := checkLease(id, r.Header.Get(workerIDHeader)); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	f := findFeed(id)
+	if f == nil {
+		http.Error(w, "job not found", http.StatusGone)
+		return
+	}
+	batch := workerEvents{}
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, e := range batch.Events {
+		if e.Type == feedEventQueued || e.Type == feedEventDone {
+			continue
+		}
+		e.Msg.JobID = id
+		f.publish(e.Type, e.Msg)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// workerArtifactsHandler unpacks a tar of the job dir of the worker into the
+// job dir here, so results are served like those of local jobs. Paths in
+// job.json are moved from the worker job dir, sent as X-Worker-Job-Dir.
+// PUT /workers/jobs/{id}/artifacts
+func workerArtifactsHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if err := checkLease(id, r.Header.Get(workerIDHeader)); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err := extractArtifacts(r.Body, jobDir(id)); err != nil {
+		logger.ctx(r.Context()).Errorf("error extracting artifacts of job %v: %v", id, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	j, err := loadJob(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if from := r.Header.Get(workerDirHeader); from != "" {
+		to, _ := filepath.Abs(j.dir())
+		j.OutputPath = rebasePath(j.OutputPath, from, to)
+		j.VideoPath = rebasePath(j.VideoPath, from, to)
+		j.SourcePath = rebasePath(j.SourcePath, from, to)
+		if err := j.save(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// extractArtifacts writes the regular files of a tar stream under dir. They
+// are unpacked next to it first and moved in place, so a dir the worker shares
+// with the API server isn't truncated while it's being read.
+func extractArtifacts(r io.Reader, dir string) error {
+	tmp := dir + ".upload-" + newJobID()
+	defer os.RemoveAll(tmp)
+	if err := untar(r, tmp); err != nil {
+		return err
+	}
+	return filepath.Walk(tmp, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(tmp, path)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return fmt.Errorf("error creating artifact dir: %v", err)
+		}
+		return os.Rename(path, dest)
+	})
+}
+
+// untar writes the regular files of a tar stream under dir.
+func untar(r io.Reader, dir string) error {
+	in := tar.NewReader(r)
+	for {
+		header, err := in.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading artifacts: %v", err)
+		}
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid artifact path %q", header.Name)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("error creating artifact dir: %v", err)
+		}
+		out, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("error creating artifact: %v", err)
+		}
+		_, err = io.Copy(out, in)
+		out.Close()
+		if err != nil {
+			return fmt.Errorf("error writing artifact: %v", err)
+		}
+	}
+}
+
+// rebasePath moves path from under dir from to dir to, paths elsewhere are
+// kept.
+func rebasePath(path, from, to string) string {
+	if path == "" {
+		return path
+	}
+	rel, err := filepath.Rel(from, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.Join(to, rel)
+}
+
+// workerCompletion ends a leased job. Failure is set when the job could not
+// start on the worker, the job is failed here since the worker has no job
+// file to upload.
+type workerCompletion struct {
+	Failure *jobError `json:"failure,omitempty"`
+}
+
+// completeJobHandler releases the lease of a job the worker finished, after
+// it posted the last events and artifacts, and ends its feed.
+// POST /workers/jobs/{id}/complete
+func completeJobHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	completion := workerCompletion{}
+	if err := json.NewDecoder(r.Body).Decode(&completion); err != nil && err != io.EOF {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := releaseLease(id, r.Header.Get(workerIDHeader)); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	f := findFeed(id)
+	if completion.Failure != nil {
+		failRemoteJob(f, id, completion.Failure)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if j, err := loadJob(id); err == nil {
+		logger.ctx(r.Context()).Info("job completed by worker", "job_id", id, "worker_id", r.Header.Get(workerIDHeader), "status", j.Status)
+	}
+	if f != nil {
+		f.publish(feedEventDone, message{JobID: id})
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
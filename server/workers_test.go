@@ -0,0 +1,244 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// newTestWorker serves the worker routes with an empty queue and returns a
+// client for them. Jobs are dispatched to workers.
+func newTestWorker(t *testing.T) *workerClient {
+	t.Helper()
+	chdirTemp(t)
+	streamTestModel(t)
+	t.Setenv("JOB_DISPATCH", dispatchRemote)
+	t.Setenv("WORKER_TOKEN", "secret")
+	workQueue.Lock()
+	workQueue.pending, workQueue.leases = nil, map[string]*workerLease{}
+	workQueue.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	router := mux.NewRouter()
+	registerWorkerRoutes(ctx, router)
+	server := httptest.NewServer(router)
+	t.Cleanup(func() {
+		server.Close()
+		cancel()
+	})
+	return &workerClient{
+		baseURL:  server.URL,
+		id:       "worker-1",
+		token:    "secret",
+		http:     &http.Client{Timeout: maxLeaseWait + 10*time.Second},
+		transfer: &http.Client{},
+	}
+}
+
+func submitRemoteJob(t *testing.T) *jobFeed {
+	t.Helper()
+	f, err := startDetectJob(context.Background(), detectRequest{Source: "rtsp://camera/stream", Model: "m"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cancelJob(f.JobID) })
+	return f
+}
+
+func leaseJob(t *testing.T, c *workerClient) workerTask {
+	t.Helper()
+	task := workerTask{}
+	status, err := c.do(context.Background(), http.MethodPost, "/workers/lease", nil, nil, &task)
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("lease: %v %v", status, err)
+	}
+	return task
+}
+
+// expireLease makes the lease of a job look abandoned.
+func expireLease(jobID string) {
+	workQueue.Lock()
+	defer workQueue.Unlock()
+	if l, ok := workQueue.leases[jobID]; ok {
+		l.expires = time.Now().Add(-time.Second)
+	}
+}
+
+func TestWorkerLeaseAndHeartbeat(t *testing.T) {
+	c := newTestWorker(t)
+	ctx := context.Background()
+	f := submitRemoteJob(t)
+
+	task := leaseJob(t, c)
+	if task.JobID != f.JobID || task.Attempt != 1 || task.LeaseSeconds != int(workerLeaseTTL.Seconds()) {
+		t.Errorf("task = %+v", task)
+	}
+	if task.Request.Source != "rtsp://camera/stream" || task.SourceURL != "" {
+		t.Errorf("remote source handed out as %q, %q", task.Request.Source, task.SourceURL)
+	}
+
+	base := "/workers/jobs/" + task.JobID
+	resp := heartbeatResponse{}
+	if err := c.postJSON(ctx, base+"/heartbeat", struct{}{}, &resp); err != nil || resp.Cancelled {
+		t.Fatalf("heartbeat: %+v, %v", resp, err)
+	}
+	other := *c
+	other.id = "worker-2"
+	if err := other.postJSON(ctx, base+"/heartbeat", struct{}{}, &resp); err != errLeaseLost {
+		t.Errorf("heartbeat of another worker: %v", err)
+	}
+	other.id, other.token = c.id, ""
+	if err := other.postJSON(ctx, base+"/heartbeat", struct{}{}, &resp); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("heartbeat without the token: %v", err)
+	}
+
+	cancelJob(task.JobID)
+	if err := c.postJSON(ctx, base+"/heartbeat", struct{}{}, &resp); err != nil || !resp.Cancelled {
+		t.Errorf("heartbeat of a cancelled job: %+v, %v", resp, err)
+	}
+	if err := c.postJSON(ctx, base+"/complete", workerCompletion{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !f.finished() {
+		t.Error("feed still open after complete")
+	}
+	if err := c.postJSON(ctx, base+"/heartbeat", struct{}{}, &resp); err != errLeaseLost {
+		t.Errorf("heartbeat after complete: %v", err)
+	}
+}
+
+func TestRemoteDispatchRequiresWorkerToken(t *testing.T) {
+	c := newTestWorker(t)
+	t.Setenv("WORKER_TOKEN", "")
+	if status, err := c.do(context.Background(), http.MethodPost, "/workers/lease", nil, nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("lease without WORKER_TOKEN: %v %v", status, err)
+	}
+}
+
+func TestPostEventsRetriesTransientErrors(t *testing.T) {
+	statuses := []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusNoContent}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statuses[min(calls, len(statuses)-1)])
+		calls++
+	}))
+	defer server.Close()
+	c := &workerClient{baseURL: server.URL, id: "worker-1", http: &http.Client{}}
+
+	if err := c.postEvents(context.Background(), "/workers/jobs/1", nil); err != nil || calls != 3 {
+		t.Fatalf("postEvents = %v after %v calls, want success on the third", err, calls)
+	}
+
+	// the lease is gone, retrying won't help
+	statuses, calls = []int{http.StatusConflict}, 0
+	if err := c.postEvents(context.Background(), "/workers/jobs/1", nil); err != errLeaseLost || calls != 1 {
+		t.Errorf("postEvents = %v after %v calls, want %v at once", err, calls, errLeaseLost)
+	}
+}
+
+func TestExpiredLeaseRequeuedUntilMaxAttempts(t *testing.T) {
+	c := newTestWorker(t)
+	f := submitRemoteJob(t)
+
+	for attempt := 1; attempt <= maxJobAttempts; attempt++ {
+		task := leaseJob(t, c)
+		if task.Attempt != attempt {
+			t.Fatalf("attempt = %v, want %v", task.Attempt, attempt)
+		}
+		expireLease(task.JobID)
+		requeueExpired()
+		if attempt < maxJobAttempts && f.finished() {
+			t.Fatalf("job finished after %v attempts", attempt)
+		}
+	}
+
+	if !f.finished() {
+		t.Fatal("job still running after the last worker stopped responding")
+	}
+	requeued := 0
+	past, _, cancel := f.subscribe(0)
+	cancel()
+	for _, e := range past {
+		for _, line := range e.Msg.LogLines {
+			if strings.Contains(line.Text, "job requeued") {
+				requeued++
+			}
+		}
+	}
+	if requeued != maxJobAttempts-1 {
+		t.Errorf("job requeued %v times, want %v", requeued, maxJobAttempts-1)
+	}
+	j, err := loadJob(f.JobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.Status != jobStatusFailed || j.Failure == nil || j.Failure.Code != errCodeInternal {
+		t.Errorf("job ended %v with %+v", j.Status, j.Failure)
+	}
+	workQueue.Lock()
+	defer workQueue.Unlock()
+	if len(workQueue.pending) != 0 {
+		t.Errorf("failed job still queued: %+v", workQueue.pending)
+	}
+}
+
+func TestUploadArtifactsOutlastsAPITimeout(t *testing.T) {
+	c := newTestWorker(t)
+	f := submitRemoteJob(t)
+	task := leaseJob(t, c)
+	j, err := loadJob(task.JobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(j.dir(), "output.mp4"), []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// API calls time out right away, uploads don't use that client
+	c.http = &http.Client{Timeout: time.Nanosecond}
+	if err := c.uploadArtifacts(context.Background(), "/workers/jobs/"+task.JobID, j, "", ""); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(jobDir(f.JobID), "output.mp4"))
+	if err != nil || string(data) != "video" {
+		t.Errorf("uploaded output = %q, %v", data, err)
+	}
+}
+
+func TestUntarRejectsPathTraversal(t *testing.T) {
+	archive := func(name string) *bytes.Buffer {
+		var buf bytes.Buffer
+		w := tar.NewWriter(&buf)
+		w.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 4, Typeflag: tar.TypeReg})
+		w.Write([]byte("data"))
+		w.Close()
+		return &buf
+	}
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "job")
+	for _, name := range []string{"../escape.txt", "labels/../../escape.txt", "/etc/escape.txt", ".."} {
+		if err := untar(archive(name), dir); err == nil {
+			t.Errorf("%q was accepted", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "escape.txt")); err == nil {
+		t.Error("a file was written outside the job dir")
+	}
+
+	if err := untar(archive("labels/frame_1.txt"), dir); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "labels", "frame_1.txt")); err != nil || string(data) != "data" {
+		t.Errorf("extracted %q, %v", data, err)
+	}
+}